@@ -0,0 +1,211 @@
+// Package anchor implements the dual-write pattern of consortium
+// applications: the detail of a record is sent in a private transaction and
+// the hash of its payload in a public anchoring transaction, so that
+// non-members can check the existence and time of the record without seeing
+// it. The two transactions cannot be sent atomically; the private one is sent
+// first, and the anchor only once it is mined successfully.
+package anchor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/bsostech/go-besu/client"
+	"github.com/bsostech/go-besu/retry"
+	"github.com/bsostech/go-besu/types"
+)
+
+// Prefix identifies the payload of anchoring transactions.
+var Prefix = []byte("besu-anchor/1:")
+
+// Link links a private transaction and its public anchor.
+type Link struct {
+	Hash       common.Hash // Keccak-256 hash of the private payload
+	PMTHash    common.Hash // privacy marker transaction of the private transaction
+	AnchorHash common.Hash // public anchoring transaction, zero until sent
+	Private    *types.PrivateReceipt
+	Anchor     *ethtypes.Receipt // nil until mined
+}
+
+// payload is the payload of an anchoring transaction.
+type payload struct {
+	Hash    common.Hash
+	PMTHash common.Hash
+}
+
+// PartialError is returned when the private transaction succeeded but its
+// anchor could not be written. Link holds the private receipt; the anchor can
+// be retried with Writer.Resume.
+type PartialError struct {
+	Link *Link
+	Err  error
+}
+
+func (e *PartialError) Error() string {
+	return fmt.Sprintf("private transaction %v is not anchored, err: %v", e.Link.PMTHash.Hex(), e.Err)
+}
+
+// Unwrap .
+func (e *PartialError) Unwrap() error {
+	return e.Err
+}
+
+// Writer writes private records with their public anchors.
+type Writer struct {
+	// To is the address anchors are sent to, the session account if nil.
+	To *common.Address
+	// Retry is the policy sending anchors is retried with.
+	Retry retry.Policy
+	// Compensate, if set, is called when the anchor of a successful private
+	// transaction cannot be written, e.g. to send a private tombstone
+	// revoking the record. Its error is reported with the PartialError.
+	Compensate func(ctx context.Context, link *Link, err error) error
+
+	session *client.Session
+}
+
+// NewWriter .
+func NewWriter(s *client.Session) *Writer {
+	return &Writer{
+		Retry:   retry.DefaultPolicy,
+		session: s,
+	}
+}
+
+// Write sends data to to in a private transaction of the session and, once it
+// is mined successfully, anchors the hash of data publicly. Nothing is
+// anchored if the private transaction fails. If the anchor fails, Compensate
+// is called and a *PartialError is returned.
+func (w *Writer) Write(ctx context.Context, to *common.Address, data []byte) (*Link, error) {
+	pmtHash, err := w.session.Send(ctx, to, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send private transaction, err: %v", err)
+	}
+	receipt, err := w.session.Client().WaitForReceipt(ctx, pmtHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write private transaction, err: %v", err)
+	}
+	link := &Link{
+		Hash:    crypto.Keccak256Hash(data),
+		PMTHash: pmtHash,
+		Private: receipt,
+	}
+	if receipt.Status != 1 {
+		return nil, client.ClassifyReceipt(link.PMTHash, receipt)
+	}
+	if err := w.anchor(ctx, link); err != nil {
+		if w.Compensate != nil {
+			if cerr := w.Compensate(ctx, link, err); cerr != nil {
+				err = fmt.Errorf("%v, compensation failed: %v", err, cerr)
+			}
+		}
+		return link, &PartialError{Link: link, Err: err}
+	}
+	return link, nil
+}
+
+// Resume writes the anchor of link, the Link of a PartialError, reusing the
+// anchoring transaction if one was sent.
+func (w *Writer) Resume(ctx context.Context, link *Link) error {
+	if link.Anchor != nil && link.Anchor.Status == ethtypes.ReceiptStatusSuccessful {
+		return nil
+	}
+	return w.anchor(ctx, link)
+}
+
+func (w *Writer) anchor(ctx context.Context, link *Link) error {
+	enc, err := rlp.EncodeToBytes(&payload{Hash: link.Hash, PMTHash: link.PMTHash})
+	if err != nil {
+		return err
+	}
+	data := append(common.CopyBytes(Prefix), enc...)
+	to := w.To
+	if to == nil {
+		account := w.session.Account()
+		to = &account
+	}
+	c := w.session.Client()
+	if link.AnchorHash != (common.Hash{}) {
+		receipt, err := c.WaitMined(ctx, link.AnchorHash)
+		if err != nil {
+			return err
+		}
+		link.Anchor = receipt
+		if receipt.Status == ethtypes.ReceiptStatusSuccessful {
+			return nil
+		}
+	}
+	err = w.Retry.Do(ctx, func() error {
+		hash, err := w.session.SendPublic(ctx, to, data)
+		if err != nil {
+			return err
+		}
+		link.AnchorHash = hash
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	receipt, err := c.WaitMined(ctx, link.AnchorHash)
+	if err != nil {
+		return err
+	}
+	link.Anchor = receipt
+	if receipt.Status != ethtypes.ReceiptStatusSuccessful {
+		return fmt.Errorf("anchoring transaction %v failed", link.AnchorHash.Hex())
+	}
+	return nil
+}
+
+// Verify checks that the public transaction anchorHash anchors the private
+// transaction pmtHash, which the node of c has to be a participant of. It
+// returns the link of the two.
+func Verify(ctx context.Context, c *client.Client, pmtHash, anchorHash common.Hash) (*Link, error) {
+	anchor, err := Read(ctx, c, anchorHash)
+	if err != nil {
+		return nil, err
+	}
+	if anchor.PMTHash != pmtHash {
+		return nil, fmt.Errorf("transaction %v does not anchor %v", anchorHash.Hex(), pmtHash.Hex())
+	}
+	tx, err := c.PrivateTransaction(ctx, pmtHash)
+	if err != nil {
+		return nil, err
+	}
+	if crypto.Keccak256Hash(tx.Data()) != anchor.Hash {
+		return nil, fmt.Errorf("payload of %v does not match anchor %v", pmtHash.Hex(), anchorHash.Hex())
+	}
+	private, err := c.PrivateReceipt(ctx, pmtHash)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := c.Eth().TransactionReceipt(ctx, anchorHash)
+	if err != nil {
+		return nil, err
+	}
+	anchor.Private, anchor.Anchor = private, receipt
+	return anchor, nil
+}
+
+// Read decodes the public anchoring transaction anchorHash, which anyone can
+// read. The returned link has no receipts.
+func Read(ctx context.Context, c *client.Client, anchorHash common.Hash) (*Link, error) {
+	tx, _, err := c.Eth().TransactionByHash(ctx, anchorHash)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(tx.Data(), Prefix) {
+		return nil, fmt.Errorf("transaction %v is not an anchor", anchorHash.Hex())
+	}
+	var p payload
+	if err := rlp.DecodeBytes(tx.Data()[len(Prefix):], &p); err != nil {
+		return nil, err
+	}
+	return &Link{Hash: p.Hash, PMTHash: p.PMTHash, AnchorHash: anchorHash}, nil
+}
@@ -0,0 +1,166 @@
+// Package artifacts loads the compiled contracts of Hardhat, Truffle and
+// Foundry for the private deployment helpers.
+package artifacts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Artifact is a compiled contract.
+type Artifact struct {
+	ContractName string
+	SourceName   string
+	ABI          abi.ABI
+	RawABI       json.RawMessage
+	// Bytecode and DeployedBytecode are hex encoded, with library placeholders
+	// left in until linked.
+	Bytecode         string
+	DeployedBytecode string
+	// LinkReferences locate the library addresses in Bytecode, by source file
+	// and library name.
+	LinkReferences map[string]map[string][]Offset
+}
+
+// Offset is the position of a library address in bytecode, in bytes.
+type Offset struct {
+	Start  int `json:"start"`
+	Length int `json:"length"`
+}
+
+// artifactJSON covers the formats of Hardhat and Truffle, where bytecode is a
+// string, and of Foundry, where it is an object.
+type artifactJSON struct {
+	ContractName     string                         `json:"contractName"`
+	SourceName       string                         `json:"sourceName"`
+	ABI              json.RawMessage                `json:"abi"`
+	Bytecode         json.RawMessage                `json:"bytecode"`
+	DeployedBytecode json.RawMessage                `json:"deployedBytecode"`
+	LinkReferences   map[string]map[string][]Offset `json:"linkReferences"`
+}
+
+type foundryBytecode struct {
+	Object         string                         `json:"object"`
+	LinkReferences map[string]map[string][]Offset `json:"linkReferences"`
+}
+
+// Load reads the artifact file at path.
+func Load(path string) (*Artifact, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	a, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %v, err: %v", path, err)
+	}
+	return a, nil
+}
+
+// Parse decodes an artifact of Hardhat, Truffle or Foundry.
+func Parse(data []byte) (*Artifact, error) {
+	var dec artifactJSON
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return nil, err
+	}
+	if len(dec.ABI) == 0 {
+		return nil, fmt.Errorf("abi not found")
+	}
+	contractABI, err := abi.JSON(bytes.NewReader(dec.ABI))
+	if err != nil {
+		return nil, err
+	}
+	a := &Artifact{
+		ContractName:   dec.ContractName,
+		SourceName:     dec.SourceName,
+		ABI:            contractABI,
+		RawABI:         dec.ABI,
+		LinkReferences: dec.LinkReferences,
+	}
+	var refs map[string]map[string][]Offset
+	if a.Bytecode, refs, err = decodeBytecode(dec.Bytecode); err != nil {
+		return nil, fmt.Errorf("invalid bytecode, err: %v", err)
+	}
+	if refs != nil {
+		a.LinkReferences = refs
+	}
+	if a.DeployedBytecode, _, err = decodeBytecode(dec.DeployedBytecode); err != nil {
+		return nil, fmt.Errorf("invalid deployedBytecode, err: %v", err)
+	}
+	return a, nil
+}
+
+// decodeBytecode decodes a bytecode string, or a Foundry bytecode object with its link references.
+func decodeBytecode(raw json.RawMessage) (string, map[string]map[string][]Offset, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return normalize(s), nil, nil
+	}
+	var obj foundryBytecode
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return "", nil, err
+	}
+	return normalize(obj.Object), obj.LinkReferences, nil
+}
+
+func normalize(code string) string {
+	if code == "" {
+		return ""
+	}
+	return "0x" + strings.TrimPrefix(code, "0x")
+}
+
+// NeedsLinking reports whether the bytecode has unlinked libraries.
+func (a *Artifact) NeedsLinking() bool {
+	return len(a.LinkReferences) > 0 || strings.Contains(a.Bytecode, "__")
+}
+
+// Libraries returns the names of the libraries to link, as "source:Library".
+func (a *Artifact) Libraries() []string {
+	var names []string
+	for source, libs := range a.LinkReferences {
+		for name := range libs {
+			names = append(names, source+":"+name)
+		}
+	}
+	return names
+}
+
+// Code returns the creation bytecode with libraries linked. Libraries are
+// keyed by library name or "source:Library". Bytecode without link references
+// is linked by its placeholders, see LinkBytecode.
+func (a *Artifact) Code(libraries map[string]common.Address) ([]byte, error) {
+	if len(a.LinkReferences) == 0 {
+		return LinkBytecode(a.Bytecode, libraries)
+	}
+	code := []byte(strings.TrimPrefix(a.Bytecode, "0x"))
+	for source, libs := range a.LinkReferences {
+		for name, offsets := range libs {
+			addr, ok := libraries[source+":"+name]
+			if !ok {
+				addr, ok = libraries[name]
+			}
+			if !ok {
+				return nil, fmt.Errorf("library %v:%v not found", source, name)
+			}
+			hexAddr := []byte(strings.TrimPrefix(strings.ToLower(addr.Hex()), "0x"))
+			for _, o := range offsets {
+				if o.Length != common.AddressLength || 2*(o.Start+o.Length) > len(code) {
+					return nil, fmt.Errorf("invalid link reference %v:%v at %v", source, name, o.Start)
+				}
+				copy(code[2*o.Start:], hexAddr)
+			}
+		}
+	}
+	return hexutil.Decode("0x" + string(code))
+}
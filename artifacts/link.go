@@ -0,0 +1,73 @@
+package artifacts
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// placeholderLength is the length of a library placeholder, that of a hex encoded address.
+const placeholderLength = 2 * common.AddressLength
+
+// LinkBytecode replaces the library placeholders of hex encoded bytecode with
+// the addresses of libraries, keyed by library name or "source:Library".
+// It handles the placeholders of solc before 0.5, "__Library_____", and
+// after, "__$hash$__", hash being the first 17 bytes of the keccak256 of the
+// fully qualified library name, so only "source:Library" keys match them.
+func LinkBytecode(bytecode string, libraries map[string]common.Address) ([]byte, error) {
+	code := strings.TrimPrefix(bytecode, "0x")
+	placeholders := make(map[string]string, 2*len(libraries))
+	for name, addr := range libraries {
+		hexAddr := strings.TrimPrefix(strings.ToLower(addr.Hex()), "0x")
+		placeholders[hashPlaceholder(name)] = hexAddr
+		placeholders[legacyPlaceholder(name)] = hexAddr
+		if i := strings.LastIndex(name, ":"); i >= 0 {
+			placeholders[legacyPlaceholder(name[i+1:])] = hexAddr
+		}
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(code, "__")
+		if i < 0 {
+			b.WriteString(code)
+			break
+		}
+		if i+placeholderLength > len(code) {
+			return nil, fmt.Errorf("invalid library placeholder at %v", i/2)
+		}
+		placeholder := code[i : i+placeholderLength]
+		addr, ok := placeholders[placeholder]
+		if !ok {
+			return nil, fmt.Errorf("library %v not found", placeholderName(placeholder))
+		}
+		b.WriteString(code[:i])
+		b.WriteString(addr)
+		code = code[i+placeholderLength:]
+	}
+	return hexutil.Decode("0x" + b.String())
+}
+
+// hashPlaceholder returns the placeholder of solc 0.5 and later.
+func hashPlaceholder(name string) string {
+	return "__$" + hexutil.Encode(crypto.Keccak256([]byte(name)))[2:36] + "$__"
+}
+
+// legacyPlaceholder returns the placeholder of solc before 0.5, the name
+// truncated or padded with underscores to the length of an address.
+func legacyPlaceholder(name string) string {
+	p := "__" + name
+	if len(p) > placeholderLength {
+		return p[:placeholderLength]
+	}
+	return p + strings.Repeat("_", placeholderLength-len(p))
+}
+
+func placeholderName(placeholder string) string {
+	if strings.HasPrefix(placeholder, "__$") {
+		return placeholder
+	}
+	return strings.Trim(placeholder, "_")
+}
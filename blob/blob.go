@@ -0,0 +1,162 @@
+// Package blob stores arbitrary bytes privately, such as documents or their
+// hashes shared within a privacy group, with a reference storage contract
+// so that no team has to write its own.
+//
+// The data lives in the payload of the storing private transaction, kept by
+// the enclaves of the group, while the contract records the Keccak-256 hash
+// of each blob with the block it was first stored in, and logs the hash and
+// the sender of every store. The contract has no ABI: calldata 0x00 || data
+// stores data and returns its hash, 0x01 || hash returns the block the hash
+// was first stored in, 0 if never.
+package blob
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bsostech/go-besu/client"
+)
+
+// Code is the creation code of the storage contract.
+const Code = "0x604d80600b6000396000f3361560165760003560f81c80600114601b57156028575b600080fd5b6001355460005260206000f35b600136038060016000376000208054603e574381555b3381600080a260005260206000f3"
+
+// Call prefixes of the storage contract.
+const (
+	opStore  = 0x00
+	opLookup = 0x01
+)
+
+// Ref references a stored blob.
+type Ref struct {
+	Contract       common.Address `json:"contract"`
+	PrivacyGroupID string         `json:"privacyGroupId"`
+	TxHash         common.Hash    `json:"txHash"` // privacy marker transaction of the store
+	Hash           common.Hash    `json:"hash"`   // Keccak-256 hash of the data
+}
+
+// Store is a binding of a storage contract in the private state of a privacy group.
+type Store struct {
+	session *client.Session
+	address common.Address
+	groupID string
+}
+
+// Deploy deploys a storage contract to the group of s and returns its binding.
+func Deploy(ctx context.Context, s *client.Session) (*Store, error) {
+	pmtHash, err := s.Send(ctx, nil, hexutil.MustDecode(Code))
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := s.Client().WaitForReceipt(ctx, pmtHash)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status != 1 {
+		return nil, fmt.Errorf("failed to deploy storage contract, err: %v", receipt.FailureReason())
+	}
+	return NewStore(s, receipt.ContractAddress)
+}
+
+// NewStore returns a binding of the storage contract at address in the group of s.
+func NewStore(s *client.Session, address common.Address) (*Store, error) {
+	group, err := s.Group()
+	if err != nil {
+		return nil, err
+	}
+	return &Store{
+		session: s,
+		address: address,
+		groupID: group.ID,
+	}, nil
+}
+
+// Address returns the address of the storage contract.
+func (st *Store) Address() common.Address {
+	return st.address
+}
+
+// StorePrivateBlob stores data and returns its reference once mined.
+func (st *Store) StorePrivateBlob(ctx context.Context, data []byte) (*Ref, error) {
+	pmtHash, err := st.session.Send(ctx, &st.address, append([]byte{opStore}, data...))
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := st.session.Client().WaitForReceipt(ctx, pmtHash)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status != 1 {
+		return nil, fmt.Errorf("failed to store blob, err: %v", receipt.FailureReason())
+	}
+	return &Ref{
+		Contract:       st.address,
+		PrivacyGroupID: st.groupID,
+		TxHash:         pmtHash,
+		Hash:           crypto.Keccak256Hash(data),
+	}, nil
+}
+
+// GetPrivateBlob returns the data of ref, checking it matches the hash.
+func (st *Store) GetPrivateBlob(ctx context.Context, ref *Ref) ([]byte, error) {
+	return GetPrivateBlob(ctx, st.session.Client(), ref)
+}
+
+// GetPrivateBlob returns the data of ref, read from its private transaction,
+// which the node has to be a participant of.
+func GetPrivateBlob(ctx context.Context, c *client.Client, ref *Ref) ([]byte, error) {
+	tx, err := c.PrivateTransaction(ctx, ref.TxHash)
+	if err != nil {
+		return nil, err
+	}
+	input := tx.Data()
+	if to := tx.To(); to == nil || *to != ref.Contract || len(input) == 0 || input[0] != opStore {
+		return nil, fmt.Errorf("transaction %v does not store a blob in %v", ref.TxHash.Hex(), ref.Contract.Hex())
+	}
+	data := input[1:]
+	if crypto.Keccak256Hash(data) != ref.Hash {
+		return nil, fmt.Errorf("blob of %v does not match hash %v", ref.TxHash.Hex(), ref.Hash.Hex())
+	}
+	return data, nil
+}
+
+// Recorded returns the block the blob with hash was first stored in, 0 if never.
+func (st *Store) Recorded(ctx context.Context, hash common.Hash) (uint64, error) {
+	output, err := st.session.Client().PrivateCallAt(ctx, st.groupID, ethereum.CallMsg{
+		From: st.session.Account(),
+		To:   &st.address,
+		Data: append([]byte{opLookup}, hash.Bytes()...),
+	}, nil)
+	if err != nil {
+		return 0, err
+	}
+	if len(output) != 32 {
+		return 0, fmt.Errorf("unexpected output %v", hexutil.Encode(output))
+	}
+	return new(big.Int).SetBytes(output).Uint64(), nil
+}
+
+// Find returns the reference of the first store of the blob with hash, nil if never stored.
+func (st *Store) Find(ctx context.Context, hash common.Hash) (*Ref, error) {
+	logs, err := st.session.Client().PrivateLogs(ctx, st.groupID, ethereum.FilterQuery{
+		Addresses: []common.Address{st.address},
+		Topics:    [][]common.Hash{{hash}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(logs) == 0 {
+		return nil, nil
+	}
+	return &Ref{
+		Contract:       st.address,
+		PrivacyGroupID: st.groupID,
+		TxHash:         logs[0].TxHash,
+		Hash:           hash,
+	}, nil
+}
@@ -0,0 +1,110 @@
+// Package cache provides the read-through caches of immutable chain data,
+// such as mined private receipts and transactions, and privacy groups.
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Cache stores values by key. Implementations must be safe for concurrent use.
+type Cache interface {
+	Get(key string) (value interface{}, ok bool)
+	Add(key string, value interface{})
+	Remove(key string)
+}
+
+// LRU is a Cache holding a bounded number of entries, evicting the least
+// recently used one when full.
+type LRU struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type entry struct {
+	key   string
+	value interface{}
+}
+
+// NewLRU returns an LRU holding at most size entries.
+func NewLRU(size int) *LRU {
+	if size < 1 {
+		size = 1
+	}
+	return &LRU{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// Get implements Cache.
+func (c *LRU) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*entry).value, true
+}
+
+// Add implements Cache.
+func (c *LRU) Add(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*entry).value = value
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&entry{key: key, value: value})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*entry).key)
+	}
+}
+
+// Remove implements Cache.
+func (c *LRU) Remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		c.order.Remove(e)
+		delete(c.entries, key)
+	}
+}
+
+// Len returns the number of entries.
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Ranger is implemented by caches which can list their entries.
+type Ranger interface {
+	// Range calls fn for each entry until it returns false.
+	Range(fn func(key string, value interface{}) bool)
+}
+
+// Range implements Ranger, from the least to the most recently used entry,
+// without changing the order.
+func (c *LRU) Range(fn func(key string, value interface{}) bool) {
+	c.mu.Lock()
+	// copies, as Add updates entries in place
+	entries := make([]entry, 0, c.order.Len())
+	for e := c.order.Back(); e != nil; e = e.Prev() {
+		entries = append(entries, *e.Value.(*entry))
+	}
+	c.mu.Unlock()
+	for _, e := range entries {
+		if !fn(e.key, e.value) {
+			return
+		}
+	}
+}
@@ -0,0 +1,161 @@
+// Package canary periodically sends a trivial private transaction to a
+// dedicated privacy group, monitoring the whole privacy pipeline: signing,
+// distribution, marker inclusion and private receipt availability.
+package canary
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bsostech/go-besu/client"
+	"github.com/bsostech/go-besu/privacy"
+	"github.com/bsostech/go-besu/types"
+)
+
+// Default settings.
+const (
+	DefaultInterval = time.Minute
+	DefaultTimeout  = 30 * time.Second
+)
+
+// Result is the outcome of a canary transaction.
+type Result struct {
+	Time    time.Time
+	Hash    common.Hash // privacy marker transaction, zero if sending failed
+	Latency time.Duration
+	Err     error
+}
+
+// Canary sends a private transaction with no payload from its account to
+// itself every Interval. The group should be dedicated to the canary, e.g. a
+// self-only group, to keep its nonces apart.
+type Canary struct {
+	Profile  types.NetworkProfile
+	Interval time.Duration // DefaultInterval if 0
+	Timeout  time.Duration // per transaction, DefaultTimeout if 0
+	Handler  func(Result)
+
+	client  *client.Client
+	key     *ecdsa.PrivateKey
+	account common.Address
+	chainID *big.Int
+	self    privacy.PublicKey
+	group   *privacy.Group
+
+	mu        sync.Mutex
+	last      Result
+	successes uint64
+	failures  uint64
+}
+
+// New returns a canary sending with key from self to group, with the defaults of types.FreeGasNetwork.
+func New(c *client.Client, key *ecdsa.PrivateKey, chainID *big.Int, self privacy.PublicKey, group *privacy.Group) *Canary {
+	return &Canary{
+		Profile: types.FreeGasNetwork,
+		client:  c,
+		key:     key,
+		account: crypto.PubkeyToAddress(key.PublicKey),
+		chainID: chainID,
+		self:    self,
+		group:   group,
+	}
+}
+
+// Run probes every Interval until ctx is done.
+func (c *Canary) Run(ctx context.Context) error {
+	interval := c.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ticker := c.client.Clock().NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		c.Probe(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+		}
+	}
+}
+
+// Probe sends a canary transaction and waits for its private receipt.
+func (c *Canary) Probe(ctx context.Context) Result {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	clk := c.client.Clock()
+	r := Result{Time: clk.Now()}
+	r.Hash, r.Err = c.send(ctx)
+	r.Latency = clk.Now().Sub(r.Time)
+	c.mu.Lock()
+	c.last = r
+	if r.Err == nil {
+		c.successes++
+	} else {
+		c.failures++
+	}
+	c.mu.Unlock()
+	if c.Handler != nil {
+		c.Handler(r)
+	}
+	return r
+}
+
+func (c *Canary) send(ctx context.Context) (common.Hash, error) {
+	groupID, err := base64.StdEncoding.DecodeString(c.group.ID)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	nonce, err := c.client.NextNonce(c.account, c.group)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	to := c.account
+	tx := c.Profile.NewPrivateTransaction(nonce, &to, nil, nil, c.self, groupID)
+	signed, err := tx.SignTx(c.chainID, c.key)
+	if err != nil {
+		c.client.ResetNonce(c.account, c.group)
+		return common.Hash{}, err
+	}
+	pmtHash, err := c.client.SendTransaction(ctx, signed)
+	if err != nil {
+		c.client.ResetNonce(c.account, c.group)
+		return common.Hash{}, err
+	}
+	receipt, err := c.client.WaitForReceipt(ctx, pmtHash)
+	if err != nil {
+		// the marker may still be mined, the nonce is reloaded from the node
+		c.client.ResetNonce(c.account, c.group)
+		return pmtHash, err
+	}
+	if receipt.Status != 1 {
+		return pmtHash, fmt.Errorf("canary failed: %v", receipt.FailureReason())
+	}
+	return pmtHash, nil
+}
+
+// Last returns the result of the latest probe.
+func (c *Canary) Last() Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.last
+}
+
+// Counts returns the numbers of successful and failed probes.
+func (c *Canary) Counts() (successes, failures uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.successes, c.failures
+}
@@ -0,0 +1,94 @@
+package capture
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type captureKey struct{}
+
+// Exchange is a raw JSON-RPC request and its response, with sensitive fields redacted.
+type Exchange struct {
+	Request  string `json:"request"`
+	Status   string `json:"status,omitempty"`
+	Response string `json:"response,omitempty"`
+	Err      string `json:"error,omitempty"`
+}
+
+// Capture records the exchanges of the calls made with its context through a Transport.
+type Capture struct {
+	mu        sync.Mutex
+	exchanges []*Exchange
+}
+
+// Error is a failed call with the exchanges it made.
+type Error struct {
+	Err       error
+	Exchanges []*Exchange
+}
+
+// WithCapture returns a context recording the exchanges of the calls made with it.
+func WithCapture(ctx context.Context) (context.Context, *Capture) {
+	c := &Capture{}
+	return context.WithValue(ctx, captureKey{}, c), c
+}
+
+// FromContext returns the Capture of ctx, if any.
+func FromContext(ctx context.Context) (*Capture, bool) {
+	c, ok := ctx.Value(captureKey{}).(*Capture)
+	return c, ok
+}
+
+// Exchanges returns the recorded exchanges.
+func (c *Capture) Exchanges() []*Exchange {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*Exchange(nil), c.exchanges...)
+}
+
+func (c *Capture) add(e *Exchange) {
+	c.mu.Lock()
+	c.exchanges = append(c.exchanges, e)
+	c.mu.Unlock()
+}
+
+// Wrap returns err as an *Error carrying the exchanges of c, or err as is if c is nil.
+func Wrap(err error, c *Capture) error {
+	if err == nil || c == nil {
+		return err
+	}
+	return &Error{
+		Err:       err,
+		Exchanges: c.Exchanges(),
+	}
+}
+
+// Call calls method with client, capturing the raw exchanges. On failure the
+// returned error is an *Error. client has to use a Transport.
+func Call(ctx context.Context, client *rpc.Client, result interface{}, method string, args ...interface{}) error {
+	ctx, c := WithCapture(ctx)
+	return Wrap(client.CallContext(ctx, result, method, args...), c)
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Err.Error())
+	for _, x := range e.Exchanges {
+		fmt.Fprintf(&b, "\n--> %v", x.Request)
+		if x.Err != "" {
+			fmt.Fprintf(&b, "\n<-- error: %v", x.Err)
+		} else {
+			fmt.Fprintf(&b, "\n<-- %v %v", x.Status, x.Response)
+		}
+	}
+	return b.String()
+}
+
+// Unwrap .
+func (e *Error) Unwrap() error {
+	return e.Err
+}
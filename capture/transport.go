@@ -0,0 +1,118 @@
+package capture
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+)
+
+// Redacted replaces the value of redacted fields.
+const Redacted = "[redacted]"
+
+// DefaultRedactedFields are the fields holding private payloads.
+var DefaultRedactedFields = []string{"input", "output", "data", "payload", "revertReason"}
+
+// DefaultRedactedMethods are the methods whose params are redacted entirely,
+// e.g. because they hold signed raw transactions.
+var DefaultRedactedMethods = []string{"eea_sendRawTransaction", "eth_sendRawTransaction", "personal_unlockAccount", "personal_sign"}
+
+// Transport is an http.RoundTripper recording the request and response bodies
+// of calls whose context carries a Capture. Use it with rpc.DialHTTPWithClient.
+type Transport struct {
+	Base            http.RoundTripper // http.DefaultTransport if nil
+	RedactedFields  []string          // DefaultRedactedFields if nil
+	RedactedMethods []string          // DefaultRedactedMethods if nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	c, ok := FromContext(req.Context())
+	if !ok {
+		return base.RoundTrip(req)
+	}
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		if reqBody, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	}
+	exchange := &Exchange{Request: t.redact(reqBody)}
+	defer c.add(exchange)
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		exchange.Err = err.Error()
+		return nil, err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		exchange.Err = err.Error()
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+	exchange.Status = resp.Status
+	exchange.Response = t.redact(respBody)
+	return resp, nil
+}
+
+func (t *Transport) redact(body []byte) string {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return string(body)
+	}
+	fields, methods := t.RedactedFields, t.RedactedMethods
+	if fields == nil {
+		fields = DefaultRedactedFields
+	}
+	if methods == nil {
+		methods = DefaultRedactedMethods
+	}
+	v = redactValue(v, toSet(fields), toSet(methods))
+	out, err := json.Marshal(v)
+	if err != nil {
+		return string(body)
+	}
+	return string(out)
+}
+
+func redactValue(v interface{}, fields, methods map[string]bool) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if method, ok := v["method"].(string); ok && methods[method] {
+			if _, ok := v["params"]; ok {
+				v["params"] = Redacted
+			}
+		}
+		for key := range v {
+			if fields[key] {
+				v[key] = Redacted
+			} else {
+				v[key] = redactValue(v[key], fields, methods)
+			}
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = redactValue(v[i], fields, methods)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func toSet(keys []string) map[string]bool {
+	set := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		set[k] = true
+	}
+	return set
+}
@@ -0,0 +1,165 @@
+// Package channel wraps the common case of a privacy group of two parties:
+// the sending node and a counterparty.
+package channel
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bsostech/go-besu/client"
+	"github.com/bsostech/go-besu/config"
+	"github.com/bsostech/go-besu/privacy"
+	"github.com/bsostech/go-besu/types"
+)
+
+// Channel is a privacy group of self and a counterparty, sent to by one account.
+type Channel struct {
+	Profile types.NetworkProfile
+
+	client       *client.Client
+	key          *ecdsa.PrivateKey
+	account      common.Address
+	chainID      *big.Int
+	self         privacy.PublicKey
+	counterparty privacy.PublicKey
+	group        *privacy.Group
+}
+
+// Open returns the channel of self and counterparty, creating its privacy
+// group named name if none exists, with the defaults of types.FreeGasNetwork.
+func Open(c *client.Client, key *ecdsa.PrivateKey, chainID *big.Int, self, counterparty privacy.PublicKey, name string) (*Channel, error) {
+	if self.ToString() == counterparty.ToString() {
+		return nil, fmt.Errorf("counterparty is self")
+	}
+	group, _, err := c.CreateIfNotExists([]*privacy.PublicKey{&self, &counterparty}, name, "")
+	if err != nil {
+		return nil, err
+	}
+	return &Channel{
+		Profile:      types.FreeGasNetwork,
+		client:       c,
+		key:          key,
+		account:      crypto.PubkeyToAddress(key.PublicKey),
+		chainID:      chainID,
+		self:         self,
+		counterparty: counterparty,
+		group:        group,
+	}, nil
+}
+
+// FromConfig opens the channel of the group named name in cfg, which must
+// have two members, one of them the enclave key of cfg.
+func FromConfig(cfg *config.Config, c *client.Client, name string) (*Channel, error) {
+	self, err := cfg.PrivateFrom()
+	if err != nil {
+		return nil, err
+	}
+	counterparty, err := Counterparty(cfg, name)
+	if err != nil {
+		return nil, err
+	}
+	key, err := cfg.Key()
+	if err != nil {
+		return nil, err
+	}
+	profile, err := cfg.NetworkProfile()
+	if err != nil {
+		return nil, err
+	}
+	ch, err := Open(c, key, cfg.ChainIDBig(), self, counterparty, name)
+	if err != nil {
+		return nil, err
+	}
+	ch.Profile = profile
+	return ch, nil
+}
+
+// Counterparty returns the member of the group named name in cfg other than its enclave key.
+func Counterparty(cfg *config.Config, name string) (privacy.PublicKey, error) {
+	self, err := cfg.PrivateFrom()
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range cfg.Groups {
+		if g.Name != name {
+			continue
+		}
+		members, err := privacy.ParseParticipantSet(g.Members...)
+		if err != nil {
+			return nil, fmt.Errorf("invalid members of group %v, err: %v", name, err)
+		}
+		if members.Len() != 2 || !members.Contains(self) {
+			return nil, fmt.Errorf("group %v is not a channel of %v", name, self.ToString())
+		}
+		for _, m := range members.Keys() {
+			if m.ToString() != self.ToString() {
+				return *m, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("group %v not found", name)
+}
+
+// Group returns the privacy group of the channel.
+func (ch *Channel) Group() *privacy.Group {
+	return ch.group
+}
+
+// Counterparty returns the enclave key of the other party.
+func (ch *Channel) Counterparty() privacy.PublicKey {
+	return ch.counterparty
+}
+
+// Send sends a private transaction to to, a contract creation if nil, and
+// waits for its private receipt.
+func (ch *Channel) Send(ctx context.Context, to *common.Address, data []byte) (*types.PrivateReceipt, error) {
+	groupID, err := base64.StdEncoding.DecodeString(ch.group.ID)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := ch.client.NextNonce(ch.account, ch.group)
+	if err != nil {
+		return nil, err
+	}
+	tx := ch.Profile.NewPrivateTransaction(nonce, to, nil, data, ch.self, groupID)
+	signed, err := tx.SignTx(ch.chainID, ch.key)
+	if err != nil {
+		ch.client.ResetNonce(ch.account, ch.group)
+		return nil, err
+	}
+	pmtHash, err := ch.client.SendTransaction(ctx, signed)
+	if err != nil {
+		ch.client.ResetNonce(ch.account, ch.group)
+		return nil, err
+	}
+	return ch.client.WaitForReceipt(ctx, pmtHash)
+}
+
+// Deploy deploys code and returns the address of the contract.
+func (ch *Channel) Deploy(ctx context.Context, code []byte) (common.Address, error) {
+	receipt, err := ch.Send(ctx, nil, code)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if receipt.Status != 1 {
+		return common.Address{}, fmt.Errorf("deployment failed: %v", receipt.FailureReason())
+	}
+	return receipt.ContractAddress, nil
+}
+
+// Call executes a call of to against the latest private state of the channel.
+func (ch *Channel) Call(ctx context.Context, to common.Address, data []byte) ([]byte, error) {
+	return ch.client.PrivateCallAt(ctx, ch.group.ID, ethereum.CallMsg{From: ch.account, To: &to, Data: data}, nil)
+}
+
+// Watch subscribes to the private logs of the channel matching q.
+func (ch *Channel) Watch(ctx context.Context, q ethereum.FilterQuery) (*client.LogSubscription, error) {
+	return ch.client.SubscribePrivateLogs(ctx, ch.group.ID, q, client.DefaultSubscriptionOptions)
+}
@@ -0,0 +1,165 @@
+// Package chunk writes payloads too large for one private transaction, as
+// enclaves limit the payload size, across several transactions and a
+// manifest transaction, and reassembles them. It suits data notarization,
+// where the data only has to be recorded in the private transactions: the
+// transactions are sent to the sender's own address, running no code.
+package chunk
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/bsostech/go-besu/client"
+)
+
+// DefaultChunkSize is the payload size per transaction, below the default
+// limits of Tessera and Besu.
+const DefaultChunkSize = 64 << 10
+
+// Payload prefixes identifying chunks and manifests.
+var (
+	ChunkPrefix    = []byte("besu-chunk/1:")
+	ManifestPrefix = []byte("besu-manifest/1:")
+)
+
+// Manifest lists the chunks of a payload.
+type Manifest struct {
+	Hash   common.Hash   // Keccak-256 hash of the payload
+	Size   uint64        // size of the payload
+	Chunks []common.Hash // privacy marker transactions of the chunks, in order
+}
+
+// chunk is the payload of a chunk transaction.
+type chunk struct {
+	Hash  common.Hash // of the whole payload
+	Index uint64
+	Data  []byte
+}
+
+// Writer writes payloads with a session.
+type Writer struct {
+	ChunkSize int // DefaultChunkSize if 0
+
+	session *client.Session
+}
+
+// NewWriter .
+func NewWriter(s *client.Session) *Writer {
+	return &Writer{
+		ChunkSize: DefaultChunkSize,
+		session:   s,
+	}
+}
+
+// Write sends data in chunks, then the manifest once all chunks are mined
+// successfully, and returns the privacy marker transaction of the manifest,
+// which identifies the payload for Read.
+func (w *Writer) Write(ctx context.Context, data []byte) (common.Hash, *Manifest, error) {
+	size := w.ChunkSize
+	if size <= 0 {
+		size = DefaultChunkSize
+	}
+	manifest := &Manifest{
+		Hash: crypto.Keccak256Hash(data),
+		Size: uint64(len(data)),
+	}
+	to := w.session.Account()
+	for i := 0; i*size < len(data) || i == 0; i++ {
+		end := (i + 1) * size
+		if end > len(data) {
+			end = len(data)
+		}
+		payload, err := encode(ChunkPrefix, &chunk{Hash: manifest.Hash, Index: uint64(i), Data: data[i*size : end]})
+		if err != nil {
+			return common.Hash{}, nil, err
+		}
+		pmtHash, err := w.session.Send(ctx, &to, payload)
+		if err != nil {
+			return common.Hash{}, nil, fmt.Errorf("failed to send chunk %v, err: %v", i, err)
+		}
+		manifest.Chunks = append(manifest.Chunks, pmtHash)
+	}
+	c := w.session.Client()
+	for i, pmtHash := range manifest.Chunks {
+		receipt, err := c.WaitForReceipt(ctx, pmtHash)
+		if err != nil {
+			return common.Hash{}, nil, fmt.Errorf("failed to write chunk %v, err: %v", i, err)
+		}
+		if receipt.Status != 1 {
+			return common.Hash{}, nil, fmt.Errorf("failed to write chunk %v, err: %v", i, receipt.FailureReason())
+		}
+	}
+	payload, err := encode(ManifestPrefix, manifest)
+	if err != nil {
+		return common.Hash{}, nil, err
+	}
+	manifestHash, err := w.session.Send(ctx, &to, payload)
+	if err == nil {
+		_, err = c.WaitForReceipt(ctx, manifestHash)
+	}
+	if err != nil {
+		return common.Hash{}, nil, fmt.Errorf("failed to write manifest, err: %v", err)
+	}
+	return manifestHash, manifest, nil
+}
+
+// ReadManifest returns the manifest sent in the privacy marker transaction manifestHash.
+func ReadManifest(ctx context.Context, c *client.Client, manifestHash common.Hash) (*Manifest, error) {
+	tx, err := c.PrivateTransaction(ctx, manifestHash)
+	if err != nil {
+		return nil, err
+	}
+	manifest := new(Manifest)
+	if err := decode(ManifestPrefix, tx.Data(), manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// Read reassembles the payload of the manifest sent in the privacy marker
+// transaction manifestHash, checking its hash.
+func Read(ctx context.Context, c *client.Client, manifestHash common.Hash) ([]byte, error) {
+	manifest, err := ReadManifest(ctx, c, manifestHash)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, 0, manifest.Size)
+	for i, pmtHash := range manifest.Chunks {
+		tx, err := c.PrivateTransaction(ctx, pmtHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read chunk %v, err: %v", i, err)
+		}
+		var ch chunk
+		if err := decode(ChunkPrefix, tx.Data(), &ch); err != nil {
+			return nil, fmt.Errorf("failed to read chunk %v, err: %v", i, err)
+		}
+		if ch.Hash != manifest.Hash || ch.Index != uint64(i) {
+			return nil, fmt.Errorf("chunk %v does not belong to payload %v", i, manifest.Hash.Hex())
+		}
+		data = append(data, ch.Data...)
+	}
+	if uint64(len(data)) != manifest.Size || crypto.Keccak256Hash(data) != manifest.Hash {
+		return nil, fmt.Errorf("payload does not match hash %v", manifest.Hash.Hex())
+	}
+	return data, nil
+}
+
+func encode(prefix []byte, v interface{}) ([]byte, error) {
+	enc, err := rlp.EncodeToBytes(v)
+	if err != nil {
+		return nil, err
+	}
+	return append(common.CopyBytes(prefix), enc...), nil
+}
+
+func decode(prefix, payload []byte, v interface{}) error {
+	if !bytes.HasPrefix(payload, prefix) {
+		return fmt.Errorf("payload is not a %s", bytes.TrimSuffix(prefix, []byte(":")))
+	}
+	return rlp.DecodeBytes(payload[len(prefix):], v)
+}
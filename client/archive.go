@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ErrStatePruned means the node pruned the private state of the requested
+// block. Errors of historical reads match it with errors.Is, and are
+// *StatePrunedError with the earliest available block.
+var ErrStatePruned = errors.New("private state pruned")
+
+// StatePrunedError is returned by historical reads of pruned private state.
+type StatePrunedError struct {
+	Block    uint64
+	Earliest uint64 // earliest block with private state, 0 if unknown
+	Err      error  // the error of the node
+}
+
+func (e *StatePrunedError) Error() string {
+	if e.Earliest == 0 {
+		return fmt.Sprintf("private state of block %v pruned", e.Block)
+	}
+	return fmt.Sprintf("private state of block %v pruned, earliest available block is %v", e.Block, e.Earliest)
+}
+
+// Is matches ErrStatePruned.
+func (e *StatePrunedError) Is(target error) bool {
+	return target == ErrStatePruned
+}
+
+// Unwrap .
+func (e *StatePrunedError) Unwrap() error {
+	return e.Err
+}
+
+// prunedMessages are parts of the errors Besu returns for unavailable world state.
+var prunedMessages = []string{"world state unavailable", "missing world state", "missing trie node", "pruned"}
+
+func isPruned(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, m := range prunedMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// PrivateCodeAt returns the code of a contract in the private state of a
+// privacy group at block, the latest if nil, with priv_getCode.
+func (c *Client) PrivateCodeAt(ctx context.Context, privacyGroupID string, account common.Address, block *big.Int) ([]byte, error) {
+	var code hexutil.Bytes
+	err := c.rpc.CallContext(ctx, &code, "priv_getCode", privacyGroupID, account, toBlockNumArg(block, "latest"))
+	if err != nil {
+		return nil, c.historicalError(ctx, privacyGroupID, block, err)
+	}
+	return code, nil
+}
+
+// PrivateCallAt executes msg against the private state of a privacy group at
+// block, the latest if nil, with priv_call.
+func (c *Client) PrivateCallAt(ctx context.Context, privacyGroupID string, msg ethereum.CallMsg, block *big.Int) ([]byte, error) {
+	arg := map[string]interface{}{
+		"from": msg.From,
+		"to":   msg.To,
+	}
+	if len(msg.Data) > 0 {
+		arg["data"] = hexutil.Bytes(msg.Data)
+	}
+	if msg.Gas != 0 {
+		arg["gas"] = hexutil.Uint64(msg.Gas)
+	}
+	if msg.Value != nil {
+		arg["value"] = (*hexutil.Big)(msg.Value)
+	}
+	var output hexutil.Bytes
+	err := c.rpc.CallContext(ctx, &output, "priv_call", privacyGroupID, arg, toBlockNumArg(block, "latest"))
+	if err != nil {
+		return nil, c.historicalError(ctx, privacyGroupID, block, err)
+	}
+	return output, nil
+}
+
+// EarliestPrivateState returns the earliest block whose private state the
+// node still has, searching between from and the latest block.
+func (c *Client) EarliestPrivateState(ctx context.Context, privacyGroupID string, from uint64) (uint64, error) {
+	header, err := c.eth.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	lo, hi := from, header.Number.Uint64()
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		available, err := c.hasPrivateState(ctx, privacyGroupID, mid)
+		if err != nil {
+			return 0, err
+		}
+		if available {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo, nil
+}
+
+func (c *Client) hasPrivateState(ctx context.Context, privacyGroupID string, block uint64) (bool, error) {
+	var code hexutil.Bytes
+	err := c.rpc.CallContext(ctx, &code, "priv_getCode", privacyGroupID, common.Address{}, hexutil.EncodeUint64(block))
+	if err == nil {
+		return true, nil
+	}
+	if isPruned(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+// historicalError converts err of a read at block into a *StatePrunedError
+// if the state was pruned.
+func (c *Client) historicalError(ctx context.Context, privacyGroupID string, block *big.Int, err error) error {
+	if block == nil || !block.IsUint64() || !isPruned(err) {
+		return err
+	}
+	pruned := &StatePrunedError{Block: block.Uint64(), Err: err}
+	if earliest, err := c.EarliestPrivateState(ctx, privacyGroupID, pruned.Block); err == nil {
+		pruned.Earliest = earliest
+	}
+	return pruned
+}
@@ -0,0 +1,255 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/bsostech/go-besu/types"
+)
+
+// Poller limits of SendAsync.
+const (
+	// maxPollFailures is the number of consecutive failed polls after which
+	// handles fail with the error of the last poll.
+	maxPollFailures = 5
+	// droppedPolls is the number of consecutive polls not finding the privacy
+	// marker transaction after which it is considered dropped.
+	droppedPolls = 5
+	// pollTimeout bounds a batch of polls.
+	pollTimeout = 30 * time.Second
+)
+
+// TxHandle is the pending result of SendAsync.
+type TxHandle struct {
+	ctx     context.Context
+	mu      sync.Mutex // guards hash, set while Drain may report the handle
+	hash    common.Hash
+	done    chan struct{}
+	receipt *types.PrivateReceipt
+	err     error
+	untrack func() // unregisters the handle from Drain
+
+	// counters of the poller goroutine
+	failures int
+	missing  int
+}
+
+// poller polls the receipts of all pending handles of a client in batches,
+// from a single goroutine running while handles are pending.
+type poller struct {
+	mu       sync.Mutex
+	pending  map[common.Hash]*TxHandle
+	running  bool
+	failures int
+}
+
+// SendAsync sends tx and returns a handle resolving to its private receipt.
+// Receipts of all pending handles are polled together, no goroutine is
+// blocked per transaction. The handle fails with ctx.Err() if ctx is done
+// first, with ErrNotParticipant if the marker is mined without a receipt, with
+// ErrPMTNotFound if the marker is dropped, and with the polling error if
+// polling keeps failing.
+func (c *Client) SendAsync(ctx context.Context, tx *types.PrivateTransaction) *TxHandle {
+	h := &TxHandle{
+		ctx:  ctx,
+		done: make(chan struct{}),
+	}
+	if !c.drainer.track(h) {
+		h.resolve(nil, ErrDraining)
+		return h
+	}
+	pmtHash, err := c.SendTransaction(ctx, tx)
+	if err != nil {
+		h.resolve(nil, err)
+		return h
+	}
+	h.setHash(pmtHash)
+	c.poller.add(c, h)
+	return h
+}
+
+// Hash returns the hash of the privacy marker transaction, zero if sending failed.
+func (h *TxHandle) Hash() common.Hash {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.hash
+}
+
+func (h *TxHandle) setHash(hash common.Hash) {
+	h.mu.Lock()
+	h.hash = hash
+	h.mu.Unlock()
+}
+
+// Done is closed once the receipt is available or sending failed.
+func (h *TxHandle) Done() <-chan struct{} {
+	return h.done
+}
+
+// Receipt waits for the private receipt until ctx is done.
+func (h *TxHandle) Receipt(ctx context.Context) (*types.PrivateReceipt, error) {
+	select {
+	case <-h.done:
+		return h.receipt, h.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Err returns the error of sending or of fetching the receipt, nil until Done.
+func (h *TxHandle) Err() error {
+	select {
+	case <-h.done:
+		return h.err
+	default:
+		return nil
+	}
+}
+
+func (h *TxHandle) resolve(receipt *types.PrivateReceipt, err error) {
+	h.receipt, h.err = receipt, err
+	close(h.done)
+	if h.untrack != nil {
+		h.untrack()
+	}
+}
+
+func (p *poller) add(c *Client, h *TxHandle) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.pending == nil {
+		p.pending = make(map[common.Hash]*TxHandle)
+	}
+	p.pending[h.hash] = h
+	if !p.running {
+		p.running = true
+		go p.loop(c)
+	}
+}
+
+func (p *poller) loop(c *Client) {
+	ticker := c.clock.NewTicker(DefaultPollInterval)
+	defer ticker.Stop()
+	for range ticker.C() {
+		p.mu.Lock()
+		handles := make([]*TxHandle, 0, len(p.pending))
+		for _, h := range p.pending {
+			handles = append(handles, h)
+		}
+		p.mu.Unlock()
+		resolved := p.poll(c, handles)
+		p.mu.Lock()
+		for _, h := range resolved {
+			delete(p.pending, h.hash)
+		}
+		if len(p.pending) == 0 {
+			p.running = false
+			p.mu.Unlock()
+			return
+		}
+		p.mu.Unlock()
+	}
+}
+
+// poll fetches the receipts of handles in one batch and resolves those which
+// are available, failed, or whose context is done.
+func (p *poller) poll(c *Client, handles []*TxHandle) []*TxHandle {
+	var resolved []*TxHandle
+	live := handles[:0:0]
+	for _, h := range handles {
+		if err := h.ctx.Err(); err != nil {
+			h.resolve(nil, err)
+			resolved = append(resolved, h)
+			continue
+		}
+		live = append(live, h)
+	}
+	if len(live) == 0 {
+		return resolved
+	}
+	// the marker is looked up before the receipt, so that a marker mined in
+	// between is not mistaken for one without a receipt
+	txs := make([]*markerTx, len(live))
+	rsps := make([]map[string]interface{}, len(live))
+	batch := make([]rpc.BatchElem, 0, 2*len(live))
+	for i, h := range live {
+		batch = append(batch, rpc.BatchElem{
+			Method: "eth_getTransactionByHash",
+			Args:   []interface{}{h.hash.Hex()},
+			Result: &txs[i],
+		}, rpc.BatchElem{
+			Method: "priv_getTransactionReceipt",
+			Args:   []interface{}{h.hash.Hex()},
+			Result: &rsps[i],
+		})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), pollTimeout)
+	defer cancel()
+	if err := c.rpc.BatchCallContext(ctx, batch); err != nil {
+		p.failures++
+		if p.failures < maxPollFailures {
+			return resolved
+		}
+		p.failures = 0
+		err = fmt.Errorf("failed to poll receipts, err: %v", err)
+		for _, h := range live {
+			c.emitReceipt(h.hash, nil, err)
+			h.resolve(nil, err)
+		}
+		return append(resolved, live...)
+	}
+	p.failures = 0
+	for i, h := range live {
+		receipt, err := p.result(c, h, txs[i], rsps[i], batch[2*i].Error, batch[2*i+1].Error)
+		if err == ErrPending {
+			continue
+		}
+		c.emitReceipt(h.hash, receipt, err)
+		h.resolve(receipt, err)
+		resolved = append(resolved, h)
+	}
+	return resolved
+}
+
+// markerTx is the part of a privacy marker transaction the poller needs, nil
+// if the node does not know it.
+type markerTx struct {
+	BlockHash *common.Hash `json:"blockHash"`
+}
+
+// result classifies the poll of h, returning ErrPending while it is to be
+// polled again.
+func (p *poller) result(c *Client, h *TxHandle, tx *markerTx, rsp map[string]interface{}, txErr, rspErr error) (*types.PrivateReceipt, error) {
+	if err := rspErr; err != nil || txErr != nil {
+		if err == nil {
+			err = txErr
+		}
+		h.failures++
+		if h.failures < maxPollFailures {
+			return nil, ErrPending
+		}
+		return nil, err
+	}
+	h.failures = 0
+	if rsp != nil {
+		return types.MarshalPrivateReceiptWithMode(rsp, c.DecodeMode())
+	}
+	switch {
+	case tx == nil:
+		h.missing++
+		if h.missing < droppedPolls {
+			return nil, ErrPending
+		}
+		return nil, ErrPMTNotFound
+	case tx.BlockHash == nil:
+		h.missing = 0
+		return nil, ErrPending
+	default:
+		return nil, ErrNotParticipant
+	}
+}
@@ -0,0 +1,146 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bsostech/go-besu/clock"
+	"github.com/bsostech/go-besu/types"
+)
+
+var testChainID = big.NewInt(2018)
+
+func signedGroupTx(t testing.TB, key *ecdsa.PrivateKey, nonce uint64, groupID []byte) *types.PrivateTransaction {
+	tx := types.NewPrivateTransaction(nonce, &common.Address{1}, big.NewInt(0), 100000, big.NewInt(0), nil, make([]byte, 32), groupID)
+	signed, err := tx.SignTx(testChainID, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+// pollUntilDone advances clk by poll intervals until all handles are done.
+func pollUntilDone(t *testing.T, clk *clock.Fake, handles ...*TxHandle) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for _, h := range handles {
+		for {
+			select {
+			case <-h.Done():
+			default:
+				if time.Now().After(deadline) {
+					t.Fatalf("handle %v not resolved", h.Hash().Hex())
+				}
+				clk.Advance(DefaultPollInterval)
+				time.Sleep(time.Millisecond)
+				continue
+			}
+			break
+		}
+	}
+}
+
+func TestSendAsyncOutcomes(t *testing.T) {
+	node := newFakeNode(t)
+	c := newFakeClient(t, node)
+	clk := clock.NewFake(time.Unix(0, 0))
+	c.SetClock(clk)
+	key, _ := crypto.GenerateKey()
+	states := []markerState{markerMined, markerNotParticipant, markerDropped, markerPending}
+	handles := make([]*TxHandle, len(states))
+	pendingCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for i, state := range states {
+		ctx := context.Background()
+		if state == markerPending {
+			ctx = pendingCtx
+		}
+		handles[i] = c.SendAsync(ctx, signedGroupTx(t, key, uint64(i), make([]byte, 32)))
+		if err := handles[i].Err(); err != nil {
+			t.Fatal(err)
+		}
+		node.setMarker(handles[i].Hash(), state)
+	}
+	pollUntilDone(t, clk, handles[:3]...)
+	if receipt, err := handles[0].Receipt(context.Background()); err != nil || receipt.TxHash != handles[0].Hash() {
+		t.Fatalf("mined: got %v, %v", receipt, err)
+	}
+	if err := handles[1].Err(); err != ErrNotParticipant {
+		t.Fatalf("not participant: got %v", err)
+	}
+	if err := handles[2].Err(); err != ErrPMTNotFound {
+		t.Fatalf("dropped: got %v", err)
+	}
+	// pending markers are polled until the context of the send is done
+	for i := 0; i < 2*droppedPolls; i++ {
+		clk.Advance(DefaultPollInterval)
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case <-handles[3].Done():
+		t.Fatalf("pending: resolved with %v", handles[3].Err())
+	default:
+	}
+	cancel()
+	pollUntilDone(t, clk, handles[3])
+	if err := handles[3].Err(); err != context.Canceled {
+		t.Fatalf("pending: got %v", err)
+	}
+}
+
+func TestSendAsyncPendingThenMined(t *testing.T) {
+	node := newFakeNode(t)
+	c := newFakeClient(t, node)
+	clk := clock.NewFake(time.Unix(0, 0))
+	c.SetClock(clk)
+	key, _ := crypto.GenerateKey()
+	node.setState(func(common.Hash) markerState { return markerPending })
+	h := c.SendAsync(context.Background(), signedGroupTx(t, key, 0, make([]byte, 32)))
+	for i := 0; i < 2*droppedPolls; i++ {
+		clk.Advance(DefaultPollInterval)
+		time.Sleep(time.Millisecond)
+	}
+	node.setMarker(h.Hash(), markerMined)
+	pollUntilDone(t, clk, h)
+	if err := h.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSendAsyncPollFailures(t *testing.T) {
+	node := newFakeNode(t)
+	c := newFakeClient(t, node)
+	clk := clock.NewFake(time.Unix(0, 0))
+	c.SetClock(clk)
+	key, _ := crypto.GenerateKey()
+	h := c.SendAsync(context.Background(), signedGroupTx(t, key, 0, make([]byte, 32)))
+	if err := h.Err(); err != nil {
+		t.Fatal(err)
+	}
+	node.failRequests(1000)
+	pollUntilDone(t, clk, h)
+	if err := h.Err(); err == nil || !strings.Contains(err.Error(), "failed to poll receipts") {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestSendAsyncPollRecovers(t *testing.T) {
+	node := newFakeNode(t)
+	c := newFakeClient(t, node)
+	clk := clock.NewFake(time.Unix(0, 0))
+	c.SetClock(clk)
+	key, _ := crypto.GenerateKey()
+	h := c.SendAsync(context.Background(), signedGroupTx(t, key, 0, make([]byte, 32)))
+	node.failRequests(maxPollFailures - 1)
+	pollUntilDone(t, clk, h)
+	if err := h.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
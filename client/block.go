@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/bsostech/go-besu/types"
+)
+
+// BlockPrivateTransaction is a privacy marker transaction of a block resolved
+// to its private transaction and receipt.
+type BlockPrivateTransaction struct {
+	BlockHash   common.Hash
+	BlockNumber uint64
+	BlockTime   uint64
+	Index       uint64
+	Marker      *ethtypes.Transaction
+	Transaction *types.PrivateTransaction
+	Receipt     *types.PrivateReceipt
+}
+
+// BlockPrivateTransactions returns the private transactions of a block, the
+// latest one if number is nil. Privacy marker transactions the node is not a
+// participant of are skipped.
+func (c *Client) BlockPrivateTransactions(ctx context.Context, number *big.Int) ([]*BlockPrivateTransaction, error) {
+	var result []*BlockPrivateTransaction
+	err := c.forEachInBlock(ctx, number, func(btx *BlockPrivateTransaction) error {
+		result = append(result, btx)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ForEachPrivateTransaction calls fn for each private transaction of blocks
+// from to to inclusive, in order, stopping at the first error.
+func (c *Client) ForEachPrivateTransaction(ctx context.Context, from, to uint64, fn func(*BlockPrivateTransaction) error) error {
+	for n := from; n <= to; n++ {
+		if err := c.forEachInBlock(ctx, new(big.Int).SetUint64(n), fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) forEachInBlock(ctx context.Context, number *big.Int, fn func(*BlockPrivateTransaction) error) error {
+	block, err := c.eth.BlockByNumber(ctx, number)
+	if err != nil {
+		return err
+	}
+	for i, tx := range block.Transactions() {
+		if !IsPrivacyMarker(tx.To()) {
+			continue
+		}
+		btx, err := c.resolve(ctx, block, uint64(i), tx)
+		if errors.Is(err, ethereum.NotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(btx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) resolve(ctx context.Context, block *ethtypes.Block, index uint64, tx *ethtypes.Transaction) (*BlockPrivateTransaction, error) {
+	ptx, err := c.PrivateTransaction(ctx, tx.Hash())
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := c.PrivateReceipt(ctx, tx.Hash())
+	if err != nil {
+		return nil, err
+	}
+	return &BlockPrivateTransaction{
+		BlockHash:   block.Hash(),
+		BlockNumber: block.NumberU64(),
+		BlockTime:   block.Time(),
+		Index:       index,
+		Marker:      tx,
+		Transaction: ptx,
+		Receipt:     receipt,
+	}, nil
+}
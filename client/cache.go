@@ -0,0 +1,35 @@
+package client
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/bsostech/go-besu/cache"
+)
+
+const (
+	txCachePrefix      = "tx:"
+	receiptCachePrefix = "receipt:"
+)
+
+// SetCache enables a read-through cache of private transactions, mined
+// private receipts and privacy groups, e.g. cache.NewLRU. Callers must not
+// modify the values returned while a cache is set, as they are shared.
+func (c *Client) SetCache(cc cache.Cache) {
+	c.cache = cc
+	if cc != nil {
+		c.Privacy.SetCache(cc)
+	}
+}
+
+func (c *Client) cached(prefix string, hash common.Hash) (interface{}, bool) {
+	if c.cache == nil {
+		return nil, false
+	}
+	return c.cache.Get(prefix + hash.Hex())
+}
+
+func (c *Client) store(prefix string, hash common.Hash, value interface{}) {
+	if c.cache != nil {
+		c.cache.Add(prefix+hash.Hex(), value)
+	}
+}
@@ -0,0 +1,147 @@
+package client
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/bsostech/go-besu/cache"
+	"github.com/bsostech/go-besu/clock"
+	"github.com/bsostech/go-besu/enclave"
+	"github.com/bsostech/go-besu/labels"
+	"github.com/bsostech/go-besu/plugin"
+	"github.com/bsostech/go-besu/policy"
+	"github.com/bsostech/go-besu/privacy"
+	"github.com/bsostech/go-besu/types"
+)
+
+var (
+	// PrivacyPrecompileAddress is the address privacy marker transactions of
+	// offchain privacy groups are sent to.
+	PrivacyPrecompileAddress = common.HexToAddress("0x000000000000000000000000000000000000007e")
+	// OnchainPrivacyPrecompileAddress is the address privacy marker
+	// transactions of onchain privacy groups are sent to.
+	OnchainPrivacyPrecompileAddress = common.HexToAddress("0x000000000000000000000000000000000000007c")
+)
+
+// Client combines the public chain and privacy APIs of a Besu node.
+type Client struct {
+	*privacy.Privacy
+	rpc      *rpc.Client
+	eth      *ethclient.Client
+	poller   poller
+	labels   labels.Store
+	payloads plugin.PayloadProcessor
+	cache    cache.Cache
+
+	transport Transport
+	enclave   *enclave.Enclave
+	minPeers  int
+
+	parallelism int
+	events      eventBus
+	clock       clock.Clock
+	drainer     drainer
+	policy      policy.Policy
+}
+
+// New .
+func New(c *rpc.Client) *Client {
+	return &Client{
+		Privacy:     privacy.NewPrivacy(c),
+		rpc:         c,
+		eth:         ethclient.NewClient(c),
+		parallelism: DefaultParallelism,
+		minPeers:    DefaultMinPeers,
+		clock:       clock.Real,
+	}
+}
+
+// RPC returns the underlying rpc client.
+func (c *Client) RPC() *rpc.Client {
+	return c.rpc
+}
+
+// SetClock sets the clock receipts are polled and events timed with, e.g. a
+// clock.Fake in tests. It is clock.Real by default.
+func (c *Client) SetClock(clk clock.Clock) {
+	c.clock = clock.Or(clk)
+}
+
+// Clock returns the clock of the client.
+func (c *Client) Clock() clock.Clock {
+	return c.clock
+}
+
+// Eth returns a client for the public chain.
+func (c *Client) Eth() *ethclient.Client {
+	return c.eth
+}
+
+// PrivateTransaction returns the private transaction of a privacy marker transaction.
+// It returns ethereum.NotFound if the node is not a participant.
+func (c *Client) PrivateTransaction(ctx context.Context, pmtHash common.Hash) (*types.PrivateTransaction, error) {
+	if v, ok := c.cached(txCachePrefix, pmtHash); ok {
+		return v.(*types.PrivateTransaction), nil
+	}
+	var (
+		tx  *types.PrivateTransaction
+		err error
+	)
+	if c.payloads != nil {
+		tx, err = c.pluginPrivateTransaction(ctx, pmtHash)
+	} else {
+		tx, err = c.privateTransaction(ctx, pmtHash)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.store(txCachePrefix, pmtHash, tx)
+	return tx, nil
+}
+
+func (c *Client) privateTransaction(ctx context.Context, pmtHash common.Hash) (*types.PrivateTransaction, error) {
+	var rsp map[string]interface{}
+	err := c.rpc.CallContext(ctx, &rsp, "priv_getPrivateTransaction", pmtHash.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if rsp == nil {
+		return nil, ethereum.NotFound
+	}
+	return types.MarshalPrivateTransactionWithMode(rsp, c.DecodeMode())
+}
+
+// PrivateReceipt returns the private receipt of a privacy marker transaction.
+// If there is no receipt, it returns ErrPMTNotFound, ErrPending or
+// ErrNotParticipant, which all match ethereum.NotFound with errors.Is.
+func (c *Client) PrivateReceipt(ctx context.Context, pmtHash common.Hash) (*types.PrivateReceipt, error) {
+	if v, ok := c.cached(receiptCachePrefix, pmtHash); ok {
+		return v.(*types.PrivateReceipt), nil
+	}
+	var rsp map[string]interface{}
+	err := c.rpc.CallContext(ctx, &rsp, "priv_getTransactionReceipt", pmtHash.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if rsp == nil {
+		return nil, c.missingReceiptError(ctx, pmtHash)
+	}
+	receipt, err := types.MarshalPrivateReceiptWithMode(rsp, c.DecodeMode())
+	if err != nil {
+		return nil, err
+	}
+	if receipt.BlockHash != (common.Hash{}) {
+		c.store(receiptCachePrefix, pmtHash, receipt)
+	}
+	return receipt, nil
+}
+
+// IsPrivacyMarker reports whether to is a privacy precompile address.
+func IsPrivacyMarker(to *common.Address) bool {
+	return to != nil && (*to == PrivacyPrecompileAddress || *to == OnchainPrivacyPrecompileAddress ||
+		*to == PluginPrivacyPrecompileAddress)
+}
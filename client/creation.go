@@ -0,0 +1,75 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bsostech/go-besu/types"
+)
+
+// CreationReceipt is the private receipt of a contract creation with the
+// metadata to verify the deployment.
+type CreationReceipt struct {
+	*types.PrivateReceipt
+	InitCodeHash    common.Hash // hash of the payload, code and constructor arguments
+	RuntimeCodeHash common.Hash // hash of the deployed code, zero if none
+	// Set when the creation bytecode is given.
+	BytecodeMatches bool                   // the payload starts with the bytecode
+	ConstructorArgs map[string]interface{} // decoded if an ABI is given
+	RawArgs         []byte
+}
+
+// CreationReceipt returns the private receipt of the contract creation
+// pmtHash with its metadata. If bytecode, the compiled creation code, is set,
+// the constructor arguments are split from the payload, and decoded with
+// contractABI if set.
+func (c *Client) CreationReceipt(ctx context.Context, pmtHash common.Hash, bytecode []byte, contractABI *abi.ABI) (*CreationReceipt, error) {
+	receipt, err := c.PrivateReceipt(ctx, pmtHash)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.ContractAddress == (common.Address{}) {
+		return nil, fmt.Errorf("%v is not a contract creation", pmtHash.Hex())
+	}
+	tx, err := c.PrivateTransaction(ctx, pmtHash)
+	if err != nil {
+		return nil, err
+	}
+	payload := tx.Data()
+	cr := &CreationReceipt{
+		PrivateReceipt: receipt,
+		InitCodeHash:   crypto.Keccak256Hash(payload),
+	}
+	groupID := receipt.PrivacyGroupID
+	if groupID == "" {
+		groupID = c.groupIDOf(tx)
+	}
+	code, err := c.PrivateCodeAt(ctx, groupID, receipt.ContractAddress, receipt.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if len(code) > 0 {
+		cr.RuntimeCodeHash = crypto.Keccak256Hash(code)
+	}
+	if len(bytecode) == 0 {
+		return cr, nil
+	}
+	cr.BytecodeMatches = bytes.HasPrefix(payload, bytecode)
+	if !cr.BytecodeMatches {
+		return cr, nil
+	}
+	cr.RawArgs = payload[len(bytecode):]
+	if contractABI != nil && len(contractABI.Constructor.Inputs) > 0 {
+		args := make(map[string]interface{})
+		if err := contractABI.Constructor.Inputs.UnpackIntoMap(args, cr.RawArgs); err != nil {
+			return cr, fmt.Errorf("failed to decode constructor arguments, err: %v", err)
+		}
+		cr.ConstructorArgs = args
+	}
+	return cr, nil
+}
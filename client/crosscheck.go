@@ -0,0 +1,114 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/bsostech/go-besu/types"
+)
+
+// CrossCheck is the result of CrossCheckReceipt.
+type CrossCheck struct {
+	Receipts   []*types.PrivateReceipt // receipt from each client, nil on error
+	Errors     []error                 // error from each client
+	Mismatches []*ReceiptMismatch
+}
+
+// ReceiptMismatch is a field of the receipt from Client which differs from
+// the one of the reference client, the first one that returned a receipt.
+type ReceiptMismatch struct {
+	Client    int
+	Reference int
+	Field     string
+	Want      string
+	Got       string
+}
+
+// CrossCheckReceipt fetches the private receipt of pmtHash from each client,
+// e.g. the nodes of all members of a group, and reports the fields they
+// disagree on. It only returns an error if no client returned a receipt.
+func CrossCheckReceipt(ctx context.Context, clients []*Client, pmtHash common.Hash) (*CrossCheck, error) {
+	result := &CrossCheck{
+		Receipts: make([]*types.PrivateReceipt, len(clients)),
+		Errors:   make([]error, len(clients)),
+	}
+	var wg sync.WaitGroup
+	for i := range clients {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result.Receipts[i], result.Errors[i] = clients[i].PrivateReceipt(ctx, pmtHash)
+		}(i)
+	}
+	wg.Wait()
+	ref := -1
+	for i := range result.Receipts {
+		if result.Receipts[i] == nil {
+			continue
+		}
+		if ref < 0 {
+			ref = i
+			continue
+		}
+		for _, m := range compareReceipts(result.Receipts[ref], result.Receipts[i]) {
+			m.Client, m.Reference = i, ref
+			result.Mismatches = append(result.Mismatches, m)
+		}
+	}
+	if ref < 0 {
+		return nil, fmt.Errorf("no client returned the private receipt of %v", pmtHash.Hex())
+	}
+	return result, nil
+}
+
+// Consistent reports whether all returned receipts agree.
+func (c *CrossCheck) Consistent() bool {
+	return len(c.Mismatches) == 0
+}
+
+func compareReceipts(want, got *types.PrivateReceipt) []*ReceiptMismatch {
+	var mismatches []*ReceiptMismatch
+	add := func(field string, w, g interface{}) {
+		mismatches = append(mismatches, &ReceiptMismatch{Field: field, Want: fmt.Sprint(w), Got: fmt.Sprint(g)})
+	}
+	if want.Status != got.Status {
+		add("status", want.Status, got.Status)
+	}
+	if !bytes.Equal(want.Output, got.Output) {
+		add("output", hexutil.Encode(want.Output), hexutil.Encode(got.Output))
+	}
+	if want.ContractAddress != got.ContractAddress {
+		add("contractAddress", want.ContractAddress.Hex(), got.ContractAddress.Hex())
+	}
+	if want.CommitmentHash != got.CommitmentHash {
+		add("commitmentHash", want.CommitmentHash.Hex(), got.CommitmentHash.Hex())
+	}
+	if len(want.Logs) != len(got.Logs) {
+		add("logs", len(want.Logs), len(got.Logs))
+		return mismatches
+	}
+	for i := range want.Logs {
+		if !equalLogs(want.Logs[i], got.Logs[i]) {
+			add(fmt.Sprintf("logs[%d]", i), want.Logs[i].Topics, got.Logs[i].Topics)
+		}
+	}
+	return mismatches
+}
+
+func equalLogs(a, b *ethtypes.Log) bool {
+	if a.Address != b.Address || !bytes.Equal(a.Data, b.Data) || len(a.Topics) != len(b.Topics) {
+		return false
+	}
+	for i := range a.Topics {
+		if a.Topics[i] != b.Topics[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/bsostech/go-besu/types"
+)
+
+// DisclosureBundle discloses a private transaction to a non-member, e.g. an
+// auditor: the private transaction and receipt, the header of the block of
+// the marker and the inclusion proof of the marker, signed by the exporting
+// member. It is meant to be serialized as JSON.
+type DisclosureBundle struct {
+	PrivateTransaction hexutil.Bytes   `json:"privateTransaction"` // RLP encoded
+	Header             hexutil.Bytes   `json:"header"`             // RLP encoded
+	Proof              *InclusionProof `json:"proof"`
+	Signer             common.Address  `json:"signer"`
+	Signature          hexutil.Bytes   `json:"signature"`
+}
+
+// ExportDisclosure builds the disclosure bundle of pmtHash signed with key.
+func (c *Client) ExportDisclosure(ctx context.Context, pmtHash common.Hash, key *ecdsa.PrivateKey) (*DisclosureBundle, error) {
+	proof, err := c.BuildInclusionProof(ctx, pmtHash)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := c.PrivateTransaction(ctx, pmtHash)
+	if err != nil {
+		return nil, err
+	}
+	rawTx, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return nil, err
+	}
+	header, err := c.eth.HeaderByHash(ctx, proof.BlockHash)
+	if err != nil {
+		return nil, err
+	}
+	rawHeader, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return nil, err
+	}
+	b := &DisclosureBundle{
+		PrivateTransaction: rawTx,
+		Header:             rawHeader,
+		Proof:              proof,
+		Signer:             crypto.PubkeyToAddress(key.PublicKey),
+	}
+	hash, err := b.Hash()
+	if err != nil {
+		return nil, err
+	}
+	if b.Signature, err = crypto.Sign(hash.Bytes(), key); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Hash returns the digest signed by Signer, covering everything but the signature.
+func (b *DisclosureBundle) Hash() (common.Hash, error) {
+	if b.Proof == nil || b.Proof.PrivateReceipt == nil {
+		return common.Hash{}, fmt.Errorf("proof not found")
+	}
+	receipt := b.Proof.PrivateReceipt
+	enc, err := rlp.EncodeToBytes([]interface{}{
+		[]byte(b.PrivateTransaction),
+		[]byte(b.Header),
+		b.Proof.BlockHash,
+		b.Proof.BlockNumber,
+		b.Proof.TxRoot,
+		b.Proof.Index,
+		[]byte(b.Proof.Transaction),
+		receipt,
+		receipt.TxHash,
+		receipt.CommitmentHash,
+		receipt.ContractAddress,
+		receipt.PrivacyGroupID,
+		b.Signer,
+	})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(enc), nil
+}
+
+// Transaction decodes the disclosed private transaction.
+func (b *DisclosureBundle) Transaction() (*types.PrivateTransaction, error) {
+	tx := new(types.PrivateTransaction)
+	if err := rlp.DecodeBytes(b.PrivateTransaction, tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// Verify checks the signature of the bundle, that the header is the block of
+// the proof, the inclusion proof, and that the private transaction is the one
+// of the private receipt. It does not check the block is canonical, which the
+// verifier has to check against a node or a trusted header.
+func (b *DisclosureBundle) Verify() error {
+	hash, err := b.Hash()
+	if err != nil {
+		return err
+	}
+	if len(b.Signature) != 65 {
+		return fmt.Errorf("invalid signature")
+	}
+	pub, err := crypto.SigToPub(hash.Bytes(), b.Signature)
+	if err != nil {
+		return err
+	}
+	if signer := crypto.PubkeyToAddress(*pub); signer != b.Signer {
+		return fmt.Errorf("signed by %v, not %v", signer.Hex(), b.Signer.Hex())
+	}
+	header := new(ethtypes.Header)
+	if err := rlp.DecodeBytes(b.Header, header); err != nil {
+		return fmt.Errorf("invalid header, err: %v", err)
+	}
+	if header.Hash() != b.Proof.BlockHash {
+		return fmt.Errorf("header is not block %v", b.Proof.BlockHash.Hex())
+	}
+	if header.TxHash != b.Proof.TxRoot {
+		return fmt.Errorf("transactions root mismatch: got %v, want %v", b.Proof.TxRoot.Hex(), header.TxHash.Hex())
+	}
+	if err := b.Proof.Verify(); err != nil {
+		return err
+	}
+	if crypto.Keccak256Hash(b.PrivateTransaction) != b.Proof.PrivateReceipt.TxHash {
+		return fmt.Errorf("private transaction is not the one of the private receipt")
+	}
+	return nil
+}
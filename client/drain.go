@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrDraining means a transaction was not sent because the client is draining.
+var ErrDraining = errors.New("client is draining")
+
+// DrainReport is the outcome of Drain.
+type DrainReport struct {
+	Resolved   int         // handles resolved while draining
+	Unresolved []*TxHandle // handles still pending when ctx was done
+}
+
+// drainer tracks the handles of SendAsync and OrderedSender so that Drain can wait for them.
+type drainer struct {
+	mu       sync.Mutex
+	draining bool
+	handles  map[*TxHandle]struct{}
+}
+
+// track registers h until it resolves, returning false if the client is draining.
+func (d *drainer) track(h *TxHandle) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.draining {
+		return false
+	}
+	if d.handles == nil {
+		d.handles = make(map[*TxHandle]struct{})
+	}
+	d.handles[h] = struct{}{}
+	h.untrack = func() {
+		d.mu.Lock()
+		delete(d.handles, h)
+		d.mu.Unlock()
+	}
+	return true
+}
+
+// Drain stops SendAsync and OrderedSender from accepting transactions, which
+// then fail with ErrDraining, and waits until the pending ones are resolved
+// or ctx is done, e.g. before a rolling restart. The unresolved handles are
+// reported, those with a Hash have been sent and may still be mined.
+func (c *Client) Drain(ctx context.Context) *DrainReport {
+	d := &c.drainer
+	d.mu.Lock()
+	d.draining = true
+	handles := make([]*TxHandle, 0, len(d.handles))
+	for h := range d.handles {
+		handles = append(handles, h)
+	}
+	d.mu.Unlock()
+	report := new(DrainReport)
+	for i, h := range handles {
+		select {
+		case <-h.done:
+			report.Resolved++
+		case <-ctx.Done():
+			for _, h := range handles[i:] {
+				select {
+				case <-h.done:
+					report.Resolved++
+				default:
+					report.Unresolved = append(report.Unresolved, h)
+				}
+			}
+			return report
+		}
+	}
+	return report
+}
+
+// Draining reports whether Drain has been called.
+func (c *Client) Draining() bool {
+	c.drainer.mu.Lock()
+	defer c.drainer.mu.Unlock()
+	return c.drainer.draining
+}
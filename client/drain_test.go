@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bsostech/go-besu/clock"
+)
+
+func trackedHandles(c *Client) int {
+	c.drainer.mu.Lock()
+	defer c.drainer.mu.Unlock()
+	return len(c.drainer.handles)
+}
+
+func TestResolvedHandlesUntracked(t *testing.T) {
+	node := newFakeNode(t)
+	c := newFakeClient(t, node)
+	clk := clock.NewFake(time.Unix(0, 0))
+	c.SetClock(clk)
+	key, _ := crypto.GenerateKey()
+	handles := make([]*TxHandle, 20)
+	for i := range handles {
+		handles[i] = c.SendAsync(context.Background(), signedGroupTx(t, key, uint64(i), make([]byte, 32)))
+	}
+	pollUntilDone(t, clk, handles...)
+	if n := trackedHandles(c); n != 0 {
+		t.Fatalf("%v resolved handles still tracked", n)
+	}
+}
+
+func TestDrainWhileSending(t *testing.T) {
+	node := newFakeNode(t)
+	node.setState(func(common.Hash) markerState { return markerPending })
+	c := newFakeClient(t, node)
+	key, _ := crypto.GenerateKey()
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		tx := signedGroupTx(t, key, uint64(i), make([]byte, 32))
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			c.SendAsync(context.Background(), tx)
+		}()
+		go func() {
+			defer wg.Done()
+			c.NewOrderedSender().Enqueue(context.Background(), tx)
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	report := c.Drain(ctx)
+	// the handles may still be sending
+	for _, h := range report.Unresolved {
+		h.Hash()
+	}
+	wg.Wait()
+	if report.Resolved+len(report.Unresolved) == 0 {
+		t.Fatal("no handle drained")
+	}
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Receipt lookup errors. They all match ethereum.NotFound with errors.Is.
+var (
+	// ErrPMTNotFound means the node knows no privacy marker transaction of the hash.
+	ErrPMTNotFound error = &notFoundError{"privacy marker transaction not found"}
+	// ErrPending means the privacy marker transaction is not mined yet.
+	ErrPending error = &notFoundError{"privacy marker transaction pending"}
+	// ErrNotParticipant means the privacy marker transaction is mined but the
+	// node has no private receipt, as it is not a participant.
+	ErrNotParticipant error = &notFoundError{"private receipt not available, node is not a participant"}
+)
+
+type notFoundError struct {
+	msg string
+}
+
+func (e *notFoundError) Error() string {
+	return e.msg
+}
+
+func (e *notFoundError) Is(target error) bool {
+	return target == ethereum.NotFound
+}
+
+// missingReceiptError tells why the node returned no private receipt for pmtHash.
+func (c *Client) missingReceiptError(ctx context.Context, pmtHash common.Hash) error {
+	_, pending, err := c.eth.TransactionByHash(ctx, pmtHash)
+	if err == ethereum.NotFound {
+		return ErrPMTNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if pending {
+		return ErrPending
+	}
+	return ErrNotParticipant
+}
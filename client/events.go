@@ -0,0 +1,148 @@
+package client
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/bsostech/go-besu/types"
+)
+
+// EventType is a stage of the lifecycle of a private transaction.
+type EventType int
+
+// EventType .
+const (
+	// Distributed means the private transaction was distributed to the
+	// enclaves, see DistributeTransaction.
+	Distributed EventType = iota
+	// Submitted means the private transaction was sent and its privacy marker
+	// transaction is in the pool.
+	Submitted
+	// Mined means the privacy marker transaction was included in a block.
+	Mined
+	// PrivateReceiptAvailable means the private receipt was received.
+	PrivateReceiptAvailable
+	// Failed means sending failed, the private transaction reverted, or the
+	// node has no private receipt of a mined marker.
+	Failed
+)
+
+var eventTypeNames = []string{"distributed", "submitted", "mined", "privateReceipt", "failed"}
+
+func (t EventType) String() string {
+	if int(t) < len(eventTypeNames) {
+		return eventTypeNames[t]
+	}
+	return fmt.Sprintf("EventType(%d)", int(t))
+}
+
+// Event reports a stage of the lifecycle of a private transaction.
+type Event struct {
+	Type        EventType
+	Time        time.Time
+	Hash        common.Hash // privacy marker transaction, zero before Submitted
+	EnclaveKey  []byte      // for Distributed
+	BlockNumber *big.Int    // for Mined
+	Receipt     *types.PrivateReceipt
+	Err         error // for Failed
+}
+
+// Hooks are callbacks per lifecycle stage, nil ones being skipped.
+type Hooks struct {
+	OnDistributed    func(Event)
+	OnSubmitted      func(Event)
+	OnMined          func(Event)
+	OnPrivateReceipt func(Event)
+	OnFailed         func(Event)
+}
+
+func (h Hooks) handle(e Event) {
+	var fn func(Event)
+	switch e.Type {
+	case Distributed:
+		fn = h.OnDistributed
+	case Submitted:
+		fn = h.OnSubmitted
+	case Mined:
+		fn = h.OnMined
+	case PrivateReceiptAvailable:
+		fn = h.OnPrivateReceipt
+	case Failed:
+		fn = h.OnFailed
+	}
+	if fn != nil {
+		fn(e)
+	}
+}
+
+// eventBus dispatches events to the handlers of a client.
+type eventBus struct {
+	mu       sync.RWMutex
+	next     int
+	handlers map[int]func(Event)
+}
+
+// Subscribe registers fn for all lifecycle events of the transactions sent
+// and received through the client, and returns a function unregistering it.
+// Handlers are called synchronously and must not block.
+func (c *Client) Subscribe(fn func(Event)) (unsubscribe func()) {
+	b := &c.events
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.handlers == nil {
+		b.handlers = make(map[int]func(Event))
+	}
+	id := b.next
+	b.next++
+	b.handlers[id] = fn
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.handlers, id)
+	}
+}
+
+// AddHooks registers h, see Subscribe.
+func (c *Client) AddHooks(h Hooks) (unsubscribe func()) {
+	return c.Subscribe(h.handle)
+}
+
+func (c *Client) emit(e Event) {
+	b := &c.events
+	b.mu.RLock()
+	if len(b.handlers) == 0 {
+		b.mu.RUnlock()
+		return
+	}
+	// handlers are called unlocked, as they may subscribe or unsubscribe
+	handlers := make([]func(Event), 0, len(b.handlers))
+	for _, fn := range b.handlers {
+		handlers = append(handlers, fn)
+	}
+	b.mu.RUnlock()
+	if e.Time.IsZero() {
+		e.Time = c.clock.Now()
+	}
+	for _, fn := range handlers {
+		fn(e)
+	}
+}
+
+// emitReceipt emits the events of a private receipt received for pmtHash.
+func (c *Client) emitReceipt(pmtHash common.Hash, receipt *types.PrivateReceipt, err error) {
+	if err != nil {
+		if err != ErrPending && err != ErrPMTNotFound {
+			c.emit(Event{Type: Failed, Hash: pmtHash, Err: err})
+		}
+		return
+	}
+	c.emit(Event{Type: Mined, Hash: pmtHash, BlockNumber: receipt.BlockNumber, Receipt: receipt})
+	c.emit(Event{Type: PrivateReceiptAvailable, Hash: pmtHash, Receipt: receipt})
+	if receipt.Status != 1 {
+		c.emit(Event{Type: Failed, Hash: pmtHash, Receipt: receipt, Err: ClassifyReceipt(pmtHash, receipt)})
+	}
+}
@@ -0,0 +1,33 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSubscribeFromHandler(t *testing.T) {
+	c := newFakeClient(t, newFakeNode(t))
+	var received []EventType
+	var unsubscribe func()
+	unsubscribe = c.Subscribe(func(e Event) {
+		received = append(received, e.Type)
+		unsubscribe()
+		c.Subscribe(func(e Event) {
+			received = append(received, e.Type)
+		})
+	})
+	done := make(chan struct{})
+	go func() {
+		c.emit(Event{Type: Submitted})
+		c.emit(Event{Type: Mined})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("emit deadlocked")
+	}
+	if len(received) != 2 || received[0] != Submitted || received[1] != Mined {
+		t.Fatalf("received %v, want [submitted mined]", received)
+	}
+}
@@ -0,0 +1,168 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/bsostech/go-besu/privacy"
+	"github.com/bsostech/go-besu/types"
+)
+
+// ErrPrivateExecutionFailed is matched by the *PrivateFailure errors of private
+// transactions whose marker was mined but whose private execution failed.
+var ErrPrivateExecutionFailed = errors.New("private execution failed")
+
+// TraceTransactionMethod is the method tracing a mined private transaction,
+// taking the privacy group ID and the privacy marker transaction hash.
+var TraceTransactionMethod = "priv_traceTransaction"
+
+// FailureCause classifies why a private transaction failed.
+type FailureCause int
+
+// FailureCause .
+const (
+	CauseUnknown FailureCause = iota
+	// CauseRevert means the contract reverted.
+	CauseRevert
+	// CauseOutOfGas means the private execution ran out of gas.
+	CauseOutOfGas
+	// CauseInvalidNonce means the private nonce was not the next one of the
+	// sender in the group, so the transaction was not executed.
+	CauseInvalidNonce
+	// CauseMissingPayload means the node is a participant but its enclave has
+	// no payload of the transaction.
+	CauseMissingPayload
+)
+
+var failureCauseNames = []string{"unknown", "revert", "outOfGas", "invalidNonce", "missingPayload"}
+
+func (c FailureCause) String() string {
+	if int(c) < len(failureCauseNames) {
+		return failureCauseNames[c]
+	}
+	return fmt.Sprintf("FailureCause(%d)", int(c))
+}
+
+// PrivateFailure is the classified failure of a private transaction.
+type PrivateFailure struct {
+	Hash    common.Hash // privacy marker transaction
+	Cause   FailureCause
+	Reason  string
+	Receipt *types.PrivateReceipt // nil for CauseMissingPayload
+}
+
+func (e *PrivateFailure) Error() string {
+	return fmt.Sprintf("%v (%v): %v", ErrPrivateExecutionFailed, e.Cause, e.Reason)
+}
+
+// Is reports whether target is ErrPrivateExecutionFailed.
+func (e *PrivateFailure) Is(target error) bool {
+	return target == ErrPrivateExecutionFailed
+}
+
+// ClassifyReceipt classifies the failure of receipt from the receipt alone,
+// CauseRevert if it carries revert data and CauseUnknown otherwise. It
+// returns nil if the transaction succeeded.
+func ClassifyReceipt(pmtHash common.Hash, receipt *types.PrivateReceipt) *PrivateFailure {
+	if receipt.Status == 1 {
+		return nil
+	}
+	f := &PrivateFailure{
+		Hash:    pmtHash,
+		Cause:   CauseUnknown,
+		Reason:  receipt.FailureReason(),
+		Receipt: receipt,
+	}
+	if len(receipt.RevertReason) > 0 || len(receipt.Output) > 0 {
+		f.Cause = CauseRevert
+	}
+	return f
+}
+
+// ClassifyFailure classifies why the private transaction of pmtHash failed,
+// returning nil if it succeeded. Receipts without revert data are classified
+// with TraceTransactionMethod where the node supports it, and by checking
+// whether the transaction consumed the private nonce of its sender, as
+// transactions with an invalid nonce are not executed.
+//
+// The node returns no receipt both when it is not a participant and when its
+// enclave lost the payload, so if group, the group the transaction was sent
+// to, has self, the enclave key of the node, as member, a missing receipt is
+// classified as CauseMissingPayload. Otherwise it is ErrNotParticipant.
+func (c *Client) ClassifyFailure(ctx context.Context, pmtHash common.Hash, group *privacy.Group, self privacy.PublicKey) (*PrivateFailure, error) {
+	receipt, err := c.PrivateReceipt(ctx, pmtHash)
+	if err == ErrNotParticipant && group != nil && group.Participants().Contains(self) {
+		return &PrivateFailure{Hash: pmtHash, Cause: CauseMissingPayload, Reason: "enclave has no payload of the transaction"}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	f := ClassifyReceipt(pmtHash, receipt)
+	if f == nil || f.Cause != CauseUnknown {
+		return f, nil
+	}
+	if cause, reason, ok := c.traceFailure(ctx, pmtHash, receipt.PrivacyGroupID); ok {
+		f.Cause, f.Reason = cause, reason
+		return f, nil
+	}
+	if invalid, err := c.nonceNotConsumed(ctx, pmtHash, receipt); err == nil && invalid {
+		f.Cause, f.Reason = CauseInvalidNonce, "private nonce not consumed"
+	}
+	return f, nil
+}
+
+// traceFailure returns the cause of the error of the top level trace of the transaction.
+func (c *Client) traceFailure(ctx context.Context, pmtHash common.Hash, groupID string) (FailureCause, string, bool) {
+	if groupID == "" {
+		return CauseUnknown, "", false
+	}
+	var traces []struct {
+		Error        string `json:"error"`
+		TraceAddress []int  `json:"traceAddress"`
+	}
+	if err := c.rpc.CallContext(ctx, &traces, TraceTransactionMethod, groupID, pmtHash); err != nil {
+		return CauseUnknown, "", false
+	}
+	for _, t := range traces {
+		if len(t.TraceAddress) != 0 || t.Error == "" {
+			continue
+		}
+		msg := strings.ToLower(t.Error)
+		switch {
+		case strings.Contains(msg, "out of gas"):
+			return CauseOutOfGas, t.Error, true
+		case strings.Contains(msg, "revert"):
+			return CauseRevert, t.Error, true
+		case strings.Contains(msg, "nonce"):
+			return CauseInvalidNonce, t.Error, true
+		}
+		return CauseUnknown, t.Error, true
+	}
+	return CauseUnknown, "", false
+}
+
+// nonceNotConsumed reports whether the private nonce of the sender is still
+// at or below the nonce of the transaction, which executed transactions
+// consume even if they fail.
+func (c *Client) nonceNotConsumed(ctx context.Context, pmtHash common.Hash, receipt *types.PrivateReceipt) (bool, error) {
+	if receipt.PrivacyGroupID == "" {
+		return false, fmt.Errorf("privacyGroupId not found")
+	}
+	tx, err := c.PrivateTransaction(ctx, pmtHash)
+	if err != nil {
+		return false, err
+	}
+	sender, err := tx.Sender()
+	if err != nil {
+		return false, err
+	}
+	nonce, err := c.PrivateNonce(sender, &privacy.Group{ID: receipt.PrivacyGroupID})
+	if err != nil {
+		return false, err
+	}
+	return nonce <= tx.Nonce(), nil
+}
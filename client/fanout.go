@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultParallelism is the number of groups QueryAcrossGroups reads concurrently.
+const DefaultParallelism = 8
+
+// GroupQuery is a read in one privacy group, e.g. a call, a logs query or a nonce.
+type GroupQuery func(ctx context.Context, privacyGroupID string) (interface{}, error)
+
+// GroupResult is the result of a GroupQuery.
+type GroupResult struct {
+	PrivacyGroupID string
+	Value          interface{}
+	Err            error
+}
+
+// GroupErrors are the failed queries of QueryAcrossGroups by privacy group ID.
+type GroupErrors map[string]error
+
+func (e GroupErrors) Error() string {
+	ids := make([]string, 0, len(e))
+	for id := range e {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	msgs := make([]string, len(ids))
+	for i, id := range ids {
+		msgs[i] = fmt.Sprintf("%v: %v", id, e[id])
+	}
+	return fmt.Sprintf("%v of the group queries failed: %v", len(e), strings.Join(msgs, "; "))
+}
+
+// SetParallelism sets the number of groups QueryAcrossGroups reads concurrently.
+func (c *Client) SetParallelism(n int) {
+	if n < 1 {
+		n = 1
+	}
+	c.parallelism = n
+}
+
+// QueryAcrossGroups runs fn for each group with bounded parallelism and
+// returns the results in the order of privacyGroupIDs. A failed query does
+// not stop the others: the error is GroupErrors holding every failure, while
+// the results of the successful queries are still returned.
+func (c *Client) QueryAcrossGroups(ctx context.Context, privacyGroupIDs []string, fn GroupQuery) ([]GroupResult, error) {
+	results := make([]GroupResult, len(privacyGroupIDs))
+	slots := make(chan struct{}, c.parallelism)
+	var wg sync.WaitGroup
+	for i, id := range privacyGroupIDs {
+		results[i].PrivacyGroupID = id
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(r *GroupResult) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			r.Value, r.Err = fn(ctx, r.PrivacyGroupID)
+		}(&results[i])
+	}
+	wg.Wait()
+	errs := make(GroupErrors)
+	for _, r := range results {
+		if r.Err != nil {
+			errs[r.PrivacyGroupID] = r.Err
+		}
+	}
+	if len(errs) > 0 {
+		return results, errs
+	}
+	return results, nil
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Fees are the fee settings of a transaction. Before London only GasPrice is set.
+type Fees struct {
+	London               bool
+	GasPrice             *big.Int // for legacy transactions, base fee included
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+}
+
+// FeeEstimator estimates the fees of privacy marker transactions from the
+// fee history of the recent blocks, or from eth_gasPrice on nodes without
+// eth_feeHistory or before London.
+type FeeEstimator struct {
+	Blocks         uint64   // number of blocks of the history
+	Percentile     float64  // priority fee percentile of each block
+	MinPriorityFee *big.Int // floor of the priority fee, nil means none
+	MaxFee         *big.Int // cap of the max fee and gas price, nil means none
+
+	client *Client
+}
+
+type feeHistory struct {
+	BaseFeePerGas []*hexutil.Big   `json:"baseFeePerGas"`
+	Reward        [][]*hexutil.Big `json:"reward"`
+}
+
+// NewFeeEstimator returns an estimator of the median priority fee of the last 20 blocks.
+func NewFeeEstimator(c *Client) *FeeEstimator {
+	return &FeeEstimator{
+		Blocks:     20,
+		Percentile: 50,
+		client:     c,
+	}
+}
+
+// Estimate returns the fees of a transaction to be included in the next
+// blocks: the median of the priority fee percentiles of the history, and a max
+// fee allowing the base fee to double.
+func (e *FeeEstimator) Estimate(ctx context.Context) (*Fees, error) {
+	var history feeHistory
+	err := e.client.rpc.CallContext(ctx, &history, "eth_feeHistory",
+		hexutil.EncodeUint64(e.Blocks), "latest", []float64{e.Percentile})
+	if err != nil || len(history.BaseFeePerGas) == 0 || history.BaseFeePerGas[len(history.BaseFeePerGas)-1].ToInt().Sign() == 0 {
+		return e.legacy(ctx)
+	}
+	var rewards []*big.Int
+	for _, r := range history.Reward {
+		if len(r) > 0 && r[0] != nil {
+			rewards = append(rewards, r[0].ToInt())
+		}
+	}
+	tip := new(big.Int)
+	if len(rewards) > 0 {
+		sort.Slice(rewards, func(i, j int) bool { return rewards[i].Cmp(rewards[j]) < 0 })
+		tip.Set(rewards[len(rewards)/2])
+	}
+	if e.MinPriorityFee != nil && tip.Cmp(e.MinPriorityFee) < 0 {
+		tip.Set(e.MinPriorityFee)
+	}
+	// the last entry is the base fee of the next block
+	baseFee := history.BaseFeePerGas[len(history.BaseFeePerGas)-1].ToInt()
+	maxFee := new(big.Int).Mul(baseFee, big.NewInt(2))
+	maxFee.Add(maxFee, tip)
+	maxFee = e.capFee(maxFee)
+	if tip.Cmp(maxFee) > 0 {
+		tip.Set(maxFee)
+	}
+	return &Fees{
+		London:               true,
+		GasPrice:             new(big.Int).Set(maxFee),
+		MaxFeePerGas:         maxFee,
+		MaxPriorityFeePerGas: tip,
+	}, nil
+}
+
+func (e *FeeEstimator) legacy(ctx context.Context) (*Fees, error) {
+	gasPrice, err := e.client.eth.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Fees{
+		GasPrice: e.capFee(gasPrice),
+	}, nil
+}
+
+func (e *FeeEstimator) capFee(fee *big.Int) *big.Int {
+	if e.MaxFee != nil && fee.Cmp(e.MaxFee) > 0 {
+		return new(big.Int).Set(e.MaxFee)
+	}
+	return fee
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/bsostech/go-besu/enclave"
+)
+
+// DefaultMinPeers is the number of peers a node needs to be ready.
+const DefaultMinPeers = 1
+
+// HealthReport is the readiness of a node and its enclave.
+type HealthReport struct {
+	Ready  bool           `json:"ready"`
+	Checks []*HealthCheck `json:"checks"`
+}
+
+// HealthCheck is the result of one check of a HealthReport.
+type HealthCheck struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// SetEnclave sets the enclave checked by HealthCheck.
+func (c *Client) SetEnclave(e *enclave.Enclave) {
+	c.enclave = e
+}
+
+// SetMinPeers sets the number of peers the node needs to be ready, e.g. 0
+// for single node networks.
+func (c *Client) SetMinPeers(n int) {
+	c.minPeers = n
+}
+
+// HealthCheck checks that the node is not syncing and has enough peers, and
+// that the enclave is up if one is set. The node is ready if all checks pass.
+func (c *Client) HealthCheck(ctx context.Context) *HealthReport {
+	report := &HealthReport{
+		Checks: []*HealthCheck{c.checkSyncing(ctx), c.checkPeers(ctx)},
+	}
+	if c.enclave != nil {
+		check := &HealthCheck{Name: "enclave", OK: true}
+		if err := c.enclave.Upcheck(ctx); err != nil {
+			check.OK, check.Detail = false, err.Error()
+		}
+		report.Checks = append(report.Checks, check)
+	}
+	report.Ready = true
+	for _, check := range report.Checks {
+		report.Ready = report.Ready && check.OK
+	}
+	return report
+}
+
+// HealthHandler serves HealthCheck as JSON, with status 503 if the node is not
+// ready, for readiness probes.
+func (c *Client) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := c.HealthCheck(r.Context())
+		w.Header().Set("Content-Type", "application/json")
+		if !report.Ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(report)
+	})
+}
+
+func (c *Client) checkSyncing(ctx context.Context) *HealthCheck {
+	check := &HealthCheck{Name: "syncing"}
+	progress, err := c.eth.SyncProgress(ctx)
+	switch {
+	case err != nil:
+		check.Detail = err.Error()
+	case progress != nil:
+		check.Detail = fmt.Sprintf("syncing, block %v of %v", progress.CurrentBlock, progress.HighestBlock)
+	default:
+		check.OK = true
+	}
+	return check
+}
+
+func (c *Client) checkPeers(ctx context.Context) *HealthCheck {
+	check := &HealthCheck{Name: "peers"}
+	count, err := c.PeerCount(ctx)
+	if err != nil {
+		check.Detail = err.Error()
+		return check
+	}
+	check.Detail = fmt.Sprintf("%v peers", count)
+	check.OK = count >= uint64(c.minPeers)
+	return check
+}
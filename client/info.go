@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// NodeInfo describes the node a client is connected to.
+type NodeInfo struct {
+	ClientVersion string   `json:"clientVersion"` // web3_clientVersion
+	Client        string   `json:"client"`        // e.g. "besu"
+	Version       string   `json:"version"`       // e.g. "v1.4.4"
+	NetworkID     string   `json:"networkId"`     // net_version
+	ChainID       *big.Int `json:"chainId"`       // eth_chainId
+	PeerCount     uint64   `json:"peerCount"`     // net_peerCount
+}
+
+// String returns a short description, e.g. for log prefixes.
+func (i *NodeInfo) String() string {
+	return fmt.Sprintf("%v/%v chain=%v network=%v peers=%v", i.Client, i.Version, i.ChainID, i.NetworkID, i.PeerCount)
+}
+
+// Info returns the NodeInfo of the node, fetched in one batch.
+func (c *Client) Info(ctx context.Context) (*NodeInfo, error) {
+	var (
+		clientVersion string
+		networkID     string
+		chainID       hexutil.Big
+		peerCount     hexutil.Uint64
+	)
+	batch := []rpc.BatchElem{
+		{Method: "web3_clientVersion", Result: &clientVersion},
+		{Method: "net_version", Result: &networkID},
+		{Method: "eth_chainId", Result: &chainID},
+		{Method: "net_peerCount", Result: &peerCount},
+	}
+	if err := c.rpc.BatchCallContext(ctx, batch); err != nil {
+		return nil, err
+	}
+	for _, elem := range batch {
+		if elem.Error != nil {
+			return nil, fmt.Errorf("%v failed, err: %v", elem.Method, elem.Error)
+		}
+	}
+	info := &NodeInfo{
+		ClientVersion: clientVersion,
+		NetworkID:     networkID,
+		ChainID:       chainID.ToInt(),
+		PeerCount:     uint64(peerCount),
+	}
+	parts := strings.Split(clientVersion, "/")
+	info.Client = strings.ToLower(parts[0])
+	if len(parts) > 1 {
+		info.Version = parts[1]
+	}
+	return info, nil
+}
+
+// PeerCount returns the number of peers of the node with net_peerCount.
+func (c *Client) PeerCount(ctx context.Context) (uint64, error) {
+	var count hexutil.Uint64
+	if err := c.rpc.CallContext(ctx, &count, "net_peerCount"); err != nil {
+		return 0, err
+	}
+	return uint64(count), nil
+}
+
+// NetworkID returns the network ID of the node with net_version.
+func (c *Client) NetworkID(ctx context.Context) (string, error) {
+	var id string
+	if err := c.rpc.CallContext(ctx, &id, "net_version"); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// ChainID returns the chain ID of the node with eth_chainId.
+func (c *Client) ChainID(ctx context.Context) (*big.Int, error) {
+	var id hexutil.Big
+	if err := c.rpc.CallContext(ctx, &id, "eth_chainId"); err != nil {
+		return nil, err
+	}
+	return id.ToInt(), nil
+}
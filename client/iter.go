@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/bsostech/go-besu/types"
+)
+
+// LogSeq is a lazy sequence of logs. It has the shape of
+// iter.Seq2[ethtypes.Log, error], so with Go 1.23 it can be ranged over:
+//
+//	for log, err := range c.PrivateLogsSeq(ctx, groupID, q, client.LogPageOptions{}) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+//
+// An error is yielded once, as the last element. Breaking out of the loop
+// stops the queries.
+type LogSeq func(yield func(ethtypes.Log, error) bool)
+
+// ReceiptSeq is a lazy sequence of private receipts, see LogSeq.
+type ReceiptSeq func(yield func(*types.PrivateReceipt, error) bool)
+
+// PrivateLogsSeq returns the private logs of a privacy group matching q like
+// PrivateLogsPaged, querying each page only once the logs of the previous one
+// are consumed. The sequence ends with ctx.Err() once ctx is done.
+func (c *Client) PrivateLogsSeq(ctx context.Context, privacyGroupID string, q ethereum.FilterQuery, opts LogPageOptions) LogSeq {
+	return func(yield func(ethtypes.Log, error) bool) {
+		stopped := false
+		err := c.pageLogs(ctx, privacyGroupID, q, opts, func(logs []ethtypes.Log) bool {
+			for _, log := range logs {
+				if err := ctx.Err(); err != nil {
+					yield(ethtypes.Log{}, err)
+					stopped = true
+					return false
+				}
+				if !yield(log, nil) {
+					stopped = true
+					return false
+				}
+			}
+			return true
+		})
+		if err != nil && !stopped {
+			yield(ethtypes.Log{}, err)
+		}
+	}
+}
+
+// ReceiptsByGroupSeq returns the private receipts of the transactions of a
+// privacy group which emitted logs in blocks like GetReceiptsByGroup, fetching
+// them as they are consumed.
+func (c *Client) ReceiptsByGroupSeq(ctx context.Context, privacyGroupID string, blocks BlockRange, opts LogPageOptions) ReceiptSeq {
+	return func(yield func(*types.PrivateReceipt, error) bool) {
+		seen := make(map[common.Hash]bool)
+		q := ethereum.FilterQuery{FromBlock: blocks.From, ToBlock: blocks.To}
+		c.PrivateLogsSeq(ctx, privacyGroupID, q, opts)(func(log ethtypes.Log, err error) bool {
+			if err != nil {
+				yield(nil, err)
+				return false
+			}
+			if seen[log.TxHash] {
+				return true
+			}
+			seen[log.TxHash] = true
+			receipt, err := c.PrivateReceipt(ctx, log.TxHash)
+			if err != nil {
+				yield(nil, err)
+				return false
+			}
+			return yield(receipt, nil)
+		})
+	}
+}
+
+// Collect returns the logs of s, stopping at the first error.
+func (s LogSeq) Collect() ([]ethtypes.Log, error) {
+	var (
+		logs []ethtypes.Log
+		err  error
+	)
+	s(func(log ethtypes.Log, e error) bool {
+		if e != nil {
+			err = e
+			return false
+		}
+		logs = append(logs, log)
+		return true
+	})
+	return logs, err
+}
+
+// Collect returns the receipts of s, stopping at the first error.
+func (s ReceiptSeq) Collect() ([]*types.PrivateReceipt, error) {
+	var (
+		receipts []*types.PrivateReceipt
+		err      error
+	)
+	s(func(receipt *types.PrivateReceipt, e error) bool {
+		if e != nil {
+			err = e
+			return false
+		}
+		receipts = append(receipts, receipt)
+		return true
+	})
+	return receipts, err
+}
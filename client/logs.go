@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/bsostech/go-besu/types"
+)
+
+// BlockRange is an inclusive range of blocks, nil bounds meaning the earliest
+// and the latest block.
+type BlockRange struct {
+	From *big.Int
+	To   *big.Int
+}
+
+// PrivateLogs returns the private logs of a privacy group matching q with priv_getLogs.
+func (c *Client) PrivateLogs(ctx context.Context, privacyGroupID string, q ethereum.FilterQuery) ([]ethtypes.Log, error) {
+	var logs []ethtypes.Log
+	err := c.rpc.CallContext(ctx, &logs, "priv_getLogs", privacyGroupID, toFilterArg(q))
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// GetReceiptsByGroup returns the private receipts of the transactions of a
+// privacy group which emitted logs in blocks, in log order.
+func (c *Client) GetReceiptsByGroup(ctx context.Context, privacyGroupID string, blocks BlockRange) ([]*types.PrivateReceipt, error) {
+	logs, err := c.PrivateLogs(ctx, privacyGroupID, ethereum.FilterQuery{FromBlock: blocks.From, ToBlock: blocks.To})
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[common.Hash]bool)
+	var receipts []*types.PrivateReceipt
+	for _, log := range logs {
+		if seen[log.TxHash] {
+			continue
+		}
+		seen[log.TxHash] = true
+		receipt, err := c.PrivateReceipt(ctx, log.TxHash)
+		if err != nil {
+			return nil, err
+		}
+		receipts = append(receipts, receipt)
+	}
+	return receipts, nil
+}
+
+func toFilterArg(q ethereum.FilterQuery) map[string]interface{} {
+	arg := map[string]interface{}{
+		"fromBlock": toBlockNumArg(q.FromBlock, "earliest"),
+		"toBlock":   toBlockNumArg(q.ToBlock, "latest"),
+	}
+	if q.BlockHash != nil {
+		arg = map[string]interface{}{
+			"blockHash": *q.BlockHash,
+		}
+	}
+	if len(q.Addresses) > 0 {
+		arg["address"] = q.Addresses
+	}
+	if len(q.Topics) > 0 {
+		arg["topics"] = q.Topics
+	}
+	return arg
+}
+
+func toBlockNumArg(number *big.Int, def string) string {
+	if number == nil {
+		return def
+	}
+	return hexutil.EncodeBig(number)
+}
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// DefaultLogPageSize is the number of blocks queried per priv_getLogs call.
+const DefaultLogPageSize = 1000
+
+// LogPageOptions configure PrivateLogsPaged.
+type LogPageOptions struct {
+	PageSize    uint64 // blocks per call, DefaultLogPageSize if 0
+	MinPageSize uint64 // smallest page the size is halved down to when the node rejects a page, 1 if 0
+	// Progress is called after each page with the last block queried and the
+	// last block of the range.
+	Progress func(done, to uint64)
+}
+
+// limitMessages are parts of the errors nodes return for queries over their limits.
+var limitMessages = []string{"limit", "too many", "exceed", "range", "timeout", "timed out"}
+
+func isLimitError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, m := range limitMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
+
+type logKey struct {
+	block common.Hash
+	tx    common.Hash
+	index uint
+}
+
+// PrivateLogsPaged returns the private logs of a privacy group matching q
+// like PrivateLogs, querying the range in pages. Latest and pending bounds,
+// as well as a nil q.ToBlock, are the head block. When the node rejects a
+// page for its limits, the page size is halved. Logs repeated within a page
+// or by adjacent pages are dropped, and logs are returned in block order.
+// q.BlockHash is not supported.
+func (c *Client) PrivateLogsPaged(ctx context.Context, privacyGroupID string, q ethereum.FilterQuery, opts LogPageOptions) ([]ethtypes.Log, error) {
+	var logs []ethtypes.Log
+	err := c.pageLogs(ctx, privacyGroupID, q, opts, func(page []ethtypes.Log) bool {
+		logs = append(logs, page...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// resolveBlockNumber returns the block number of a query bound, nil, latest
+// and pending being the head block.
+func (c *Client) resolveBlockNumber(ctx context.Context, number *big.Int) (uint64, error) {
+	head := number == nil
+	if number != nil && number.IsInt64() {
+		tag := rpc.BlockNumber(number.Int64())
+		head = tag == rpc.LatestBlockNumber || tag == rpc.PendingBlockNumber
+	}
+	if head {
+		header, err := c.eth.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return 0, err
+		}
+		return header.Number.Uint64(), nil
+	}
+	if number.Sign() < 0 || !number.IsUint64() {
+		return 0, fmt.Errorf("invalid block number %v", number)
+	}
+	return number.Uint64(), nil
+}
+
+// pageLogs queries the logs matching q in pages like PrivateLogsPaged and
+// passes the deduplicated logs of each page to fn, stopping when it returns
+// false.
+func (c *Client) pageLogs(ctx context.Context, privacyGroupID string, q ethereum.FilterQuery, opts LogPageOptions, fn func([]ethtypes.Log) bool) error {
+	if q.BlockHash != nil {
+		return fmt.Errorf("blockHash not supported")
+	}
+	pageSize, minPageSize := opts.PageSize, opts.MinPageSize
+	if pageSize == 0 {
+		pageSize = DefaultLogPageSize
+	}
+	if minPageSize == 0 {
+		minPageSize = 1
+	}
+	var from uint64
+	if q.FromBlock != nil {
+		var err error
+		if from, err = c.resolveBlockNumber(ctx, q.FromBlock); err != nil {
+			return err
+		}
+	}
+	to, err := c.resolveBlockNumber(ctx, q.ToBlock)
+	if err != nil {
+		return err
+	}
+	// keys of the logs of the previous page, as nodes may return the logs of
+	// the boundary block twice
+	var prev map[logKey]bool
+	for from <= to {
+		end := from + pageSize - 1
+		if end > to || end < from {
+			end = to
+		}
+		page := q
+		page.FromBlock, page.ToBlock = new(big.Int).SetUint64(from), new(big.Int).SetUint64(end)
+		pageLogs, err := c.PrivateLogs(ctx, privacyGroupID, page)
+		if err != nil {
+			if ctx.Err() == nil && isLimitError(err) && pageSize/2 >= minPageSize {
+				pageSize /= 2
+				continue
+			}
+			return fmt.Errorf("failed to get logs of blocks %v to %v, err: %v", from, end, err)
+		}
+		seen := make(map[logKey]bool, len(pageLogs))
+		logs := pageLogs[:0]
+		for _, log := range pageLogs {
+			key := logKey{log.BlockHash, log.TxHash, log.Index}
+			if seen[key] || prev[key] {
+				continue
+			}
+			seen[key] = true
+			logs = append(logs, log)
+		}
+		prev = seen
+		if !fn(logs) {
+			return nil
+		}
+		if opts.Progress != nil {
+			opts.Progress(end, to)
+		}
+		if end == to {
+			break
+		}
+		from = end + 1
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testHead is the head block of overlappingLogsServer.
+const testHead = 9
+
+// overlappingLogsServer answers priv_getLogs with one log per block, also
+// returning the block before the range and the first log of each page twice.
+func overlappingLogsServer(t *testing.T) *Client {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage
+			Method string
+			Params []json.RawMessage
+		}
+		var filter struct {
+			FromBlock hexutil.Uint64
+			ToBlock   hexutil.Uint64
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err == nil && req.Method == "eth_getBlockByNumber" {
+			head, _ := json.Marshal(&ethtypes.Header{Number: big.NewInt(testHead), Difficulty: big.NewInt(0)})
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%s}`, req.ID, head)
+			return
+		}
+		if len(req.Params) != 2 || json.Unmarshal(req.Params[1], &filter) != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		from := uint64(filter.FromBlock)
+		if from > 0 {
+			from--
+		}
+		logs := []*ethtypes.Log{}
+		for block := from; block <= uint64(filter.ToBlock); block++ {
+			log := &ethtypes.Log{
+				BlockNumber: block,
+				BlockHash:   common.BigToHash(new(big.Int).SetUint64(block + 1)),
+				TxHash:      common.BigToHash(new(big.Int).SetUint64(block + 100)),
+				Topics:      []common.Hash{},
+			}
+			logs = append(logs, log)
+			if block == from {
+				logs = append(logs, log)
+			}
+		}
+		result, _ := json.Marshal(logs)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%s}`, req.ID, result)
+	}))
+	t.Cleanup(srv.Close)
+	c, err := NewClient(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestPrivateLogsPagedDeduplicates(t *testing.T) {
+	c := overlappingLogsServer(t)
+	for _, pageSize := range []uint64{1, 3, 10, 100} {
+		q := ethereum.FilterQuery{FromBlock: big.NewInt(0), ToBlock: big.NewInt(9)}
+		logs, err := c.PrivateLogsPaged(context.Background(), "group", q, LogPageOptions{PageSize: pageSize})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(logs) != 10 {
+			t.Fatalf("page size %v: %v logs, want 10", pageSize, len(logs))
+		}
+		for i, log := range logs {
+			if log.BlockNumber != uint64(i) {
+				t.Fatalf("page size %v: log %v of block %v", pageSize, i, log.BlockNumber)
+			}
+		}
+	}
+}
+
+func TestPrivateLogsPagedBlockTags(t *testing.T) {
+	c := overlappingLogsServer(t)
+	latest, pending := big.NewInt(int64(rpc.LatestBlockNumber)), big.NewInt(int64(rpc.PendingBlockNumber))
+	tests := []struct {
+		name     string
+		from, to *big.Int
+		logs     int
+		err      string
+	}{
+		{name: "to nil", from: big.NewInt(0), logs: testHead + 1},
+		{name: "to latest", from: big.NewInt(0), to: latest, logs: testHead + 1},
+		{name: "to pending", from: big.NewInt(5), to: pending, logs: testHead - 3},
+		{name: "from latest", from: latest, to: latest, logs: 2},
+		{name: "unknown tag", from: big.NewInt(-5), err: "invalid block number -5"},
+		{name: "too large", from: big.NewInt(0), to: new(big.Int).Lsh(big.NewInt(1), 64), err: "invalid block number"},
+	}
+	// the server also returns the logs of the block before the range
+	for _, test := range tests {
+		q := ethereum.FilterQuery{FromBlock: test.from, ToBlock: test.to}
+		logs, err := c.PrivateLogsPaged(context.Background(), "group", q, LogPageOptions{PageSize: 4})
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%v: got %v, want %q", test.name, err, test.err)
+			}
+			continue
+		}
+		if err != nil || len(logs) != test.logs {
+			t.Errorf("%v: got %v logs, %v, want %v", test.name, len(logs), err, test.logs)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/bsostech/go-besu/internal/decode"
+	"github.com/bsostech/go-besu/types"
+)
+
+// DistributeTransaction distributes a signed private transaction to the
+// enclaves of its participants with priv_distributeRawTransaction, without
+// sending a privacy marker transaction. It returns the enclave key, the
+// payload of the marker transaction the sender signs with NewMarker.
+func (c *Client) DistributeTransaction(ctx context.Context, tx *types.PrivateTransaction) ([]byte, error) {
+	if err := c.checkPolicy(ctx, tx); err != nil {
+		return nil, err
+	}
+	raw, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return nil, err
+	}
+	var rsp string
+	err = c.rpc.CallContext(ctx, &rsp, "priv_distributeRawTransaction", hexutil.Encode(raw))
+	if err != nil {
+		c.emit(Event{Type: Failed, Err: err})
+		return nil, err
+	}
+	enclaveKey, err := decode.Bytes(rsp)
+	if err != nil {
+		return nil, err
+	}
+	c.recordPolicy(ctx, tx)
+	c.emit(Event{Type: Distributed, EnclaveKey: enclaveKey})
+	return enclaveKey, nil
+}
+
+// NewMarker returns an unsigned privacy marker transaction carrying enclaveKey.
+func NewMarker(nonce uint64, enclaveKey []byte, gasLimit uint64, gasPrice *big.Int) *ethtypes.Transaction {
+	return ethtypes.NewTransaction(nonce, PrivacyPrecompileAddress, big.NewInt(0), gasLimit, gasPrice, enclaveKey)
+}
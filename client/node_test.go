@@ -0,0 +1,266 @@
+package client
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/bsostech/go-besu/internal/decode"
+	"github.com/bsostech/go-besu/types"
+)
+
+// markerState is the state of a privacy marker transaction on a fakeNode.
+type markerState int
+
+const (
+	markerMined markerState = iota // mined with a private receipt
+	markerPending
+	markerDropped        // unknown to the node
+	markerNotParticipant // mined without a private receipt
+)
+
+// fakeNode is a Besu JSON-RPC endpoint serving sends, receipts, nonces and
+// privacy groups from memory, answering batches like single calls.
+type fakeNode struct {
+	*httptest.Server
+
+	mu sync.Mutex
+	// state is the state of sent markers, markerMined by default
+	state   func(pmtHash common.Hash) markerState
+	markers map[common.Hash]markerState
+	// nonces are the sent nonces by sender and group, to catch duplicates
+	nonces     map[string]map[uint64]bool
+	duplicates []string
+	// failures is the number of requests still to fail with HTTP 500
+	failures int
+	groups   map[string]string // privacy group IDs by sorted members
+}
+
+type fakeRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+type fakeResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result"`
+	Error   *fakeError      `json:"error,omitempty"`
+}
+
+type fakeError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func newFakeNode(t testing.TB) *fakeNode {
+	n := &fakeNode{
+		markers: make(map[common.Hash]markerState),
+		nonces:  make(map[string]map[uint64]bool),
+		groups:  make(map[string]string),
+	}
+	n.Server = httptest.NewServer(http.HandlerFunc(n.serve))
+	t.Cleanup(n.Close)
+	return n
+}
+
+func newFakeClient(t testing.TB, n *fakeNode) *Client {
+	c, err := NewClient(n.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func (n *fakeNode) setState(state func(pmtHash common.Hash) markerState) {
+	n.mu.Lock()
+	n.state = state
+	n.mu.Unlock()
+}
+
+func (n *fakeNode) setMarker(pmtHash common.Hash, state markerState) {
+	n.mu.Lock()
+	n.markers[pmtHash] = state
+	n.mu.Unlock()
+}
+
+func (n *fakeNode) failRequests(count int) {
+	n.mu.Lock()
+	n.failures = count
+	n.mu.Unlock()
+}
+
+func (n *fakeNode) serve(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	n.mu.Lock()
+	if n.failures > 0 {
+		n.failures--
+		n.mu.Unlock()
+		http.Error(w, "node unavailable", http.StatusInternalServerError)
+		return
+	}
+	n.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		var reqs []fakeRequest
+		if err := json.Unmarshal(body, &reqs); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		rsps := make([]*fakeResponse, len(reqs))
+		for i := range reqs {
+			rsps[i] = n.call(&reqs[i])
+		}
+		json.NewEncoder(w).Encode(rsps)
+		return
+	}
+	var req fakeRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	json.NewEncoder(w).Encode(n.call(&req))
+}
+
+func (n *fakeNode) call(req *fakeRequest) *fakeResponse {
+	rsp := &fakeResponse{JSONRPC: "2.0", ID: req.ID}
+	result, err := n.result(req)
+	if err != nil {
+		rsp.Error = &fakeError{Code: -32000, Message: err.Error()}
+	} else {
+		rsp.Result = result
+	}
+	return rsp
+}
+
+func (n *fakeNode) result(req *fakeRequest) (interface{}, error) {
+	var arg string
+	if len(req.Params) > 0 {
+		json.Unmarshal(req.Params[0], &arg)
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	switch req.Method {
+	case "eea_sendRawTransaction":
+		return n.send(arg)
+	case "eth_getTransactionByHash":
+		marker := fakeMarker(arg)
+		switch n.marker(common.HexToHash(arg)) {
+		case markerDropped:
+			return nil, nil
+		case markerPending:
+			marker["blockHash"], marker["blockNumber"] = nil, nil
+		default:
+			marker["blockHash"], marker["blockNumber"] = common.Hash{1}.Hex(), "0x1"
+		}
+		return marker, nil
+	case "priv_getTransactionReceipt":
+		if n.marker(common.HexToHash(arg)) != markerMined {
+			return nil, nil
+		}
+		return fakeReceipt(common.HexToHash(arg)), nil
+	case "priv_getTransactionCount":
+		return "0x0", nil
+	case "priv_findPrivacyGroup":
+		var members []string
+		json.Unmarshal(req.Params[0], &members)
+		key := strings.Join(members, ",")
+		id, ok := n.groups[key]
+		if !ok {
+			id = base64.StdEncoding.EncodeToString(crypto.Keccak256([]byte(key)))
+			n.groups[key] = id
+		}
+		return []map[string]interface{}{{
+			"privacyGroupId": id,
+			"name":           "",
+			"description":    "",
+			"type":           "PANTHEON",
+			"members":        members,
+		}}, nil
+	default:
+		return nil, fmt.Errorf("method %v not supported", req.Method)
+	}
+}
+
+// send records the nonce of a raw transaction and returns its marker hash.
+func (n *fakeNode) send(raw string) (interface{}, error) {
+	b, err := decode.Bytes(raw)
+	if err != nil {
+		return nil, err
+	}
+	tx := new(types.PrivateTransaction)
+	if err := rlp.DecodeBytes(b, tx); err != nil {
+		return nil, err
+	}
+	sender, err := tx.Sender()
+	if err != nil {
+		return nil, err
+	}
+	key := sender.Hex() + "/" + base64.StdEncoding.EncodeToString(tx.PrivacyGroupID())
+	if n.nonces[key] == nil {
+		n.nonces[key] = make(map[uint64]bool)
+	}
+	if n.nonces[key][tx.Nonce()] {
+		n.duplicates = append(n.duplicates, fmt.Sprintf("%v nonce %v", key, tx.Nonce()))
+	}
+	n.nonces[key][tx.Nonce()] = true
+	pmtHash := crypto.Keccak256Hash(b)
+	n.markers[pmtHash] = markerMined
+	if n.state != nil {
+		n.markers[pmtHash] = n.state(pmtHash)
+	}
+	return pmtHash, nil
+}
+
+func (n *fakeNode) marker(pmtHash common.Hash) markerState {
+	if state, ok := n.markers[pmtHash]; ok {
+		return state
+	}
+	return markerDropped
+}
+
+func fakeReceipt(pmtHash common.Hash) map[string]interface{} {
+	return map[string]interface{}{
+		"commitmentHash":   pmtHash.Hex(),
+		"transactionHash":  pmtHash.Hex(),
+		"privateFrom":      base64.StdEncoding.EncodeToString(make([]byte, 32)),
+		"privacyGroupId":   base64.StdEncoding.EncodeToString(make([]byte, 32)),
+		"status":           "0x1",
+		"logs":             []interface{}{},
+		"logsBloom":        "0x" + strings.Repeat("00", 256),
+		"blockHash":        common.Hash{1}.Hex(),
+		"blockNumber":      "0x1",
+		"transactionIndex": "0x0",
+	}
+}
+
+// fakeMarker returns a signed privacy marker transaction of hash as returned
+// by eth_getTransactionByHash, without its block.
+func fakeMarker(hash string) map[string]interface{} {
+	key, _ := crypto.HexToECDSA("8f2a55949038a9610f50fb23b5883af3b4ecb3c3bb792cbcefbd1542c692be63")
+	to := PrivacyPrecompileAddress
+	tx, _ := ethtypes.SignTx(ethtypes.NewTransaction(0, to, big.NewInt(0), 0, big.NewInt(0), nil), ethtypes.HomesteadSigner{}, key)
+	b, _ := json.Marshal(tx)
+	var marker map[string]interface{}
+	json.Unmarshal(b, &marker)
+	marker["hash"] = hash
+	return marker
+}
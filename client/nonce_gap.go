@@ -0,0 +1,151 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/base64"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bsostech/go-besu/privacy"
+	"github.com/bsostech/go-besu/types"
+)
+
+// NoopGasLimit is the gas limit of the no-op transactions filling nonce gaps.
+const NoopGasLimit = 50000
+
+// NonceReport compares the private nonce states of an account in a group.
+type NonceReport struct {
+	Account        common.Address
+	PrivacyGroupID string
+	Local          uint64 // next nonce of the nonce manager, if LocalLoaded
+	LocalLoaded    bool
+	Chain          uint64   // next nonce on the latest private state
+	Pending        []uint64 // nonces of the account's private transactions in the pool
+	Gaps           []uint64 // nonces below the expected next nonce no transaction holds
+}
+
+// FillFunc sends a transaction with nonce to fill a gap.
+type FillFunc func(ctx context.Context, nonce uint64) error
+
+// HasGaps .
+func (r *NonceReport) HasGaps() bool {
+	return len(r.Gaps) > 0
+}
+
+// DiagnoseNonce reports the nonce gaps of account in privacyGroup: the nonces
+// from the chain nonce up to the higher of the local and the pending nonces
+// which no pending transaction holds. Transactions after a gap stay in the
+// pool until it is filled.
+func (c *Client) DiagnoseNonce(ctx context.Context, account common.Address, privacyGroup *privacy.Group) (*NonceReport, error) {
+	chain, err := c.PrivateNonce(account, privacyGroup)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := c.pendingPrivateNonces(ctx, account, privacyGroup.ID)
+	if err != nil {
+		return nil, err
+	}
+	r := &NonceReport{
+		Account:        account,
+		PrivacyGroupID: privacyGroup.ID,
+		Chain:          chain,
+		Pending:        pending,
+	}
+	r.Local, r.LocalLoaded = c.ReservedNonce(account, privacyGroup)
+	next := chain
+	if r.LocalLoaded && r.Local > next {
+		next = r.Local
+	}
+	if n := len(pending); n > 0 && pending[n-1]+1 > next {
+		next = pending[n-1] + 1
+	}
+	held := make(map[uint64]bool)
+	for _, n := range pending {
+		held[n] = true
+	}
+	for n := chain; n < next; n++ {
+		if !held[n] {
+			r.Gaps = append(r.Gaps, n)
+		}
+	}
+	return r, nil
+}
+
+// RepairNonceGaps calls fill for each gap of r, in order.
+func (c *Client) RepairNonceGaps(ctx context.Context, r *NonceReport, fill FillFunc) error {
+	for _, n := range r.Gaps {
+		if err := fill(ctx, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NoopFill returns a FillFunc sending zero value transactions without data
+// from key to itself in the privacy group, which change no private state.
+func (c *Client) NoopFill(key *ecdsa.PrivateKey, chainID *big.Int, privateFrom privacy.PublicKey, privacyGroupID string, gasPrice *big.Int) (FillFunc, error) {
+	groupID, err := base64.StdEncoding.DecodeString(privacyGroupID)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, nonce uint64) error {
+		self := crypto.PubkeyToAddress(key.PublicKey)
+		tx := types.NewPrivateTransaction(nonce, &self, big.NewInt(0), NoopGasLimit, gasPrice, nil, privateFrom, groupID)
+		signed, err := tx.SignTx(chainID, key)
+		if err != nil {
+			return err
+		}
+		_, err = c.SendTransaction(ctx, signed)
+		return err
+	}, nil
+}
+
+// pendingPrivateNonces returns the sorted nonces of the private transactions
+// of account in the group whose markers are in the pool of the node.
+func (c *Client) pendingPrivateNonces(ctx context.Context, account common.Address, privacyGroupID string) ([]uint64, error) {
+	var pool []struct {
+		Hash common.Hash `json:"hash"`
+	}
+	if err := c.rpc.CallContext(ctx, &pool, "txpool_besuTransactions"); err != nil {
+		return nil, err
+	}
+	var nonces []uint64
+	for _, entry := range pool {
+		marker, _, err := c.eth.TransactionByHash(ctx, entry.Hash)
+		if err != nil || !IsPrivacyMarker(marker.To()) {
+			continue
+		}
+		tx, err := c.PrivateTransaction(ctx, entry.Hash)
+		if err != nil {
+			continue
+		}
+		if from, err := tx.Sender(); err != nil || from != account {
+			continue
+		}
+		if c.groupIDOf(tx) != privacyGroupID {
+			continue
+		}
+		nonces = append(nonces, tx.Nonce())
+	}
+	sort.Slice(nonces, func(i, j int) bool { return nonces[i] < nonces[j] })
+	return nonces, nil
+}
+
+// groupIDOf returns the privacy group ID of tx, derived from its participants
+// for transactions sent with privateFor.
+func (c *Client) groupIDOf(tx *types.PrivateTransaction) string {
+	if id := tx.PrivacyGroupID(); id != nil {
+		return base64.StdEncoding.EncodeToString(id)
+	}
+	privateFrom := privacy.PublicKey(tx.PrivateFrom())
+	participants := []*privacy.PublicKey{&privateFrom}
+	for _, v := range tx.PrivateFor() {
+		key := privacy.PublicKey(v)
+		participants = append(participants, &key)
+	}
+	return c.FindRootPrivacyGroup(participants).ID
+}
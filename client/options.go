@@ -0,0 +1,340 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/bsostech/go-besu/labels"
+	"github.com/bsostech/go-besu/retry"
+)
+
+// Option configures NewClient.
+type Option func(*options)
+
+// Metrics observes the JSON-RPC calls of a client.
+type Metrics interface {
+	// ObserveCall is called once per HTTP request, method being "batch" for batch requests.
+	ObserveCall(method string, duration time.Duration, err error)
+}
+
+// LabeledMetrics is Metrics also observing the labels of calls made with a
+// context of labels.WithLabels, e.g. to slice latency by business flow.
+// ObserveLabeledCall is called instead of ObserveCall for labeled calls.
+type LabeledMetrics interface {
+	Metrics
+	ObserveLabeledCall(method string, l labels.Labels, duration time.Duration, err error)
+}
+
+// Logger .
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+type options struct {
+	timeout    time.Duration
+	authToken  string
+	retry      *retry.Policy
+	metrics    Metrics
+	logger     Logger
+	httpClient *http.Client
+	pool       *PoolConfig
+}
+
+// WithTimeout bounds each HTTP request, or dialing for other endpoints.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// WithAuthToken sends token as bearer token, e.g. for Besu started with JWT authentication.
+func WithAuthToken(token string) Option {
+	return func(o *options) {
+		o.authToken = token
+	}
+}
+
+// WithRetry retries requests according to p when they fail in transport, are
+// answered with HTTP 429, 502, 503 or 504, or are rate limited with JSON-RPC
+// error -32005. Batches are only retried if all their calls were rate
+// limited. Requests are replayed as is, which is safe for signed raw
+// transactions as the node rejects duplicates.
+func WithRetry(p retry.Policy) Option {
+	return func(o *options) {
+		o.retry = &p
+	}
+}
+
+// WithMetrics reports every request to m, with its labels if m is LabeledMetrics.
+func WithMetrics(m Metrics) Option {
+	return func(o *options) {
+		o.metrics = m
+	}
+}
+
+// WithLogger logs failed requests to l, with their labels.
+func WithLogger(l Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// WithHTTPClient sets the HTTP client the other options wrap the transport of.
+func WithHTTPClient(c *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = c
+	}
+}
+
+// NewClient dials url and returns a client on it. The transport is selected
+// by the scheme of url: http:// and https://, ws:// and wss://, or unix://
+// and plain paths for IPC sockets. Options other than WithTimeout need an
+// HTTP endpoint.
+func NewClient(url string, opts ...Option) (*Client, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	transport, endpoint, err := ParseEndpoint(url)
+	if err != nil {
+		return nil, err
+	}
+	if transport != TransportHTTP && (o.authToken != "" || o.retry != nil || o.metrics != nil || o.logger != nil || o.httpClient != nil || o.pool != nil) {
+		return nil, fmt.Errorf("options need an HTTP endpoint, got %v", url)
+	}
+	ctx := context.Background()
+	if o.timeout > 0 && transport != TransportHTTP {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.timeout)
+		defer cancel()
+	}
+	var c *rpc.Client
+	switch transport {
+	case TransportHTTP:
+		var httpClient *http.Client
+		if httpClient, err = o.newHTTPClient(); err == nil {
+			c, err = rpc.DialHTTPWithClient(endpoint, httpClient)
+		}
+	case TransportWS:
+		c, err = rpc.DialWebsocket(ctx, endpoint, "")
+	default:
+		c, err = rpc.DialIPC(ctx, endpoint)
+	}
+	if err != nil {
+		return nil, err
+	}
+	client := New(c)
+	client.transport = transport
+	return client, nil
+}
+
+func (o *options) newHTTPClient() (*http.Client, error) {
+	httpClient := new(http.Client)
+	if o.httpClient != nil {
+		*httpClient = *o.httpClient
+	}
+	transport := httpClient.Transport
+	if o.pool != nil {
+		pooled, err := pooledTransport(transport, *o.pool)
+		if err != nil {
+			return nil, err
+		}
+		transport = pooled
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if o.authToken != "" {
+		transport = &authTransport{base: transport, token: o.authToken}
+	}
+	if o.retry != nil {
+		transport = &retryTransport{base: &retry.Transport{Base: transport}, policy: *o.retry}
+	}
+	if o.metrics != nil || o.logger != nil {
+		transport = &observeTransport{base: transport, metrics: o.metrics, logger: o.logger}
+	}
+	httpClient.Transport = transport
+	if o.timeout > 0 {
+		httpClient.Timeout = o.timeout
+	}
+	return httpClient, nil
+}
+
+type authTransport struct {
+	base  http.RoundTripper
+	token string
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return t.base.RoundTrip(req)
+}
+
+type retryTransport struct {
+	base   http.RoundTripper
+	policy retry.Policy
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	var resp *http.Response
+	err = t.policy.Do(req.Context(), func() error {
+		attempt := req.Clone(req.Context())
+		attempt.Body = ioutil.NopCloser(bytes.NewReader(body))
+		var err error
+		resp, err = t.base.RoundTrip(attempt)
+		if err != nil {
+			resp = nil
+			return err
+		}
+		return retryableResponse(resp)
+	})
+	if resp != nil {
+		// the last response, retryable or not, is returned for the rpc client to report
+		return resp, nil
+	}
+	return nil, err
+}
+
+// retryableResponse returns an error retry.Retryable accepts if resp is a
+// 502, 503 or 504 response, or a response of calls rate limited by the node.
+// The body of such responses is buffered, so resp stays readable.
+func retryableResponse(resp *http.Response) error {
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		if _, err := bufferBody(resp); err != nil {
+			return err
+		}
+		return fmt.Errorf("%v", resp.Status)
+	case http.StatusOK:
+		body, err := bufferBody(resp)
+		if err != nil {
+			return err
+		}
+		if rateLimitedBody(body) {
+			return &retry.RateLimitError{Status: fmt.Sprintf("rate limited, error %v", retry.RateLimitCode)}
+		}
+	}
+	return nil
+}
+
+// bufferBody reads and closes the body of resp, replacing it with the read
+// bytes, which it returns.
+func bufferBody(resp *http.Response) ([]byte, error) {
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+type rpcErrorResponse struct {
+	Error *struct {
+		Code int `json:"code"`
+	} `json:"error"`
+}
+
+func (m *rpcErrorResponse) rateLimited() bool {
+	return m.Error != nil && m.Error.Code == retry.RateLimitCode
+}
+
+// rateLimitedBody reports whether a JSON-RPC response body is a rate limit
+// error, for batches whether all calls of the batch were rate limited.
+func rateLimitedBody(body []byte) bool {
+	if !bytes.Contains(body, []byte(strconv.Itoa(retry.RateLimitCode))) {
+		return false
+	}
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var msgs []rpcErrorResponse
+		if err := json.Unmarshal(body, &msgs); err != nil || len(msgs) == 0 {
+			return false
+		}
+		for i := range msgs {
+			if !msgs[i].rateLimited() {
+				return false
+			}
+		}
+		return true
+	}
+	var msg rpcErrorResponse
+	return json.Unmarshal(body, &msg) == nil && msg.rateLimited()
+}
+
+type observeTransport struct {
+	base    http.RoundTripper
+	metrics Metrics
+	logger  Logger
+}
+
+func (t *observeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	method := requestMethod(body)
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	if err == nil && resp.StatusCode >= http.StatusBadRequest {
+		err = fmt.Errorf("%v", resp.Status)
+	}
+	duration := time.Since(start)
+	l := labels.FromContext(req.Context())
+	if t.metrics != nil {
+		if lm, ok := t.metrics.(LabeledMetrics); ok && len(l) > 0 {
+			lm.ObserveLabeledCall(method, l, duration, err)
+		} else {
+			t.metrics.ObserveCall(method, duration, err)
+		}
+	}
+	if t.logger != nil && err != nil {
+		if len(l) > 0 {
+			t.logger.Printf("besu: %v [%v] failed after %v: %v", method, l, duration, err)
+		} else {
+			t.logger.Printf("besu: %v failed after %v: %v", method, duration, err)
+		}
+	}
+	if resp != nil {
+		return resp, nil
+	}
+	return nil, err
+}
+
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	return body, err
+}
+
+// requestMethod returns the method of a JSON-RPC request body.
+func requestMethod(body []byte) string {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		return "batch"
+	}
+	var msg struct {
+		Method string `json:"method"`
+	}
+	if err := json.Unmarshal(body, &msg); err != nil || msg.Method == "" {
+		return "unknown"
+	}
+	return msg.Method
+}
@@ -0,0 +1,211 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/bsostech/go-besu/labels"
+	"github.com/bsostech/go-besu/retry"
+)
+
+var testRetryPolicy = retry.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+// failingServer answers the first failures requests with fail and the others
+// with a successful web3_clientVersion response.
+func failingServer(t *testing.T, failures int32, fail func(w http.ResponseWriter, body string)) (*httptest.Server, *int32) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if atomic.AddInt32(&calls, 1) <= failures {
+			fail(w, string(body))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasPrefix(strings.TrimSpace(string(body)), "[") {
+			fmt.Fprint(w, `[{"jsonrpc":"2.0","id":1,"result":"besu"},{"jsonrpc":"2.0","id":2,"result":"besu"}]`)
+			return
+		}
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":"besu"}`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func clientVersion(t *testing.T, url string) (string, error) {
+	t.Helper()
+	c, err := NewClient(url, WithRetry(testRetryPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var version string
+	err = c.RPC().CallContext(context.Background(), &version, "web3_clientVersion")
+	return version, err
+}
+
+func TestRetryServerErrors(t *testing.T) {
+	for _, code := range []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout} {
+		srv, calls := failingServer(t, 2, func(w http.ResponseWriter, body string) {
+			w.WriteHeader(code)
+		})
+		version, err := clientVersion(t, srv.URL)
+		if err != nil || version != "besu" {
+			t.Fatalf("status %v: got %q, %v", code, version, err)
+		}
+		if n := atomic.LoadInt32(calls); n != 3 {
+			t.Fatalf("status %v: %v requests, want 3", code, n)
+		}
+	}
+}
+
+func TestRetryServerErrorsExhausted(t *testing.T) {
+	srv, calls := failingServer(t, 10, func(w http.ResponseWriter, body string) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	if _, err := clientVersion(t, srv.URL); err == nil || !strings.HasPrefix(err.Error(), "503") {
+		t.Fatalf("got %v, want 503 error", err)
+	}
+	if n := atomic.LoadInt32(calls); n != int32(testRetryPolicy.MaxAttempts) {
+		t.Fatalf("%v requests, want %v", n, testRetryPolicy.MaxAttempts)
+	}
+}
+
+func TestRetryRateLimitedCall(t *testing.T) {
+	srv, calls := failingServer(t, 1, func(w http.ResponseWriter, body string) {
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"error":{"code":-32005,"message":"rate limit exceeded"}}`)
+	})
+	version, err := clientVersion(t, srv.URL)
+	if err != nil || version != "besu" {
+		t.Fatalf("got %q, %v", version, err)
+	}
+	if n := atomic.LoadInt32(calls); n != 2 {
+		t.Fatalf("%v requests, want 2", n)
+	}
+}
+
+func TestRetryNotOtherRPCErrors(t *testing.T) {
+	srv, calls := failingServer(t, 1, func(w http.ResponseWriter, body string) {
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"execution reverted"}}`)
+	})
+	_, err := clientVersion(t, srv.URL)
+	if rpcErr, ok := err.(rpc.Error); !ok || rpcErr.ErrorCode() != -32000 {
+		t.Fatalf("got %v, want rpc error -32000", err)
+	}
+	if n := atomic.LoadInt32(calls); n != 1 {
+		t.Fatalf("%v requests, want 1", n)
+	}
+}
+
+func TestRetryBatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		requests int32
+	}{
+		{"all rate limited", `[{"jsonrpc":"2.0","id":1,"error":{"code":-32005,"message":"limit"}},{"jsonrpc":"2.0","id":2,"error":{"code":-32005,"message":"limit"}}]`, 2},
+		{"partly rate limited", `[{"jsonrpc":"2.0","id":1,"result":"besu"},{"jsonrpc":"2.0","id":2,"error":{"code":-32005,"message":"limit"}}]`, 1},
+	}
+	for _, test := range tests {
+		srv, calls := failingServer(t, 1, func(w http.ResponseWriter, body string) {
+			fmt.Fprint(w, test.response)
+		})
+		c, err := NewClient(srv.URL, WithRetry(testRetryPolicy))
+		if err != nil {
+			t.Fatal(err)
+		}
+		batch := make([]rpc.BatchElem, 2)
+		for i := range batch {
+			batch[i] = rpc.BatchElem{Method: "web3_clientVersion", Result: new(string)}
+		}
+		if err := c.RPC().BatchCallContext(context.Background(), batch); err != nil {
+			t.Fatalf("%v: %v", test.name, err)
+		}
+		if n := atomic.LoadInt32(calls); n != test.requests {
+			t.Fatalf("%v: %v requests, want %v", test.name, n, test.requests)
+		}
+	}
+}
+
+func TestRateLimitedBody(t *testing.T) {
+	tests := []struct {
+		body string
+		want bool
+	}{
+		{`{"jsonrpc":"2.0","id":1,"result":"0x1"}`, false},
+		{`{"jsonrpc":"2.0","id":1,"error":{"code":-32005,"message":"limit"}}`, true},
+		{`{"jsonrpc":"2.0","id":1,"result":"-32005"}`, false},
+		{`[{"error":{"code":-32005}},{"error":{"code":-32005}}]`, true},
+		{`[{"error":{"code":-32005}},{"result":"0x1"}]`, false},
+		{`[]`, false},
+		{`not json -32005`, false},
+	}
+	for _, test := range tests {
+		if got := rateLimitedBody([]byte(test.body)); got != test.want {
+			t.Errorf("rateLimitedBody(%s) = %v, want %v", test.body, got, test.want)
+		}
+	}
+}
+
+type recordingMetrics struct {
+	mu      sync.Mutex
+	calls   []string
+	labeled []labels.Labels
+}
+
+func (m *recordingMetrics) ObserveCall(method string, duration time.Duration, err error) {
+	m.mu.Lock()
+	m.calls = append(m.calls, method)
+	m.mu.Unlock()
+}
+
+func (m *recordingMetrics) ObserveLabeledCall(method string, l labels.Labels, duration time.Duration, err error) {
+	m.mu.Lock()
+	m.calls = append(m.calls, method)
+	m.labeled = append(m.labeled, l)
+	m.mu.Unlock()
+}
+
+type recordingLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+	l.mu.Lock()
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+	l.mu.Unlock()
+}
+
+func TestObserveLabels(t *testing.T) {
+	srv, _ := failingServer(t, 1, func(w http.ResponseWriter, body string) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	m, logger := new(recordingMetrics), new(recordingLogger)
+	c, err := NewClient(srv.URL, WithMetrics(m), WithLogger(logger))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := labels.WithLabels(context.Background(), labels.Labels{"flow": "settlement", "app": "treasury"})
+	var version string
+	if err := c.RPC().CallContext(ctx, &version, "web3_clientVersion"); err == nil {
+		t.Fatal("call to failing server succeeded")
+	}
+	if err := c.RPC().CallContext(context.Background(), &version, "web3_clientVersion"); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.calls) != 2 || len(m.labeled) != 1 || m.labeled[0]["flow"] != "settlement" {
+		t.Fatalf("calls %v, labeled %v", m.calls, m.labeled)
+	}
+	if len(logger.lines) != 1 || !strings.Contains(logger.lines[0], "web3_clientVersion [app=treasury,flow=settlement] failed") {
+		t.Fatalf("logged %q", logger.lines)
+	}
+}
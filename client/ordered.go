@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/bsostech/go-besu/types"
+)
+
+// ErrPredecessorFailed means a transaction was not sent because one enqueued
+// before it on the same OrderedSender failed.
+var ErrPredecessorFailed = errors.New("previous transaction failed")
+
+// OrderedSender sends the private transactions of a privacy group one at a
+// time, in the order they are enqueued: a transaction is only sent once the
+// marker of the previous one is mined and its private receipt is available.
+// Transactions have to be signed with consecutive nonces, use one sender per
+// account and privacy group.
+type OrderedSender struct {
+	client *Client
+
+	mu      sync.Mutex
+	queue   []*orderedTx
+	running bool
+}
+
+type orderedTx struct {
+	ctx    context.Context
+	tx     *types.PrivateTransaction
+	handle *TxHandle
+}
+
+// NewOrderedSender .
+func (c *Client) NewOrderedSender() *OrderedSender {
+	return &OrderedSender{client: c}
+}
+
+// Enqueue queues signed tx and returns a handle resolving to its private
+// receipt, whose Hash is only set once Done. If sending a transaction or
+// waiting for its marker fails, the transactions queued after it fail with
+// ErrPredecessorFailed, as their nonces can not be mined before the failed
+// one is replaced. Reverted transactions do not stop the queue.
+func (s *OrderedSender) Enqueue(ctx context.Context, tx *types.PrivateTransaction) *TxHandle {
+	h := &TxHandle{
+		done: make(chan struct{}),
+	}
+	if !s.client.drainer.track(h) {
+		h.resolve(nil, ErrDraining)
+		return h
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queue = append(s.queue, &orderedTx{ctx: ctx, tx: tx, handle: h})
+	if !s.running {
+		s.running = true
+		go s.loop()
+	}
+	return h
+}
+
+// Len returns the number of queued transactions, including the one in flight.
+func (s *OrderedSender) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.queue)
+}
+
+func (s *OrderedSender) loop() {
+	for {
+		s.mu.Lock()
+		if len(s.queue) == 0 {
+			s.running = false
+			s.mu.Unlock()
+			return
+		}
+		next := s.queue[0]
+		s.mu.Unlock()
+
+		err := s.send(next)
+
+		s.mu.Lock()
+		s.queue = s.queue[1:]
+		var failed []*orderedTx
+		if err != nil {
+			failed, s.queue = s.queue, nil
+		}
+		s.mu.Unlock()
+		for _, o := range failed {
+			o.handle.resolve(nil, fmt.Errorf("%w: %v", ErrPredecessorFailed, err))
+		}
+	}
+}
+
+// send sends o and waits for its private receipt, returning an error if the
+// transactions after it can not be sent.
+func (s *OrderedSender) send(o *orderedTx) error {
+	pmtHash, err := s.client.SendTransaction(o.ctx, o.tx)
+	if err != nil {
+		o.handle.resolve(nil, err)
+		return err
+	}
+	o.handle.setHash(pmtHash)
+	receipt, err := s.client.WaitForReceipt(o.ctx, pmtHash)
+	o.handle.resolve(receipt, err)
+	if err != nil && err != ErrNotParticipant {
+		return err
+	}
+	return nil
+}
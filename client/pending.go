@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/bsostech/go-besu/privacy"
+)
+
+// ErrNoEnclave means the client has no enclave, see SetEnclave.
+var ErrNoEnclave = errors.New("enclave not set")
+
+// IncomingPrivateTx is a pending privacy marker transaction whose payload the
+// enclave resolves for the subscribed key.
+type IncomingPrivateTx struct {
+	Hash       common.Hash
+	Marker     *ethtypes.Transaction
+	EnclaveKey []byte
+	Payload    []byte // RLP encoded private transaction
+}
+
+// PendingMarkerSubscription delivers incoming private transactions before they are mined.
+type PendingMarkerSubscription struct {
+	client *Client
+	self   privacy.PublicKey
+	sub    *rpc.ClientSubscription
+	in     chan common.Hash
+	txs    chan IncomingPrivateTx
+	errc   chan error
+	quit   chan struct{}
+	once   sync.Once
+}
+
+// SubscribePendingMarkers watches pending transactions with the
+// newPendingTransactions subscription and delivers the privacy marker
+// transactions whose payload the enclave of the client resolves for self,
+// i.e. the private transactions self participates in. It needs an enclave
+// serving the client API, see SetEnclave, and returns
+// ErrSubscriptionsUnsupported on HTTP clients. Markers of plugin privacy
+// are not resolved.
+func (c *Client) SubscribePendingMarkers(ctx context.Context, self privacy.PublicKey, bufferSize int) (*PendingMarkerSubscription, error) {
+	if !c.SupportsSubscriptions() {
+		return nil, ErrSubscriptionsUnsupported
+	}
+	if c.enclave == nil {
+		return nil, ErrNoEnclave
+	}
+	if bufferSize <= 0 {
+		bufferSize = DefaultSubscriptionOptions.BufferSize
+	}
+	s := &PendingMarkerSubscription{
+		client: c,
+		self:   self,
+		in:     make(chan common.Hash),
+		txs:    make(chan IncomingPrivateTx, bufferSize),
+		errc:   make(chan error, 1),
+		quit:   make(chan struct{}),
+	}
+	sub, err := c.rpc.EthSubscribe(ctx, s.in, "newPendingTransactions")
+	if err != nil {
+		return nil, err
+	}
+	s.sub = sub
+	go s.forward()
+	return s, nil
+}
+
+// Transactions returns the channel incoming private transactions are delivered on.
+func (s *PendingMarkerSubscription) Transactions() <-chan IncomingPrivateTx {
+	return s.txs
+}
+
+// Err returns the channel the error ending the subscription is sent on.
+func (s *PendingMarkerSubscription) Err() <-chan error {
+	return s.errc
+}
+
+// Unsubscribe ends the subscription.
+func (s *PendingMarkerSubscription) Unsubscribe() {
+	s.once.Do(func() {
+		close(s.quit)
+		s.sub.Unsubscribe()
+	})
+}
+
+func (s *PendingMarkerSubscription) forward() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-s.quit
+		cancel()
+	}()
+	for {
+		select {
+		case <-s.quit:
+			return
+		case err := <-s.sub.Err():
+			if err != nil {
+				s.errc <- err
+			}
+			return
+		case hash := <-s.in:
+			incoming, ok := s.resolve(ctx, hash)
+			if !ok {
+				continue
+			}
+			select {
+			case s.txs <- incoming:
+			case <-s.quit:
+				return
+			}
+		}
+	}
+}
+
+// resolve returns the incoming private transaction of hash, false if hash is
+// not a marker or its payload is not for self.
+func (s *PendingMarkerSubscription) resolve(ctx context.Context, hash common.Hash) (IncomingPrivateTx, bool) {
+	tx, _, err := s.client.eth.TransactionByHash(ctx, hash)
+	if err != nil || !IsPrivacyMarker(tx.To()) || *tx.To() == PluginPrivacyPrecompileAddress {
+		return IncomingPrivateTx{}, false
+	}
+	payload, err := s.client.enclave.Receive(ctx, tx.Data(), s.self)
+	if err != nil {
+		return IncomingPrivateTx{}, false
+	}
+	return IncomingPrivateTx{
+		Hash:       hash,
+		Marker:     tx,
+		EnclaveKey: tx.Data(),
+		Payload:    payload,
+	}, true
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bsostech/go-besu/plugin"
+	"github.com/bsostech/go-besu/types"
+)
+
+var (
+	// PluginPrivacyPrecompileAddress is the address privacy marker
+	// transactions of plugin privacy are sent to.
+	PluginPrivacyPrecompileAddress = common.HexToAddress("0x000000000000000000000000000000000000007a")
+	// ErrNoPayloadProcessor .
+	ErrNoPayloadProcessor = errors.New("no payload processor set")
+)
+
+// SetPayloadProcessor switches the client to plugin privacy: private
+// transactions are read from the payload of their privacy marker transaction
+// with p instead of priv_getPrivateTransaction.
+func (c *Client) SetPayloadProcessor(p plugin.PayloadProcessor) {
+	c.payloads = p
+}
+
+// SendMarkerTransaction sends tx as the payload produced by the payload
+// processor of the client, in a privacy marker transaction signed with key.
+// The marker transaction takes the gas limit and gas price of tx. It is used
+// when the marker is signed by the sender rather than by the node.
+func (c *Client) SendMarkerTransaction(ctx context.Context, tx *types.PrivateTransaction, privacyUserID string, key *ecdsa.PrivateKey, chainID *big.Int) (common.Hash, error) {
+	if c.payloads == nil {
+		return common.Hash{}, ErrNoPayloadProcessor
+	}
+	if err := c.checkPolicy(ctx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	payload, err := c.payloads.MarkerPayload(ctx, tx, privacyUserID)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	nonce, err := c.eth.PendingNonceAt(ctx, crypto.PubkeyToAddress(key.PublicKey))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	marker := ethtypes.NewTransaction(nonce, PluginPrivacyPrecompileAddress, big.NewInt(0), tx.Gas(), tx.GasPrice(), payload)
+	signed, err := ethtypes.SignTx(marker, ethtypes.NewEIP155Signer(chainID), key)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := c.eth.SendTransaction(ctx, signed); err != nil {
+		return common.Hash{}, err
+	}
+	c.recordPolicy(ctx, tx)
+	return signed.Hash(), nil
+}
+
+// pluginPrivateTransaction reads the private transaction of a privacy marker
+// transaction with the payload processor.
+func (c *Client) pluginPrivateTransaction(ctx context.Context, pmtHash common.Hash) (*types.PrivateTransaction, error) {
+	marker, _, err := c.eth.TransactionByHash(ctx, pmtHash)
+	if err != nil {
+		return nil, err
+	}
+	if !IsPrivacyMarker(marker.To()) {
+		return nil, ethereum.NotFound
+	}
+	return c.payloads.PrivateTransaction(ctx, marker)
+}
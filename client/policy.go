@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+
+	"github.com/bsostech/go-besu/policy"
+	"github.com/bsostech/go-besu/types"
+)
+
+// SetPolicy sets the policy every transaction is checked against before it
+// is sent or distributed, nil for none. Denied transactions fail with an
+// error wrapping policy.ErrDenied. A policy.Recorder records transactions
+// once sent.
+func (c *Client) SetPolicy(p policy.Policy) {
+	c.policy = p
+}
+
+// ApplyPolicy checks the unsigned tx against the policy of the client,
+// returning the transaction to sign, modified if the policy decided so.
+func (c *Client) ApplyPolicy(ctx context.Context, tx *types.PrivateTransaction) (*types.PrivateTransaction, error) {
+	if c.policy == nil {
+		return tx, nil
+	}
+	return policy.Apply(ctx, c.policy, tx, false)
+}
+
+// checkPolicy checks the signed tx against the policy of the client.
+func (c *Client) checkPolicy(ctx context.Context, tx *types.PrivateTransaction) error {
+	if c.policy == nil {
+		return nil
+	}
+	_, err := policy.Apply(ctx, c.policy, tx, true)
+	if err != nil {
+		c.emit(Event{Type: Failed, Err: err})
+	}
+	return err
+}
+
+// recordPolicy records the sent tx with the policy of the client, e.g. so
+// that quotas only count sent transactions.
+func (c *Client) recordPolicy(ctx context.Context, tx *types.PrivateTransaction) {
+	if r, ok := c.policy.(policy.Recorder); ok {
+		r.Record(ctx, tx)
+	}
+}
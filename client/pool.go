@@ -0,0 +1,62 @@
+package client
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// PoolConfig tunes the connection pool of the HTTP transport. Go's default
+// transport keeps only 2 idle connections per host, so clients sending many
+// concurrent requests to one node, e.g. indexers, open and close connections
+// continuously. Set MaxIdleConnsPerHost to the expected concurrency, and
+// MaxConnsPerHost to bound the load on the node.
+type PoolConfig struct {
+	MaxIdleConns        int           // idle connections across hosts, 0 for no limit
+	MaxIdleConnsPerHost int           // idle connections per host, http.DefaultMaxIdleConnsPerHost if 0
+	MaxConnsPerHost     int           // connections per host, 0 for no limit
+	IdleConnTimeout     time.Duration // how long idle connections are kept, 0 for no limit
+	KeepAlive           time.Duration // TCP keep-alive period, 15s if 0, negative to disable
+	DisableKeepAlives   bool          // one connection per request
+}
+
+// DefaultPoolConfig suits clients with up to 100 concurrent requests to one node.
+var DefaultPoolConfig = PoolConfig{
+	MaxIdleConns:        100,
+	MaxIdleConnsPerHost: 100,
+	IdleConnTimeout:     90 * time.Second,
+	KeepAlive:           30 * time.Second,
+}
+
+// WithPool tunes the connection pool of the HTTP transport. The transport of
+// the client set by WithHTTPClient, if any, must be an *http.Transport.
+func WithPool(cfg PoolConfig) Option {
+	return func(o *options) {
+		o.pool = &cfg
+	}
+}
+
+// pooledTransport returns a copy of base, http.DefaultTransport if nil, with
+// the pool settings of cfg.
+func pooledTransport(base http.RoundTripper, cfg PoolConfig) (*http.Transport, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t, ok := base.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("pool needs an *http.Transport, got %T", base)
+	}
+	t = t.Clone()
+	t.MaxIdleConns = cfg.MaxIdleConns
+	t.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	t.MaxConnsPerHost = cfg.MaxConnsPerHost
+	t.IdleConnTimeout = cfg.IdleConnTimeout
+	t.DisableKeepAlives = cfg.DisableKeepAlives
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: cfg.KeepAlive,
+	}
+	t.DialContext = dialer.DialContext
+	return t, nil
+}
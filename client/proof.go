@@ -0,0 +1,127 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+
+	"github.com/bsostech/go-besu/types"
+)
+
+// InclusionProof proves a privacy marker transaction is in the transactions
+// trie of its block, packaged with the private receipt it anchors.
+type InclusionProof struct {
+	BlockHash      common.Hash           `json:"blockHash"`
+	BlockNumber    uint64                `json:"blockNumber"`
+	TxRoot         common.Hash           `json:"transactionsRoot"`
+	Index          uint64                `json:"transactionIndex"`
+	Transaction    hexutil.Bytes         `json:"transaction"` // RLP encoded marker transaction
+	Proof          []hexutil.Bytes       `json:"proof"`       // trie nodes from root to leaf
+	PrivateReceipt *types.PrivateReceipt `json:"privateReceipt"`
+}
+
+// proofNodes collects the nodes written by trie.Prove.
+type proofNodes []hexutil.Bytes
+
+// BuildInclusionProof builds the inclusion proof of a mined privacy marker transaction.
+func (c *Client) BuildInclusionProof(ctx context.Context, pmtHash common.Hash) (*InclusionProof, error) {
+	receipt, err := c.eth.TransactionReceipt(ctx, pmtHash)
+	if err != nil {
+		return nil, err
+	}
+	block, err := c.eth.BlockByHash(ctx, receipt.BlockHash)
+	if err != nil {
+		return nil, err
+	}
+	tr, err := trie.New(common.Hash{}, trie.NewDatabase(memorydb.New()))
+	if err != nil {
+		return nil, err
+	}
+	var rawTx []byte
+	for i, tx := range block.Transactions() {
+		key, err := rlp.EncodeToBytes(uint(i))
+		if err != nil {
+			return nil, err
+		}
+		value, err := rlp.EncodeToBytes(tx)
+		if err != nil {
+			return nil, err
+		}
+		tr.Update(key, value)
+		if uint(i) == receipt.TransactionIndex {
+			rawTx = value
+		}
+	}
+	if tr.Hash() != block.TxHash() {
+		return nil, fmt.Errorf("transactions root mismatch: got %v, want %v", tr.Hash().Hex(), block.TxHash().Hex())
+	}
+	if rawTx == nil {
+		return nil, fmt.Errorf("transaction index %v not found in block %v", receipt.TransactionIndex, block.Hash().Hex())
+	}
+	key, err := rlp.EncodeToBytes(receipt.TransactionIndex)
+	if err != nil {
+		return nil, err
+	}
+	var nodes proofNodes
+	if err := tr.Prove(key, 0, &nodes); err != nil {
+		return nil, err
+	}
+	privateReceipt, err := c.PrivateReceipt(ctx, pmtHash)
+	if err != nil {
+		return nil, err
+	}
+	return &InclusionProof{
+		BlockHash:      block.Hash(),
+		BlockNumber:    block.NumberU64(),
+		TxRoot:         block.TxHash(),
+		Index:          uint64(receipt.TransactionIndex),
+		Transaction:    rawTx,
+		Proof:          nodes,
+		PrivateReceipt: privateReceipt,
+	}, nil
+}
+
+// Verify checks that the proof proves Transaction at Index under TxRoot, and
+// that PrivateReceipt is anchored by Transaction. It does not check TxRoot
+// belongs to a canonical block.
+func (p *InclusionProof) Verify() error {
+	db := memorydb.New()
+	for _, node := range p.Proof {
+		if err := db.Put(crypto.Keccak256(node), node); err != nil {
+			return err
+		}
+	}
+	key, err := rlp.EncodeToBytes(p.Index)
+	if err != nil {
+		return err
+	}
+	value, _, err := trie.VerifyProof(p.TxRoot, key, db)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(value, p.Transaction) {
+		return fmt.Errorf("proof does not prove the transaction")
+	}
+	if p.PrivateReceipt != nil && p.PrivateReceipt.CommitmentHash != crypto.Keccak256Hash(p.Transaction) {
+		return fmt.Errorf("private receipt is not anchored by the transaction")
+	}
+	return nil
+}
+
+// Put implements ethdb.KeyValueWriter.
+func (n *proofNodes) Put(key []byte, value []byte) error {
+	*n = append(*n, common.CopyBytes(value))
+	return nil
+}
+
+// Delete implements ethdb.KeyValueWriter.
+func (n *proofNodes) Delete(key []byte) error {
+	return fmt.Errorf("not supported")
+}
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+)
+
+// ErrNoMatch means no block of the searched range satisfies the predicate.
+var ErrNoMatch = errors.New("no block matches")
+
+// BlockPredicate reports whether the private state at block satisfies a
+// condition. Searched predicates have to be monotonic: once true at a
+// block, true at all later blocks, e.g. a balance exceeding a threshold when
+// balances only grow.
+type BlockPredicate func(ctx context.Context, block *big.Int) (bool, error)
+
+// FindFirstBlockWhere binary searches blocks for the first block at which
+// pred holds for the private state of a privacy group, evaluating pred at
+// O(log n) blocks. A nil blocks.From is the earliest block with private
+// state, a nil blocks.To the latest block. It returns ErrNoMatch if pred does
+// not hold at the end of the range, and *StatePrunedError if pred reads
+// pruned state.
+func (c *Client) FindFirstBlockWhere(ctx context.Context, privacyGroupID string, blocks BlockRange, pred BlockPredicate) (uint64, error) {
+	var lo, hi uint64
+	if blocks.To != nil {
+		hi = blocks.To.Uint64()
+	} else {
+		header, err := c.eth.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return 0, err
+		}
+		hi = header.Number.Uint64()
+	}
+	if blocks.From != nil {
+		lo = blocks.From.Uint64()
+	} else {
+		earliest, err := c.EarliestPrivateState(ctx, privacyGroupID, 0)
+		if err != nil {
+			return 0, err
+		}
+		lo = earliest
+	}
+	if lo > hi {
+		return 0, fmt.Errorf("invalid block range %v to %v", lo, hi)
+	}
+	ok, err := pred(ctx, new(big.Int).SetUint64(hi))
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, ErrNoMatch
+	}
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		ok, err := pred(ctx, new(big.Int).SetUint64(mid))
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo, nil
+}
+
+// CallPredicate returns the predicate executing msg with priv_call against
+// the private state of a privacy group and passing the output to cond.
+func (c *Client) CallPredicate(privacyGroupID string, msg ethereum.CallMsg, cond func(output []byte) (bool, error)) BlockPredicate {
+	return func(ctx context.Context, block *big.Int) (bool, error) {
+		output, err := c.PrivateCallAt(ctx, privacyGroupID, msg, block)
+		if err != nil {
+			return false, err
+		}
+		return cond(output)
+	}
+}
+
+// UintAtLeast returns the condition of CallPredicate which holds if the
+// output, an ABI encoded uint256, is at least threshold.
+func UintAtLeast(threshold *big.Int) func(output []byte) (bool, error) {
+	return func(output []byte) (bool, error) {
+		if len(output) < 32 {
+			return false, fmt.Errorf("output of %v bytes is not a uint256", len(output))
+		}
+		return new(big.Int).SetBytes(output[:32]).Cmp(threshold) >= 0, nil
+	}
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/bsostech/go-besu/labels"
+	"github.com/bsostech/go-besu/types"
+)
+
+// DefaultPollInterval is the interval receipts are polled at.
+const DefaultPollInterval = time.Second
+
+// SetLabelStore sets the store the labels of sends are recorded in, see labels.WithLabels.
+func (c *Client) SetLabelStore(s labels.Store) {
+	c.labels = s
+}
+
+// SendTransaction sends a signed private transaction with eea_sendRawTransaction
+// and returns the hash of its privacy marker transaction. The labels of ctx are
+// recorded in the label store of the client, if any.
+func (c *Client) SendTransaction(ctx context.Context, tx *types.PrivateTransaction) (common.Hash, error) {
+	if err := c.checkPolicy(ctx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	raw, err := tx.EncodeHex()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	var pmtHash common.Hash
+	err = c.rpc.CallContext(ctx, &pmtHash, "eea_sendRawTransaction", raw)
+	if err != nil {
+		c.emit(Event{Type: Failed, Err: err})
+		return common.Hash{}, err
+	}
+	c.recordPolicy(ctx, tx)
+	c.emit(Event{Type: Submitted, Hash: pmtHash})
+	if l := labels.FromContext(ctx); c.labels != nil && len(l) > 0 {
+		c.labels.Put(pmtHash, l)
+	}
+	return pmtHash, nil
+}
+
+// WaitForReceipt polls the private receipt of pmtHash until it is available or
+// ctx is done. It returns ErrNotParticipant if the transaction is mined but
+// the node has no receipt.
+func (c *Client) WaitForReceipt(ctx context.Context, pmtHash common.Hash) (*types.PrivateReceipt, error) {
+	ticker := c.clock.NewTicker(DefaultPollInterval)
+	defer ticker.Stop()
+	for {
+		receipt, err := c.PrivateReceipt(ctx, pmtHash)
+		if err != ErrPending && err != ErrPMTNotFound {
+			c.emitReceipt(pmtHash, receipt, err)
+			return receipt, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C():
+		}
+	}
+}
+
+// WaitMined polls the receipt of the public transaction hash until it is
+// mined or ctx is done.
+func (c *Client) WaitMined(ctx context.Context, hash common.Hash) (*ethtypes.Receipt, error) {
+	ticker := c.clock.NewTicker(DefaultPollInterval)
+	defer ticker.Stop()
+	for {
+		receipt, err := c.eth.TransactionReceipt(ctx, hash)
+		if err != ethereum.NotFound {
+			return receipt, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C():
+		}
+	}
+}
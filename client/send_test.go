@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bsostech/go-besu/clock"
+	"github.com/bsostech/go-besu/policy"
+)
+
+func TestWaitForReceipt(t *testing.T) {
+	node := newFakeNode(t)
+	c := newFakeClient(t, node)
+	clk := clock.NewFake(time.Unix(0, 0))
+	c.SetClock(clk)
+	key, _ := crypto.GenerateKey()
+	pmtHash, err := c.SendTransaction(context.Background(), signedGroupTx(t, key, 0, make([]byte, 32)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	node.setMarker(pmtHash, markerPending)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		receipt, err := c.WaitForReceipt(ctx, pmtHash)
+		if err == nil && receipt.TxHash != pmtHash {
+			t.Errorf("receipt of %v, want %v", receipt.TxHash.Hex(), pmtHash.Hex())
+		}
+		done <- err
+	}()
+	// polls on each tick while the marker is pending
+	for i := 0; i < 3; i++ {
+		clk.BlockUntil(1)
+		time.Sleep(10 * time.Millisecond)
+		select {
+		case err := <-done:
+			t.Fatalf("returned %v while pending", err)
+		default:
+		}
+		clk.Advance(DefaultPollInterval)
+	}
+	node.setMarker(pmtHash, markerMined)
+	clk.Advance(DefaultPollInterval)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("receipt not polled after the marker was mined")
+	}
+}
+
+func TestWaitForReceiptContext(t *testing.T) {
+	node := newFakeNode(t)
+	node.setState(func(common.Hash) markerState { return markerPending })
+	c := newFakeClient(t, node)
+	clk := clock.NewFake(time.Unix(0, 0))
+	c.SetClock(clk)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.WaitForReceipt(ctx, common.Hash{1})
+		done <- err
+	}()
+	clk.BlockUntil(1)
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestSendRecordsQuota(t *testing.T) {
+	node := newFakeNode(t)
+	c := newFakeClient(t, node)
+	q := policy.NewQuota(time.Hour, 100000, 0)
+	c.SetPolicy(q)
+	key, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	node.failRequests(1)
+	if _, err := c.SendTransaction(context.Background(), signedGroupTx(t, key, 0, make([]byte, 32))); err == nil {
+		t.Fatal("send to a failing node succeeded")
+	}
+	if used := q.Usage(policy.ScopeAccount, sender); used != 0 {
+		t.Fatalf("%v gas counted for a failed send", used)
+	}
+	if _, err := c.SendTransaction(context.Background(), signedGroupTx(t, key, 0, make([]byte, 32))); err != nil {
+		t.Fatal(err)
+	}
+	if used := q.Usage(policy.ScopeAccount, sender); used != 100000 {
+		t.Fatalf("%v gas counted for a sent transaction, want 100000", used)
+	}
+}
@@ -0,0 +1,178 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bsostech/go-besu/privacy"
+	"github.com/bsostech/go-besu/types"
+)
+
+// Session holds the defaults of the private transactions of one account:
+// signing key, privateFrom, recipients and gas settings, so calls only pass
+// the recipient and payload. Sessions are values, With methods return
+// modified copies.
+type Session struct {
+	Profile  types.NetworkProfile
+	Ordering types.ParticipantsOrdering
+
+	client      *Client
+	key         *ecdsa.PrivateKey
+	account     common.Address
+	chainID     *big.Int
+	privateFrom privacy.PublicKey
+	group       *privacy.Group      // set by WithGroup
+	privateFor  []privacy.PublicKey // set by WithPrivateFor
+}
+
+// NewSession returns a session sending with key from privateFrom, with the
+// defaults of types.FreeGasNetwork. A group or counterparties have to be set
+// before sending.
+func (c *Client) NewSession(key *ecdsa.PrivateKey, chainID *big.Int, privateFrom privacy.PublicKey) *Session {
+	return &Session{
+		Profile:     types.FreeGasNetwork,
+		client:      c,
+		key:         key,
+		account:     crypto.PubkeyToAddress(key.PublicKey),
+		chainID:     chainID,
+		privateFrom: privateFrom,
+	}
+}
+
+// WithGroup returns a copy of s sending to group.
+func (s *Session) WithGroup(group *privacy.Group) *Session {
+	cpy := *s
+	cpy.group, cpy.privateFor = group, nil
+	return &cpy
+}
+
+// WithPrivateFor returns a copy of s sending to counterparties with privateFor.
+func (s *Session) WithPrivateFor(counterparties ...privacy.PublicKey) *Session {
+	cpy := *s
+	cpy.group, cpy.privateFor = nil, append([]privacy.PublicKey(nil), counterparties...)
+	return &cpy
+}
+
+// WithProfile returns a copy of s with other gas settings.
+func (s *Session) WithProfile(p types.NetworkProfile) *Session {
+	cpy := *s
+	cpy.Profile = p
+	return &cpy
+}
+
+// Client returns the client the session sends with.
+func (s *Session) Client() *Client {
+	return s.client
+}
+
+// Account returns the sending account.
+func (s *Session) Account() common.Address {
+	return s.account
+}
+
+// Group returns the privacy group sent to, the root group of the
+// counterparties if sending with privateFor.
+func (s *Session) Group() (*privacy.Group, error) {
+	if s.group != nil {
+		return s.group, nil
+	}
+	if len(s.privateFor) == 0 {
+		return nil, fmt.Errorf("group or privateFor not set")
+	}
+	participants := []*privacy.PublicKey{&s.privateFrom}
+	for i := range s.privateFor {
+		participants = append(participants, &s.privateFor[i])
+	}
+	return s.client.RootPrivacyGroup(participants)
+}
+
+// Transaction returns the unsigned transaction with nonce to to, a contract
+// creation if nil, with the defaults of s.
+func (s *Session) Transaction(nonce uint64, to *common.Address, data []byte) (*types.PrivateTransaction, error) {
+	if s.group != nil {
+		groupID, err := base64.StdEncoding.DecodeString(s.group.ID)
+		if err != nil {
+			return nil, err
+		}
+		return s.Profile.NewPrivateTransaction(nonce, to, nil, data, s.privateFrom, groupID), nil
+	}
+	if len(s.privateFor) == 0 {
+		return nil, fmt.Errorf("group or privateFor not set")
+	}
+	privateFor := make([][]byte, len(s.privateFor))
+	for i := range s.privateFor {
+		privateFor[i] = s.privateFor[i]
+	}
+	tx := s.Profile.NewTransaction(nonce, to, nil, data, s.privateFrom, privateFor)
+	return tx.WithParticipantsOrdering(s.Ordering), nil
+}
+
+// Send signs and sends a transaction to to with the next nonce of the
+// session account, returning the hash of its privacy marker transaction.
+func (s *Session) Send(ctx context.Context, to *common.Address, data []byte) (common.Hash, error) {
+	group, err := s.Group()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	nonce, err := s.client.NextNonce(s.account, group)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	tx, err := s.Transaction(nonce, to, data)
+	if err == nil {
+		tx, err = s.client.ApplyPolicy(ctx, tx)
+	}
+	if err == nil {
+		tx, err = tx.SignTx(s.chainID, s.key)
+	}
+	var pmtHash common.Hash
+	if err == nil {
+		pmtHash, err = s.client.SendTransaction(ctx, tx)
+	}
+	if err != nil {
+		s.client.ResetNonce(s.account, group)
+		return common.Hash{}, err
+	}
+	return pmtHash, nil
+}
+
+// SendAndWait sends like Send and waits for the private receipt.
+func (s *Session) SendAndWait(ctx context.Context, to *common.Address, data []byte) (*types.PrivateReceipt, error) {
+	pmtHash, err := s.Send(ctx, to, data)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.WaitForReceipt(ctx, pmtHash)
+}
+
+// SendPublic signs and sends a public transaction from the session account
+// with the gas settings of the session, returning its hash. It uses the
+// pending public nonce of the account, which is separate from its private
+// nonces.
+func (s *Session) SendPublic(ctx context.Context, to *common.Address, data []byte) (common.Hash, error) {
+	nonce, err := s.client.eth.PendingNonceAt(ctx, s.account)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	var tx *ethtypes.Transaction
+	if to == nil {
+		tx = ethtypes.NewContractCreation(nonce, big.NewInt(0), s.Profile.GasLimit, s.Profile.GasPrice, data)
+	} else {
+		tx = ethtypes.NewTransaction(nonce, *to, big.NewInt(0), s.Profile.GasLimit, s.Profile.GasPrice, data)
+	}
+	signed, err := ethtypes.SignTx(tx, ethtypes.NewEIP155Signer(s.chainID), s.key)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := s.client.eth.SendTransaction(ctx, signed); err != nil {
+		return common.Hash{}, err
+	}
+	return signed.Hash(), nil
+}
@@ -0,0 +1,180 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ErrStateDiffUnsupported means the node can not trace private calls.
+var ErrStateDiffUnsupported = errors.New("private state diff not supported by the node")
+
+// StateDiffMethod is the method tracing a private call with the stateDiff
+// trace option, taking the privacy group ID, the call, the trace options and
+// the block, like trace_call does for public calls.
+var StateDiffMethod = "priv_traceCall"
+
+// Simulation is the outcome of a simulated private call.
+type Simulation struct {
+	Output  []byte
+	Changes map[common.Address]*AccountDiff // nil if the node does not support state diffs
+}
+
+// AccountDiff are the changes of an account.
+type AccountDiff struct {
+	Storage map[common.Hash]StorageChange
+	Code    *BytesChange // nil if unchanged
+	Created bool
+	Deleted bool
+}
+
+// StorageChange is the change of a storage slot, From being zero for new slots.
+type StorageChange struct {
+	From common.Hash
+	To   common.Hash
+}
+
+// BytesChange .
+type BytesChange struct {
+	From []byte
+	To   []byte
+}
+
+// SimulateWithStateDiff executes msg against the latest private state of a
+// privacy group without committing it, returning its output and, when the
+// node supports tracing private calls, the storage changes it would make.
+// If the node does not, the output is returned with ErrStateDiffUnsupported.
+func (c *Client) SimulateWithStateDiff(ctx context.Context, privacyGroupID string, msg ethereum.CallMsg) (*Simulation, error) {
+	output, err := c.PrivateCallAt(ctx, privacyGroupID, msg, nil)
+	if err != nil {
+		return nil, err
+	}
+	sim := &Simulation{Output: output}
+	arg := map[string]interface{}{
+		"from": msg.From,
+		"to":   msg.To,
+	}
+	if len(msg.Data) > 0 {
+		arg["data"] = hexutil.Bytes(msg.Data)
+	}
+	var rsp struct {
+		StateDiff map[common.Address]map[string]json.RawMessage `json:"stateDiff"`
+	}
+	err = c.rpc.CallContext(ctx, &rsp, StateDiffMethod, privacyGroupID, arg, []string{"stateDiff"}, "latest")
+	if err != nil {
+		if isMethodNotFound(err) {
+			return sim, ErrStateDiffUnsupported
+		}
+		return sim, err
+	}
+	sim.Changes = make(map[common.Address]*AccountDiff, len(rsp.StateDiff))
+	for addr, fields := range rsp.StateDiff {
+		diff, err := decodeAccountDiff(fields)
+		if err != nil {
+			return sim, err
+		}
+		sim.Changes[addr] = diff
+	}
+	return sim, nil
+}
+
+func isMethodNotFound(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "method not found") || strings.Contains(msg, "method not enabled") ||
+		strings.Contains(msg, "does not exist")
+}
+
+// decodeAccountDiff decodes the stateDiff of an account in the trace_call
+// format, a change being "=" if unchanged, {"+": to} if created,
+// {"-": from} if deleted, or {"*": {"from": from, "to": to}}.
+func decodeAccountDiff(fields map[string]json.RawMessage) (*AccountDiff, error) {
+	diff := &AccountDiff{
+		Storage: make(map[common.Hash]StorageChange),
+	}
+	if raw, ok := fields["code"]; ok {
+		from, to, kind, err := decodeChange(raw)
+		if err != nil {
+			return nil, err
+		}
+		if kind != "=" {
+			code := &BytesChange{}
+			if code.From, err = decodeBytes(from); err != nil {
+				return nil, err
+			}
+			if code.To, err = decodeBytes(to); err != nil {
+				return nil, err
+			}
+			diff.Code = code
+			diff.Created, diff.Deleted = kind == "+", kind == "-"
+		}
+	}
+	if raw, ok := fields["storage"]; ok {
+		var slots map[common.Hash]json.RawMessage
+		if err := json.Unmarshal(raw, &slots); err != nil {
+			return nil, err
+		}
+		for slot, rawChange := range slots {
+			from, to, kind, err := decodeChange(rawChange)
+			if err != nil {
+				return nil, err
+			}
+			if kind == "=" {
+				continue
+			}
+			var change StorageChange
+			if from != nil {
+				if err := json.Unmarshal(from, &change.From); err != nil {
+					return nil, err
+				}
+			}
+			if to != nil {
+				if err := json.Unmarshal(to, &change.To); err != nil {
+					return nil, err
+				}
+			}
+			diff.Storage[slot] = change
+		}
+	}
+	return diff, nil
+}
+
+func decodeChange(raw json.RawMessage) (from, to json.RawMessage, kind string, err error) {
+	var unchanged string
+	if json.Unmarshal(raw, &unchanged) == nil {
+		return nil, nil, "=", nil
+	}
+	var change map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &change); err != nil {
+		return nil, nil, "", err
+	}
+	if v, ok := change["+"]; ok {
+		return nil, v, "+", nil
+	}
+	if v, ok := change["-"]; ok {
+		return v, nil, "-", nil
+	}
+	var modified struct {
+		From json.RawMessage `json:"from"`
+		To   json.RawMessage `json:"to"`
+	}
+	if err := json.Unmarshal(change["*"], &modified); err != nil {
+		return nil, nil, "", err
+	}
+	return modified.From, modified.To, "*", nil
+}
+
+func decodeBytes(raw json.RawMessage) ([]byte, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	var b hexutil.Bytes
+	if err := json.Unmarshal(raw, &b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
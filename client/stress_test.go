@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bsostech/go-besu/clock"
+	"github.com/bsostech/go-besu/privacy"
+	"github.com/bsostech/go-besu/types"
+)
+
+// TestConcurrentSends sends thousands of transactions from several accounts to
+// several groups at once, sharing one client, and checks that no nonce is
+// reserved twice and that all receipts arrive. Run with -race.
+func TestConcurrentSends(t *testing.T) {
+	const (
+		accounts = 4
+		groups   = 8
+		sends    = 2000
+	)
+	node := newFakeNode(t)
+	c := newFakeClient(t, node)
+	clk := clock.NewFake(time.Unix(0, 0))
+	c.SetClock(clk)
+	keys := make([]*ecdsa.PrivateKey, accounts)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	members := make([][]*privacy.PublicKey, groups)
+	for i := range members {
+		a, b := privacy.PublicKey(make([]byte, 32)), privacy.PublicKey(make([]byte, 32))
+		b[0], b[1] = 1, byte(i)
+		members[i] = []*privacy.PublicKey{&a, &b}
+	}
+	handles := make([]*TxHandle, sends)
+	errs := make(chan error, sends)
+	var wg sync.WaitGroup
+	for i := 0; i < sends; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := keys[(i/groups)%accounts]
+			group, err := c.FindPrivacyGroup(members[i%groups])
+			if err != nil {
+				errs <- err
+				return
+			}
+			nonce, err := c.NextNonce(crypto.PubkeyToAddress(key.PublicKey), group)
+			if err != nil {
+				errs <- err
+				return
+			}
+			groupID, err := base64.StdEncoding.DecodeString(group.ID)
+			if err != nil {
+				errs <- err
+				return
+			}
+			tx, err := types.NewPrivateTransaction(nonce, &common.Address{1}, big.NewInt(0), 100000, big.NewInt(0), nil, make([]byte, 32), groupID).SignTx(testChainID, key)
+			if err != nil {
+				errs <- err
+				return
+			}
+			handles[i] = c.SendAsync(context.Background(), tx)
+			if err := handles[i].Err(); err != nil {
+				errs <- fmt.Errorf("send %v: %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+	pollUntilDone(t, clk, handles...)
+	for i, h := range handles {
+		if err := h.Err(); err != nil {
+			t.Fatalf("receipt %v: %v", i, err)
+		}
+	}
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	if len(node.duplicates) > 0 {
+		t.Fatalf("%v duplicate nonces, e.g. %v", len(node.duplicates), node.duplicates[0])
+	}
+	if len(node.nonces) != accounts*groups {
+		t.Fatalf("%v account and group pairs, want %v", len(node.nonces), accounts*groups)
+	}
+	for key, nonces := range node.nonces {
+		for nonce := uint64(0); nonce < uint64(len(nonces)); nonce++ {
+			if !nonces[nonce] {
+				t.Fatalf("%v: nonce %v skipped", key, nonce)
+			}
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// StuckTxEventType .
+type StuckTxEventType int
+
+// StuckTxEventType .
+const (
+	// MarkerBumped means a stuck marker was re-sent with a higher gas price.
+	MarkerBumped StuckTxEventType = iota
+	// MarkerMined means a tracked marker, in any of its versions, was mined.
+	MarkerMined
+	// MarkerBumpFailed means a stuck marker could not be re-sent, e.g. because
+	// the bumped gas price would exceed MaxGasPrice.
+	MarkerBumpFailed
+)
+
+// StuckTxEvent reports what the monitor did about a tracked marker.
+type StuckTxEvent struct {
+	Type     StuckTxEventType
+	Nonce    uint64
+	Hash     common.Hash // the latest version of the marker
+	OldHash  common.Hash // the replaced version, for MarkerBumped
+	GasPrice *big.Int
+	Err      error
+}
+
+// StuckTxMonitor re-sends privacy marker transactions stuck in the pool
+// beyond Threshold with a bumped gas price. Only markers signed by the
+// sender can be tracked, i.e. sent with DistributeTransaction and NewMarker:
+// the payload is kept, so the private transaction is not distributed again.
+// A stuck marker blocks the private nonces of its group behind it.
+type StuckTxMonitor struct {
+	Threshold   time.Duration // how long a marker may stay in the pool
+	Interval    time.Duration // how often the pool is checked, Threshold/4 if 0
+	BumpPercent int64         // gas price increase, at least the 10% Besu requires to replace
+	MaxGasPrice *big.Int      // no bump beyond it, nil means no limit
+	Handler     func(StuckTxEvent)
+
+	client  *Client
+	key     *ecdsa.PrivateKey
+	signer  ethtypes.Signer
+	account common.Address
+
+	mu      sync.Mutex
+	markers map[uint64]*trackedMarker
+}
+
+type trackedMarker struct {
+	tx   *ethtypes.Transaction
+	sent time.Time
+}
+
+// NewStuckTxMonitor returns a monitor of the markers signed with key.
+func NewStuckTxMonitor(c *Client, key *ecdsa.PrivateKey, chainID *big.Int, threshold time.Duration) *StuckTxMonitor {
+	return &StuckTxMonitor{
+		Threshold:   threshold,
+		BumpPercent: 10,
+		client:      c,
+		key:         key,
+		signer:      ethtypes.NewEIP155Signer(chainID),
+		account:     crypto.PubkeyToAddress(key.PublicKey),
+		markers:     make(map[uint64]*trackedMarker),
+	}
+}
+
+// Send signs and sends marker, and tracks it.
+func (m *StuckTxMonitor) Send(ctx context.Context, marker *ethtypes.Transaction) (common.Hash, error) {
+	signed, err := ethtypes.SignTx(marker, m.signer, m.key)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := m.client.eth.SendTransaction(ctx, signed); err != nil {
+		return common.Hash{}, err
+	}
+	m.Track(signed)
+	return signed.Hash(), nil
+}
+
+// Track tracks a marker signed with the key of the monitor and already sent.
+func (m *StuckTxMonitor) Track(signed *ethtypes.Transaction) {
+	m.mu.Lock()
+	m.markers[signed.Nonce()] = &trackedMarker{tx: signed, sent: m.client.clock.Now()}
+	m.mu.Unlock()
+}
+
+// Pending returns the number of tracked markers not mined yet.
+func (m *StuckTxMonitor) Pending() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.markers)
+}
+
+// Run checks the tracked markers every Interval until ctx is done.
+func (m *StuckTxMonitor) Run(ctx context.Context) error {
+	interval := m.Interval
+	if interval <= 0 {
+		interval = m.Threshold / 4
+	}
+	ticker := m.client.clock.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+		}
+		if err := m.Check(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// Check drops the mined markers and bumps the stuck ones once.
+func (m *StuckTxMonitor) Check(ctx context.Context) error {
+	mined, err := m.client.eth.NonceAt(ctx, m.account, nil)
+	if err != nil {
+		return err
+	}
+	var (
+		events []StuckTxEvent
+		stuck  []*trackedMarker
+	)
+	m.mu.Lock()
+	for nonce, marker := range m.markers {
+		if nonce < mined {
+			delete(m.markers, nonce)
+			events = append(events, StuckTxEvent{Type: MarkerMined, Nonce: nonce, Hash: marker.tx.Hash(), GasPrice: marker.tx.GasPrice()})
+			continue
+		}
+		if m.client.clock.Now().Sub(marker.sent) > m.Threshold {
+			stuck = append(stuck, marker)
+		}
+	}
+	m.mu.Unlock()
+	for _, e := range events {
+		m.emit(e)
+	}
+	for _, marker := range stuck {
+		m.bump(ctx, marker)
+	}
+	return nil
+}
+
+func (m *StuckTxMonitor) bump(ctx context.Context, marker *trackedMarker) {
+	old := marker.tx
+	gasPrice := new(big.Int).Mul(old.GasPrice(), big.NewInt(100+m.BumpPercent))
+	gasPrice.Div(gasPrice, big.NewInt(100))
+	if gasPrice.Cmp(old.GasPrice()) <= 0 {
+		gasPrice.Add(old.GasPrice(), big.NewInt(1))
+	}
+	if m.MaxGasPrice != nil && gasPrice.Cmp(m.MaxGasPrice) > 0 {
+		m.emit(StuckTxEvent{Type: MarkerBumpFailed, Nonce: old.Nonce(), Hash: old.Hash(), GasPrice: old.GasPrice(),
+			Err: fmt.Errorf("gas price %v exceeds maximum %v", gasPrice, m.MaxGasPrice)})
+		return
+	}
+	replacement := ethtypes.NewTransaction(old.Nonce(), *old.To(), old.Value(), old.Gas(), gasPrice, old.Data())
+	signed, err := ethtypes.SignTx(replacement, m.signer, m.key)
+	if err == nil {
+		err = m.client.eth.SendTransaction(ctx, signed)
+	}
+	if err != nil {
+		m.emit(StuckTxEvent{Type: MarkerBumpFailed, Nonce: old.Nonce(), Hash: old.Hash(), GasPrice: old.GasPrice(), Err: err})
+		return
+	}
+	m.mu.Lock()
+	if m.markers[old.Nonce()] == marker {
+		m.markers[old.Nonce()] = &trackedMarker{tx: signed, sent: m.client.clock.Now()}
+	}
+	m.mu.Unlock()
+	m.emit(StuckTxEvent{Type: MarkerBumped, Nonce: old.Nonce(), Hash: signed.Hash(), OldHash: old.Hash(), GasPrice: gasPrice})
+}
+
+func (m *StuckTxMonitor) emit(e StuckTxEvent) {
+	if m.Handler != nil {
+		m.Handler(e)
+	}
+}
@@ -0,0 +1,162 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// OverflowPolicy decides what a subscription does when its buffer is full.
+type OverflowPolicy int
+
+// OverflowPolicy .
+const (
+	// OverflowBlock waits for the consumer, logs queue up in the RPC client meanwhile.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest drops the oldest buffered log to make room, counting it in Dropped.
+	OverflowDropOldest
+	// OverflowError ends the subscription with ErrSubscriptionOverflow.
+	OverflowError
+)
+
+// ErrSubscriptionOverflow is sent on Err when the buffer of an OverflowError subscription is full.
+var ErrSubscriptionOverflow = errors.New("subscription buffer overflow")
+
+// SubscriptionOptions .
+type SubscriptionOptions struct {
+	BufferSize int
+	Overflow   OverflowPolicy
+}
+
+// DefaultSubscriptionOptions .
+var DefaultSubscriptionOptions = SubscriptionOptions{
+	BufferSize: 128,
+	Overflow:   OverflowBlock,
+}
+
+// LogSubscription is a subscription to the private logs of a privacy group.
+type LogSubscription struct {
+	sub     *rpc.ClientSubscription
+	policy  OverflowPolicy
+	in      chan ethtypes.Log
+	logs    chan ethtypes.Log
+	errc    chan error
+	quit    chan struct{}
+	once    sync.Once
+	dropped uint64
+}
+
+// SubscribePrivateLogs subscribes to the private logs of a privacy group matching
+// the addresses and topics of q with priv_subscribe. It returns
+// ErrSubscriptionsUnsupported on HTTP clients.
+func (c *Client) SubscribePrivateLogs(ctx context.Context, privacyGroupID string, q ethereum.FilterQuery, opts SubscriptionOptions) (*LogSubscription, error) {
+	if !c.SupportsSubscriptions() {
+		return nil, ErrSubscriptionsUnsupported
+	}
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = DefaultSubscriptionOptions.BufferSize
+	}
+	filter := make(map[string]interface{})
+	if len(q.Addresses) > 0 {
+		filter["address"] = q.Addresses
+	}
+	if len(q.Topics) > 0 {
+		filter["topics"] = q.Topics
+	}
+	s := &LogSubscription{
+		policy: opts.Overflow,
+		in:     make(chan ethtypes.Log),
+		logs:   make(chan ethtypes.Log, opts.BufferSize),
+		errc:   make(chan error, 1),
+		quit:   make(chan struct{}),
+	}
+	sub, err := c.rpc.Subscribe(ctx, "priv", s.in, privacyGroupID, "logs", filter)
+	if err != nil {
+		return nil, err
+	}
+	s.sub = sub
+	go s.forward()
+	return s, nil
+}
+
+// Logs returns the channel logs are delivered on.
+func (s *LogSubscription) Logs() <-chan ethtypes.Log {
+	return s.logs
+}
+
+// Err returns the channel the error ending the subscription is sent on.
+func (s *LogSubscription) Err() <-chan error {
+	return s.errc
+}
+
+// Dropped returns the number of logs dropped by OverflowDropOldest.
+func (s *LogSubscription) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// Unsubscribe ends the subscription.
+func (s *LogSubscription) Unsubscribe() {
+	s.once.Do(func() {
+		close(s.quit)
+		s.sub.Unsubscribe()
+	})
+}
+
+func (s *LogSubscription) forward() {
+	for {
+		select {
+		case <-s.quit:
+			return
+		case err := <-s.sub.Err():
+			if err != nil {
+				s.errc <- err
+			}
+			return
+		case log := <-s.in:
+			if !s.deliver(log) {
+				return
+			}
+		}
+	}
+}
+
+// deliver hands log to the consumer according to the overflow policy and
+// reports whether the subscription is still alive.
+func (s *LogSubscription) deliver(log ethtypes.Log) bool {
+	select {
+	case s.logs <- log:
+		return true
+	default:
+	}
+	switch s.policy {
+	case OverflowDropOldest:
+		for {
+			select {
+			case s.logs <- log:
+				return true
+			default:
+			}
+			select {
+			case <-s.logs:
+				atomic.AddUint64(&s.dropped, 1)
+			default:
+			}
+		}
+	case OverflowError:
+		s.errc <- ErrSubscriptionOverflow
+		s.Unsubscribe()
+		return false
+	default:
+		select {
+		case s.logs <- log:
+			return true
+		case <-s.quit:
+			return false
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Transport is the kind of connection of a client.
+type Transport int
+
+// Transport .
+const (
+	// TransportUnknown is the transport of clients created with New.
+	TransportUnknown Transport = iota
+	TransportHTTP
+	TransportWS
+	TransportIPC
+)
+
+// ErrSubscriptionsUnsupported is returned when subscribing over HTTP.
+var ErrSubscriptionsUnsupported = errors.New("subscriptions need a websocket or IPC endpoint")
+
+func (t Transport) String() string {
+	switch t {
+	case TransportHTTP:
+		return "http"
+	case TransportWS:
+		return "ws"
+	case TransportIPC:
+		return "ipc"
+	}
+	return "unknown"
+}
+
+// ParseEndpoint returns the transport of url and the endpoint to dial, the
+// socket path for IPC.
+func ParseEndpoint(url string) (Transport, string, error) {
+	i := strings.Index(url, "://")
+	if i < 0 {
+		if url == "" {
+			return TransportUnknown, "", fmt.Errorf("empty endpoint")
+		}
+		return TransportIPC, url, nil
+	}
+	switch strings.ToLower(url[:i]) {
+	case "http", "https":
+		return TransportHTTP, url, nil
+	case "ws", "wss":
+		return TransportWS, url, nil
+	case "unix", "ipc":
+		return TransportIPC, url[i+3:], nil
+	}
+	return TransportUnknown, "", fmt.Errorf("unsupported scheme %v", url[:i])
+}
+
+// Transport returns the transport of the client, TransportUnknown for clients created with New.
+func (c *Client) Transport() Transport {
+	return c.transport
+}
+
+// SupportsSubscriptions reports whether the transport supports subscriptions,
+// assuming it does if the transport is unknown.
+func (c *Client) SupportsSubscriptions() bool {
+	return c.transport != TransportHTTP
+}
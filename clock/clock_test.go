@@ -0,0 +1,147 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+var start = time.Unix(1700000000, 0)
+
+// fired returns the time sent on c, zero if none.
+func fired(c <-chan time.Time) time.Time {
+	select {
+	case t := <-c:
+		return t
+	default:
+		return time.Time{}
+	}
+}
+
+func TestFakeTimers(t *testing.T) {
+	f := NewFake(start)
+	delays := []time.Duration{3 * time.Second, time.Second, 2 * time.Second, 5 * time.Second}
+	timers := make([]Timer, len(delays))
+	for i, d := range delays {
+		timers[i] = f.NewTimer(d)
+	}
+	f.Advance(999 * time.Millisecond)
+	for i, timer := range timers {
+		if got := fired(timer.C()); !got.IsZero() {
+			t.Fatalf("timer %v fired at %v before its deadline", delays[i], got)
+		}
+	}
+	f.Advance(2001 * time.Millisecond)
+	// timers fire at their deadline, in deadline order, up to the new time
+	for i, timer := range timers[:3] {
+		if got := fired(timer.C()); !got.Equal(start.Add(delays[i])) {
+			t.Errorf("timer %v fired at %v, want %v", delays[i], got, start.Add(delays[i]))
+		}
+	}
+	if !f.Now().Equal(start.Add(3 * time.Second)) {
+		t.Fatalf("now %v, want %v", f.Now(), start.Add(3*time.Second))
+	}
+	if f.Waiters() != 1 {
+		t.Fatalf("%v waiters, want 1", f.Waiters())
+	}
+	if !timers[3].Stop() || timers[3].Stop() {
+		t.Fatal("Stop of a pending timer")
+	}
+	if timers[0].Stop() {
+		t.Fatal("Stop of a fired timer returned true")
+	}
+	f.Advance(time.Hour)
+	if got := fired(timers[3].C()); !got.IsZero() {
+		t.Fatalf("stopped timer fired at %v", got)
+	}
+}
+
+func TestFakeZeroTimer(t *testing.T) {
+	f := NewFake(start)
+	timer := f.NewTimer(0)
+	f.Advance(0)
+	if got := fired(timer.C()); !got.Equal(start) {
+		t.Fatalf("timer fired at %v, want %v", got, start)
+	}
+}
+
+func TestFakeTicker(t *testing.T) {
+	f := NewFake(start)
+	ticker := f.NewTicker(time.Second)
+	for i := 1; i <= 3; i++ {
+		f.Advance(time.Second)
+		if got := fired(ticker.C()); !got.Equal(start.Add(time.Duration(i) * time.Second)) {
+			t.Fatalf("tick %v at %v", i, got)
+		}
+	}
+	// ticks the reader is not ready for are dropped, like time.Ticker
+	f.Advance(5 * time.Second)
+	if got := fired(ticker.C()); !got.Equal(start.Add(4 * time.Second)) {
+		t.Fatalf("tick at %v, want the first missed one", got)
+	}
+	if got := fired(ticker.C()); !got.IsZero() {
+		t.Fatalf("dropped tick at %v delivered", got)
+	}
+	// the ticker stays armed on its period
+	f.Advance(time.Second)
+	if got := fired(ticker.C()); !got.Equal(start.Add(9 * time.Second)) {
+		t.Fatalf("tick at %v, want %v", got, start.Add(9*time.Second))
+	}
+	ticker.Stop()
+	f.Advance(time.Hour)
+	if got := fired(ticker.C()); !got.IsZero() || f.Waiters() != 0 {
+		t.Fatalf("stopped ticker ticked at %v", got)
+	}
+}
+
+func TestFakeNewTickerPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("NewTicker(0) did not panic")
+		}
+	}()
+	NewFake(start).NewTicker(0)
+}
+
+func TestFakeBlockUntil(t *testing.T) {
+	f := NewFake(start)
+	done := make(chan time.Time)
+	go func() {
+		timer := f.NewTimer(time.Minute)
+		done <- <-timer.C()
+	}()
+	// without BlockUntil the clock could be advanced before the timer exists
+	f.BlockUntil(1)
+	f.Advance(time.Minute)
+	select {
+	case got := <-done:
+		if !got.Equal(start.Add(time.Minute)) {
+			t.Fatalf("timer fired at %v", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timer did not fire")
+	}
+	returned := make(chan struct{})
+	go func() {
+		f.BlockUntil(2)
+		close(returned)
+	}()
+	f.NewTimer(time.Second)
+	select {
+	case <-returned:
+		t.Fatal("BlockUntil(2) returned with 1 waiter")
+	case <-time.After(10 * time.Millisecond):
+	}
+	f.NewTicker(time.Second)
+	select {
+	case <-returned:
+	case <-time.After(5 * time.Second):
+		t.Fatal("BlockUntil(2) not woken by a new waiter")
+	}
+}
+
+func TestOr(t *testing.T) {
+	f := NewFake(start)
+	if Or(nil) != Real || Or(f) != f {
+		t.Fatal("Or")
+	}
+}
@@ -0,0 +1,201 @@
+// Package config loads the settings of a Besu network from a YAML or JSON
+// file, with environment variable overrides, and builds a ready Client.
+package config
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"gopkg.in/yaml.v2"
+
+	"github.com/bsostech/go-besu/client"
+	"github.com/bsostech/go-besu/privacy"
+	"github.com/bsostech/go-besu/signer/pkcs11"
+	"github.com/bsostech/go-besu/types"
+)
+
+// EnvPrefix prefixes the environment variables overriding the config file.
+const EnvPrefix = "BESU_"
+
+// Signer types.
+const (
+	SignerKey     = "key"      // hex private key in Key
+	SignerKeyFile = "key-file" // hex private key in the file KeyFile
+	SignerPKCS11  = "pkcs11"   // HSM key selected by PKCS11
+)
+
+// Config .
+type Config struct {
+	Endpoint   string        `yaml:"endpoint" json:"endpoint"`
+	ChainID    uint64        `yaml:"chainId" json:"chainId"`
+	EnclaveKey string        `yaml:"enclaveKey" json:"enclaveKey"` // base64 public key of the node's enclave, used as privateFrom
+	Profile    string        `yaml:"profile" json:"profile"`       // "free-gas" or "public-testnet"
+	Signer     SignerConfig  `yaml:"signer" json:"signer"`
+	Groups     []GroupConfig `yaml:"groups" json:"groups"`
+}
+
+// SignerConfig .
+type SignerConfig struct {
+	Type    string         `yaml:"type" json:"type"`
+	Key     string         `yaml:"key" json:"key"`
+	KeyFile string         `yaml:"keyFile" json:"keyFile"`
+	PKCS11  *pkcs11.Config `yaml:"pkcs11" json:"pkcs11"`
+}
+
+// GroupConfig defines a privacy group by ID, by members, or both.
+type GroupConfig struct {
+	Name    string   `yaml:"name" json:"name"`
+	ID      string   `yaml:"id" json:"id"`
+	Members []string `yaml:"members" json:"members"`
+}
+
+// Load reads the config file at path, YAML unless its extension is .json,
+// and applies the environment overrides.
+func Load(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &cfg)
+	} else {
+		err = yaml.UnmarshalStrict(data, &cfg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %v, err: %v", path, err)
+	}
+	if err := cfg.ApplyEnv(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// ApplyEnv overrides the settings with BESU_ENDPOINT, BESU_CHAIN_ID,
+// BESU_ENCLAVE_KEY, BESU_PROFILE, BESU_SIGNER_TYPE, BESU_SIGNER_KEY and
+// BESU_SIGNER_KEY_FILE when set.
+func (c *Config) ApplyEnv() error {
+	for name, v := range map[string]*string{
+		"ENDPOINT":        &c.Endpoint,
+		"ENCLAVE_KEY":     &c.EnclaveKey,
+		"PROFILE":         &c.Profile,
+		"SIGNER_TYPE":     &c.Signer.Type,
+		"SIGNER_KEY":      &c.Signer.Key,
+		"SIGNER_KEY_FILE": &c.Signer.KeyFile,
+	} {
+		if value, ok := os.LookupEnv(EnvPrefix + name); ok {
+			*v = value
+		}
+	}
+	if value, ok := os.LookupEnv(EnvPrefix + "CHAIN_ID"); ok {
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %vCHAIN_ID %v", EnvPrefix, value)
+		}
+		c.ChainID = id
+	}
+	return nil
+}
+
+// Client dials the endpoint with client.NewClient and returns a client on it.
+func (c *Config) Client(opts ...client.Option) (*client.Client, error) {
+	if c.Endpoint == "" {
+		return nil, fmt.Errorf("endpoint not found")
+	}
+	return client.NewClient(c.Endpoint, opts...)
+}
+
+// ChainIDBig returns the chain ID as a big.Int, as signing takes it.
+func (c *Config) ChainIDBig() *big.Int {
+	return new(big.Int).SetUint64(c.ChainID)
+}
+
+// PrivateFrom returns the enclave key.
+func (c *Config) PrivateFrom() (privacy.PublicKey, error) {
+	if c.EnclaveKey == "" {
+		return nil, fmt.Errorf("enclaveKey not found")
+	}
+	return privacy.ToPublicKey(c.EnclaveKey)
+}
+
+// NetworkProfile returns the transaction defaults of the profile, FreeGasNetwork if unset.
+func (c *Config) NetworkProfile() (types.NetworkProfile, error) {
+	switch c.Profile {
+	case "", "free-gas":
+		return types.FreeGasNetwork, nil
+	case "public-testnet":
+		return types.PublicTestnet, nil
+	}
+	return types.NetworkProfile{}, fmt.Errorf("unknown profile %v", c.Profile)
+}
+
+// Key returns the private key of the signer.
+func (c *Config) Key() (*ecdsa.PrivateKey, error) {
+	switch c.Signer.Type {
+	case "", SignerKey:
+		if c.Signer.Key == "" {
+			return nil, fmt.Errorf("signer key not found")
+		}
+		return crypto.HexToECDSA(strings.TrimPrefix(c.Signer.Key, "0x"))
+	case SignerKeyFile:
+		data, err := ioutil.ReadFile(c.Signer.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(string(data)), "0x"))
+	case SignerPKCS11:
+		return nil, fmt.Errorf("pkcs11 signer keys can not be exported, use PKCS11Signer")
+	}
+	return nil, fmt.Errorf("unknown signer type %v", c.Signer.Type)
+}
+
+// PKCS11Signer returns the HSM signer of a pkcs11 signer, on module m.
+func (c *Config) PKCS11Signer(m pkcs11.Module) (*pkcs11.Signer, error) {
+	if c.Signer.Type != SignerPKCS11 || c.Signer.PKCS11 == nil {
+		return nil, fmt.Errorf("pkcs11 signer not found")
+	}
+	return pkcs11.NewSigner(m, *c.Signer.PKCS11)
+}
+
+// Group returns the privacy group named name. Groups defined by members only
+// get the ID of their root privacy group.
+func (c *Config) Group(p *privacy.Privacy, name string) (*privacy.Group, error) {
+	for _, g := range c.Groups {
+		if g.Name != name {
+			continue
+		}
+		members := make([]*privacy.PublicKey, len(g.Members))
+		for i, m := range g.Members {
+			key, err := privacy.ToPublicKey(m)
+			if err != nil {
+				return nil, fmt.Errorf("invalid member %v of group %v, err: %v", m, name, err)
+			}
+			members[i] = &key
+		}
+		group := &privacy.Group{
+			ID:      g.ID,
+			Name:    g.Name,
+			Members: members,
+		}
+		if group.ID == "" {
+			if len(members) == 0 {
+				return nil, fmt.Errorf("group %v has neither id nor members", name)
+			}
+			root, err := p.RootPrivacyGroup(members)
+			if err != nil {
+				return nil, err
+			}
+			group.ID = root.ID
+		}
+		return group, nil
+	}
+	return nil, fmt.Errorf("group %v not found", name)
+}
@@ -0,0 +1,117 @@
+// Package create2 plans the addresses of private contracts deployed with
+// CREATE2 by a deployer contract, so they are known before deployment and
+// stay the same across redeployments in a privacy group.
+package create2
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bsostech/go-besu/client"
+)
+
+// DeployerABI is the interface of the reference deployer contract, deploying
+// code with CREATE2 and salt.
+const DeployerABI = `[{"constant":false,"inputs":[{"name":"salt","type":"bytes32"},{"name":"code","type":"bytes"}],"name":"deploy","outputs":[{"name":"addr","type":"address"}],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":true,"inputs":[{"name":"salt","type":"bytes32"},{"name":"codeHash","type":"bytes32"}],"name":"computeAddress","outputs":[{"name":"addr","type":"address"}],"payable":false,"stateMutability":"view","type":"function"},{"anonymous":false,"inputs":[{"indexed":true,"name":"addr","type":"address"},{"indexed":true,"name":"salt","type":"bytes32"}],"name":"Deployed","type":"event"}]`
+
+// Address returns the address of code deployed by deployer with salt.
+func Address(deployer common.Address, salt [32]byte, code []byte) common.Address {
+	return crypto.CreateAddress2(deployer, salt, crypto.Keccak256(code))
+}
+
+// Salt derives a salt from a name, e.g. "token-v1".
+func Salt(name string) [32]byte {
+	var salt [32]byte
+	copy(salt[:], crypto.Keccak256([]byte(name)))
+	return salt
+}
+
+// Deployer is a binding of a deployer contract in the private state of a privacy group.
+type Deployer struct {
+	client         *client.Client
+	address        common.Address
+	privacyGroupID string
+	abi            abi.ABI
+}
+
+// NewDeployer .
+func NewDeployer(c *client.Client, address common.Address, privacyGroupID string) (*Deployer, error) {
+	parsed, err := abi.JSON(strings.NewReader(DeployerABI))
+	if err != nil {
+		return nil, err
+	}
+	return &Deployer{
+		client:         c,
+		address:        address,
+		privacyGroupID: privacyGroupID,
+		abi:            parsed,
+	}, nil
+}
+
+// Address returns the address of the deployer.
+func (d *Deployer) Address() common.Address {
+	return d.address
+}
+
+// ChildAddress returns the address code deployed with salt will have.
+func (d *Deployer) ChildAddress(salt [32]byte, code []byte) common.Address {
+	return Address(d.address, salt, code)
+}
+
+// DeployData returns the input of a private transaction to the deployer deploying code with salt.
+func (d *Deployer) DeployData(salt [32]byte, code []byte) ([]byte, error) {
+	return d.abi.Pack("deploy", salt, code)
+}
+
+// ComputeAddress returns the address the deployer computes for code and salt with priv_call.
+func (d *Deployer) ComputeAddress(ctx context.Context, salt [32]byte, code []byte) (common.Address, error) {
+	var codeHash [32]byte
+	copy(codeHash[:], crypto.Keccak256(code))
+	input, err := d.abi.Pack("computeAddress", salt, codeHash)
+	if err != nil {
+		return common.Address{}, err
+	}
+	var output hexutil.Bytes
+	msg := map[string]interface{}{
+		"to":   d.address,
+		"data": hexutil.Bytes(input),
+	}
+	if err := d.client.RPC().CallContext(ctx, &output, "priv_call", d.privacyGroupID, msg, "latest"); err != nil {
+		return common.Address{}, err
+	}
+	var addr common.Address
+	if err := d.abi.Unpack(&addr, "computeAddress", output); err != nil {
+		return common.Address{}, err
+	}
+	return addr, nil
+}
+
+// Verify checks the deployer computes the address planned locally for code
+// and salt. If deployed is set, it also checks code is deployed at it.
+func (d *Deployer) Verify(ctx context.Context, salt [32]byte, code []byte, deployed bool) (common.Address, error) {
+	want := d.ChildAddress(salt, code)
+	got, err := d.ComputeAddress(ctx, salt, code)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if got != want {
+		return common.Address{}, fmt.Errorf("deployer computed %v, expected %v", got.Hex(), want.Hex())
+	}
+	if !deployed {
+		return want, nil
+	}
+	var runtime hexutil.Bytes
+	if err := d.client.RPC().CallContext(ctx, &runtime, "priv_getCode", d.privacyGroupID, want, "latest"); err != nil {
+		return common.Address{}, err
+	}
+	if len(runtime) == 0 {
+		return common.Address{}, fmt.Errorf("code at %v not found", want.Hex())
+	}
+	return want, nil
+}
@@ -0,0 +1,149 @@
+package enclave
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/bsostech/go-besu/privacy"
+)
+
+// Dialect is the API flavour of an enclave.
+type Dialect int
+
+// Dialect .
+const (
+	// DialectAuto detects the dialect on first use.
+	DialectAuto Dialect = iota
+	DialectTessera
+	DialectOrion
+)
+
+func (d Dialect) String() string {
+	switch d {
+	case DialectTessera:
+		return "tessera"
+	case DialectOrion:
+		return "orion"
+	}
+	return "auto"
+}
+
+// SetDialect sets the dialect instead of detecting it.
+func (e *Enclave) SetDialect(d Dialect) {
+	e.mu.Lock()
+	e.dialect = d
+	e.mu.Unlock()
+}
+
+// Dialect returns the dialect of the enclave, detecting it if needed: only
+// Tessera serves /version.
+func (e *Enclave) Dialect(ctx context.Context) (Dialect, error) {
+	e.mu.Lock()
+	d := e.dialect
+	e.mu.Unlock()
+	if d != DialectAuto {
+		return d, nil
+	}
+	req, err := http.NewRequest(http.MethodGet, e.url+"/version", nil)
+	if err != nil {
+		return DialectAuto, err
+	}
+	resp, err := e.http.Do(req.WithContext(ctx))
+	if err != nil {
+		return DialectAuto, err
+	}
+	resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		d = DialectTessera
+	case http.StatusNotFound:
+		d = DialectOrion
+	default:
+		return DialectAuto, fmt.Errorf("failed to detect dialect: %v", resp.Status)
+	}
+	e.SetDialect(d)
+	return d, nil
+}
+
+// Send stores payload for the recipients to, or the members of
+// privacyGroupID if set, and returns its enclave key.
+func (e *Enclave) Send(ctx context.Context, payload []byte, from privacy.PublicKey, to []privacy.PublicKey, privacyGroupID string) ([]byte, error) {
+	body := map[string]interface{}{
+		"payload": base64.StdEncoding.EncodeToString(payload),
+		"from":    from.ToString(),
+	}
+	if privacyGroupID != "" {
+		body["privacyGroupId"] = privacyGroupID
+	} else {
+		recipients := make([]string, len(to))
+		for i, k := range to {
+			recipients[i] = k.ToString()
+		}
+		body["to"] = recipients
+	}
+	var rsp struct {
+		Key string `json:"key"`
+	}
+	if err := e.do(ctx, http.MethodPost, "/send", body, &rsp); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(rsp.Key)
+}
+
+// Receive returns the payload of key as decrypted for the recipient to.
+func (e *Enclave) Receive(ctx context.Context, key []byte, to privacy.PublicKey) ([]byte, error) {
+	d, err := e.Dialect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	encodedKey := base64.StdEncoding.EncodeToString(key)
+	var rsp struct {
+		Payload string `json:"payload"`
+	}
+	if d == DialectOrion {
+		body := map[string]string{
+			"key": encodedKey,
+			"to":  to.ToString(),
+		}
+		err = e.do(ctx, http.MethodPost, "/receive", body, &rsp)
+	} else {
+		path := "/transaction/" + url.PathEscape(encodedKey) + "?to=" + url.QueryEscape(to.ToString())
+		err = e.do(ctx, http.MethodGet, path, nil, &rsp)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(rsp.Payload)
+}
+
+func (e *Enclave) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequest(method, e.url+path, &body)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := e.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%v %v: %v", method, path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,127 @@
+package enclave
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bsostech/go-besu/privacy"
+)
+
+// Enclave is a client of the REST API of a Tessera or Orion node. The peer
+// to peer API (PartyInfo) and the client API (Send, Receive) may be served on
+// different ports, needing an Enclave each.
+type Enclave struct {
+	url  string
+	http *http.Client
+
+	mu      sync.Mutex
+	dialect Dialect
+}
+
+// PartyInfo is the view of a Tessera node on the privacy network.
+type PartyInfo struct {
+	URL   string `json:"url"`
+	Peers []Peer `json:"peers"`
+	Keys  []Key  `json:"keys"`
+}
+
+// Peer .
+type Peer struct {
+	URL         string     `json:"url"`
+	LastContact *time.Time `json:"lastContact,omitempty"` // nil if never contacted
+}
+
+// Key is a public key known to the node and the URL of the node owning it.
+type Key struct {
+	Key string `json:"key"`
+	URL string `json:"url"`
+}
+
+// NewEnclave returns a client of the Tessera node at url, e.g. its P2P
+// endpoint "http://tessera:9000". A nil httpClient means http.DefaultClient.
+func NewEnclave(url string, httpClient *http.Client) *Enclave {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Enclave{
+		url:  strings.TrimSuffix(url, "/"),
+		http: httpClient,
+	}
+}
+
+// PartyInfo returns the peers and the public keys known to the node. Orion
+// only serves the keys, peers are derived from their URLs.
+func (e *Enclave) PartyInfo(ctx context.Context) (*PartyInfo, error) {
+	d, err := e.Dialect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var info PartyInfo
+	if d == DialectOrion {
+		if err := e.do(ctx, http.MethodGet, "/partyinfo/keys", nil, &info); err != nil {
+			return nil, err
+		}
+		seen := make(map[string]bool)
+		for _, k := range info.Keys {
+			if !seen[k.URL] {
+				seen[k.URL] = true
+				info.Peers = append(info.Peers, Peer{URL: k.URL})
+			}
+		}
+		return &info, nil
+	}
+	if err := e.do(ctx, http.MethodGet, "/partyinfo", nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// HasKey reports whether pub is known to the node.
+func (p *PartyInfo) HasKey(pub privacy.PublicKey) bool {
+	_, ok := p.KeyURL(pub)
+	return ok
+}
+
+// KeyURL returns the URL of the node owning pub.
+func (p *PartyInfo) KeyURL(pub privacy.PublicKey) (string, bool) {
+	s := pub.ToString()
+	for _, k := range p.Keys {
+		if k.Key == s {
+			return k.URL, true
+		}
+	}
+	return "", false
+}
+
+// MissingKeys returns the keys of keys not known to the node, e.g. the
+// participants of a group a transaction is about to be sent to.
+func (p *PartyInfo) MissingKeys(keys []privacy.PublicKey) []privacy.PublicKey {
+	var missing []privacy.PublicKey
+	for _, k := range keys {
+		if !p.HasKey(k) {
+			missing = append(missing, k)
+		}
+	}
+	return missing
+}
+
+// Upcheck checks that the node is up with its /upcheck endpoint.
+func (e *Enclave) Upcheck(ctx context.Context) error {
+	req, err := http.NewRequest(http.MethodGet, e.url+"/upcheck", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := e.http.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("upcheck: %v", resp.Status)
+	}
+	return nil
+}
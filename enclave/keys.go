@@ -0,0 +1,267 @@
+// Package enclave generates and stores enclave key pairs in the formats of
+// Tessera: NaCl box (Curve25519) keys, the public key file holding the base64
+// public key and the private key file a JSON document, unlocked or locked
+// with a password through Argon2 and NaCl secretbox.
+package enclave
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/nacl/box"
+	"golang.org/x/crypto/nacl/secretbox"
+
+	"github.com/bsostech/go-besu/privacy"
+)
+
+// KeySize is the size of enclave public and private keys.
+const KeySize = 32
+
+// Private key types of Tessera.
+const (
+	TypeUnlocked   = "unlocked"
+	TypeArgon2SBox = "argon2sbox"
+)
+
+// ErrWrongPassword is returned when a locked private key can not be opened.
+var ErrWrongPassword = errors.New("wrong password for private key")
+
+// KeyPair .
+type KeyPair struct {
+	Public  [KeySize]byte
+	Private [KeySize]byte
+}
+
+// ArgonOptions are the Argon2 parameters of a locked private key.
+type ArgonOptions struct {
+	Variant     string `json:"variant"` // "id" or "i"
+	Memory      uint32 `json:"memory"`  // in KiB
+	Iterations  uint32 `json:"iterations"`
+	Parallelism uint8  `json:"parallelism"`
+}
+
+// DefaultArgonOptions are the defaults of Tessera.
+var DefaultArgonOptions = ArgonOptions{
+	Variant:     "id",
+	Memory:      1048576,
+	Iterations:  10,
+	Parallelism: 4,
+}
+
+type privateKeyFile struct {
+	Type string         `json:"type"`
+	Data privateKeyData `json:"data"`
+}
+
+type privateKeyData struct {
+	Bytes  string        `json:"bytes,omitempty"`
+	AOpts  *ArgonOptions `json:"aopts,omitempty"`
+	SNonce string        `json:"snonce,omitempty"`
+	ASalt  string        `json:"asalt,omitempty"`
+	SBox   string        `json:"sbox,omitempty"`
+}
+
+// GenerateKeyPair generates a key pair from crypto/rand.
+func GenerateKeyPair() (*KeyPair, error) {
+	pub, priv, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyPair{
+		Public:  *pub,
+		Private: *priv,
+	}, nil
+}
+
+// PublicKey returns the public key as used for privateFrom and privateFor.
+func (k *KeyPair) PublicKey() privacy.PublicKey {
+	return append(privacy.PublicKey(nil), k.Public[:]...)
+}
+
+// Validate checks that the public key is derived from the private key.
+func (k *KeyPair) Validate() error {
+	var pub [KeySize]byte
+	curve25519.ScalarBaseMult(&pub, &k.Private)
+	if pub != k.Public {
+		return fmt.Errorf("public key does not match private key")
+	}
+	return nil
+}
+
+// ParsePublicKey decodes and validates a base64 public key.
+func ParsePublicKey(s string) (privacy.PublicKey, error) {
+	key, err := privacy.ToPublicKey(strings.TrimSpace(s))
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("invalid public key length %v", len(key))
+	}
+	return key, nil
+}
+
+// ReadPublicKeyFile reads a public key file.
+func ReadPublicKeyFile(path string) (privacy.PublicKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePublicKey(string(data))
+}
+
+// WritePublicKeyFile writes a public key file.
+func WritePublicKeyFile(path string, pub privacy.PublicKey) error {
+	return ioutil.WriteFile(path, []byte(pub.ToString()), 0644)
+}
+
+// ReadPrivateKeyFile reads a private key file, opening locked keys with password.
+func ReadPrivateKeyFile(path string, password []byte) ([KeySize]byte, error) {
+	var key [KeySize]byte
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return key, err
+	}
+	var file privateKeyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return key, fmt.Errorf("failed to parse %v, err: %v", path, err)
+	}
+	var priv []byte
+	switch file.Type {
+	case TypeUnlocked:
+		priv, err = base64.StdEncoding.DecodeString(file.Data.Bytes)
+		if err != nil {
+			return key, err
+		}
+	case TypeArgon2SBox:
+		priv, err = openPrivateKey(&file.Data, password)
+		if err != nil {
+			return key, err
+		}
+	default:
+		return key, fmt.Errorf("unsupported private key type %v", file.Type)
+	}
+	if len(priv) != KeySize {
+		return key, fmt.Errorf("invalid private key length %v", len(priv))
+	}
+	copy(key[:], priv)
+	return key, nil
+}
+
+// WritePrivateKeyFile writes a private key file, locked with password and
+// DefaultArgonOptions unless password is empty.
+func WritePrivateKeyFile(path string, priv [KeySize]byte, password []byte) error {
+	file := privateKeyFile{
+		Type: TypeUnlocked,
+		Data: privateKeyData{
+			Bytes: base64.StdEncoding.EncodeToString(priv[:]),
+		},
+	}
+	if len(password) > 0 {
+		data, err := lockPrivateKey(priv, password, DefaultArgonOptions)
+		if err != nil {
+			return err
+		}
+		file = privateKeyFile{
+			Type: TypeArgon2SBox,
+			Data: *data,
+		}
+	}
+	data, err := json.MarshalIndent(&file, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// ReadKeyPair reads a public and a private key file and validates the pair.
+func ReadKeyPair(publicPath, privatePath string, password []byte) (*KeyPair, error) {
+	pub, err := ReadPublicKeyFile(publicPath)
+	if err != nil {
+		return nil, err
+	}
+	priv, err := ReadPrivateKeyFile(privatePath, password)
+	if err != nil {
+		return nil, err
+	}
+	k := &KeyPair{
+		Private: priv,
+	}
+	copy(k.Public[:], pub)
+	if err := k.Validate(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func lockPrivateKey(priv [KeySize]byte, password []byte, opts ArgonOptions) (*privateKeyData, error) {
+	var nonce [24]byte
+	salt := make([]byte, 16)
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	key, err := deriveKey(password, salt, opts)
+	if err != nil {
+		return nil, err
+	}
+	sealed := secretbox.Seal(nil, priv[:], &nonce, key)
+	return &privateKeyData{
+		AOpts:  &opts,
+		SNonce: base64.StdEncoding.EncodeToString(nonce[:]),
+		ASalt:  base64.StdEncoding.EncodeToString(salt),
+		SBox:   base64.StdEncoding.EncodeToString(sealed),
+	}, nil
+}
+
+func openPrivateKey(data *privateKeyData, password []byte) ([]byte, error) {
+	if data.AOpts == nil {
+		return nil, fmt.Errorf("aopts not found")
+	}
+	nonceBytes, err := base64.StdEncoding.DecodeString(data.SNonce)
+	if err != nil || len(nonceBytes) != 24 {
+		return nil, fmt.Errorf("invalid snonce %v", data.SNonce)
+	}
+	salt, err := base64.StdEncoding.DecodeString(data.ASalt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid asalt %v", data.ASalt)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(data.SBox)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sbox %v", data.SBox)
+	}
+	key, err := deriveKey(password, salt, *data.AOpts)
+	if err != nil {
+		return nil, err
+	}
+	var nonce [24]byte
+	copy(nonce[:], nonceBytes)
+	priv, ok := secretbox.Open(nil, sealed, &nonce, key)
+	if !ok {
+		return nil, ErrWrongPassword
+	}
+	return priv, nil
+}
+
+func deriveKey(password, salt []byte, opts ArgonOptions) (*[KeySize]byte, error) {
+	var derived []byte
+	switch opts.Variant {
+	case "id":
+		derived = argon2.IDKey(password, salt, opts.Iterations, opts.Memory, opts.Parallelism, KeySize)
+	case "i":
+		derived = argon2.Key(password, salt, opts.Iterations, opts.Memory, opts.Parallelism, KeySize)
+	default:
+		return nil, fmt.Errorf("unsupported argon2 variant %v", opts.Variant)
+	}
+	var key [KeySize]byte
+	copy(key[:], derived)
+	return &key, nil
+}
@@ -0,0 +1,64 @@
+package enrich
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bsostech/go-besu/types"
+)
+
+// Call is a decoded transaction input. Method is empty if the input could not be decoded.
+type Call struct {
+	Address      common.Address         `json:"address"`
+	ContractName string                 `json:"contractName,omitempty"`
+	Method       string                 `json:"method,omitempty"`
+	Signature    string                 `json:"signature,omitempty"`
+	Args         map[string]interface{} `json:"args,omitempty"`
+}
+
+// DecodeTransaction decodes the input of tx with the ABI of its recipient in
+// registry. It returns nil for contract creations.
+func DecodeTransaction(tx *types.PrivateTransaction, registry ABIRegistry) *Call {
+	to := tx.To()
+	if to == nil {
+		return nil
+	}
+	return DecodeCall(*to, tx.Data(), registry)
+}
+
+// DecodeCall decodes input of a call of the contract at address.
+func DecodeCall(address common.Address, input []byte, registry ABIRegistry) *Call {
+	call := &Call{
+		Address: address,
+	}
+	name, contractABI, ok := registry.Contract(address)
+	if !ok {
+		return call
+	}
+	call.ContractName = name
+	if len(input) < 4 {
+		return call
+	}
+	for _, m := range contractABI.Methods {
+		sig := MethodSignature(m)
+		if !bytes.Equal(crypto.Keccak256([]byte(sig))[:4], input[:4]) {
+			continue
+		}
+		args := make(map[string]interface{})
+		if err := m.Inputs.UnpackIntoMap(args, input[4:]); err != nil {
+			return call
+		}
+		call.Method = m.Name
+		call.Signature = sig
+		call.Args = args
+		return call
+	}
+	return call
+}
+
+// EnrichGroupReceipt decodes the logs of receipt with the ABIs registered for its privacy group.
+func EnrichGroupReceipt(receipt *types.PrivateReceipt, privacyGroupID string, registry *GroupRegistry) *Receipt {
+	return EnrichReceipt(receipt, registry.ForGroup(privacyGroupID))
+}
@@ -0,0 +1,106 @@
+package enrich
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bsostech/go-besu/types"
+)
+
+// Receipt is a private receipt with its logs decoded.
+type Receipt struct {
+	*types.PrivateReceipt
+	ContractName string   `json:"contractName,omitempty"` // name of the created contract, if any
+	Events       []*Event `json:"events"`
+}
+
+// Event is a decoded log. Name is empty if the log could not be decoded.
+type Event struct {
+	Address      common.Address         `json:"address"`
+	ContractName string                 `json:"contractName,omitempty"`
+	Name         string                 `json:"name,omitempty"`
+	Signature    string                 `json:"signature,omitempty"`
+	Args         map[string]interface{} `json:"args,omitempty"`
+	Log          *ethtypes.Log          `json:"log"`
+}
+
+// EnrichReceipt decodes each log of receipt with the ABIs in registry.
+// Indexed arguments of dynamic types are kept as their topic hash.
+func EnrichReceipt(receipt *types.PrivateReceipt, registry ABIRegistry) *Receipt {
+	enriched := &Receipt{
+		PrivateReceipt: receipt,
+	}
+	if receipt.ContractAddress != (common.Address{}) {
+		if name, _, ok := registry.Contract(receipt.ContractAddress); ok {
+			enriched.ContractName = name
+		}
+	}
+	for _, log := range receipt.Logs {
+		enriched.Events = append(enriched.Events, DecodeLog(log, registry))
+	}
+	return enriched
+}
+
+// DecodeLog decodes log with the ABI of its contract in registry.
+func DecodeLog(log *ethtypes.Log, registry ABIRegistry) *Event {
+	event := &Event{
+		Address: log.Address,
+		Log:     log,
+	}
+	name, contractABI, ok := registry.Contract(log.Address)
+	if !ok {
+		return event
+	}
+	event.ContractName = name
+	if len(log.Topics) == 0 {
+		return event
+	}
+	for _, e := range contractABI.Events {
+		if e.Anonymous {
+			continue
+		}
+		sig := EventSignature(e)
+		if crypto.Keccak256Hash([]byte(sig)) != log.Topics[0] {
+			continue
+		}
+		args := make(map[string]interface{})
+		if err := e.Inputs.NonIndexed().UnpackIntoMap(args, log.Data); err != nil {
+			return event
+		}
+		topics := log.Topics[1:]
+		for _, input := range e.Inputs {
+			if !input.Indexed || len(topics) == 0 {
+				continue
+			}
+			args[input.Name] = topics[0]
+			topics = topics[1:]
+		}
+		event.Name = e.Name
+		event.Signature = sig
+		event.Args = args
+		return event
+	}
+	return event
+}
+
+// EventSignature returns the canonical signature of e, e.g. Transfer(address,address,uint256).
+func EventSignature(e abi.Event) string {
+	return signature(e.Name, e.Inputs)
+}
+
+// MethodSignature returns the canonical signature of m, e.g. transfer(address,uint256).
+func MethodSignature(m abi.Method) string {
+	return signature(m.Name, m.Inputs)
+}
+
+func signature(name string, args abi.Arguments) string {
+	argTypes := make([]string, len(args))
+	for i := range args {
+		argTypes[i] = args[i].Type.String()
+	}
+	return name + "(" + strings.Join(argTypes, ",") + ")"
+}
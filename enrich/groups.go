@@ -0,0 +1,64 @@
+package enrich
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// GroupRegistry maps the contracts of each privacy group to their ABIs, as
+// the same address holds different contracts in different groups. Contracts
+// registered without group are shared by all groups.
+type GroupRegistry struct {
+	mu     sync.RWMutex
+	shared *Registry
+	groups map[string]*Registry
+}
+
+// NewGroupRegistry .
+func NewGroupRegistry() *GroupRegistry {
+	return &GroupRegistry{
+		shared: NewRegistry(),
+		groups: make(map[string]*Registry),
+	}
+}
+
+// Register adds or replaces the contract at address in the privacy group,
+// in all groups if privacyGroupID is empty.
+func (r *GroupRegistry) Register(privacyGroupID string, address common.Address, name string, contractABI *abi.ABI) {
+	if privacyGroupID == "" {
+		r.shared.Register(address, name, contractABI)
+		return
+	}
+	r.mu.Lock()
+	g, ok := r.groups[privacyGroupID]
+	if !ok {
+		g = NewRegistry()
+		r.groups[privacyGroupID] = g
+	}
+	r.mu.Unlock()
+	g.Register(address, name, contractABI)
+}
+
+// ForGroup returns the ABIRegistry of a privacy group.
+func (r *GroupRegistry) ForGroup(privacyGroupID string) ABIRegistry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return &groupView{group: r.groups[privacyGroupID], shared: r.shared}
+}
+
+type groupView struct {
+	group  *Registry
+	shared *Registry
+}
+
+// Contract implements ABIRegistry.
+func (v *groupView) Contract(address common.Address) (string, *abi.ABI, bool) {
+	if v.group != nil {
+		if name, contractABI, ok := v.group.Contract(address); ok {
+			return name, contractABI, true
+		}
+	}
+	return v.shared.Contract(address)
+}
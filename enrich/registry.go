@@ -0,0 +1,49 @@
+package enrich
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ABIRegistry resolves the name and ABI of a contract.
+type ABIRegistry interface {
+	Contract(address common.Address) (name string, contractABI *abi.ABI, ok bool)
+}
+
+// Registry is an in-memory ABIRegistry.
+type Registry struct {
+	mu        sync.RWMutex
+	contracts map[common.Address]*contract
+}
+
+type contract struct {
+	name string
+	abi  *abi.ABI
+}
+
+// NewRegistry .
+func NewRegistry() *Registry {
+	return &Registry{
+		contracts: make(map[common.Address]*contract),
+	}
+}
+
+// Register adds or replaces the contract at address.
+func (r *Registry) Register(address common.Address, name string, contractABI *abi.ABI) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.contracts[address] = &contract{name: name, abi: contractABI}
+}
+
+// Contract implements ABIRegistry.
+func (r *Registry) Contract(address common.Address) (string, *abi.ABI, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.contracts[address]
+	if !ok {
+		return "", nil, false
+	}
+	return c.name, c.abi, true
+}
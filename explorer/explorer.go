@@ -0,0 +1,60 @@
+package explorer
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/bsostech/go-besu/indexer"
+)
+
+// Explorer answers private block explorer queries from an indexer Store.
+type Explorer struct {
+	store indexer.Store
+}
+
+// New .
+func New(store indexer.Store) *Explorer {
+	return &Explorer{
+		store: store,
+	}
+}
+
+// Transaction returns the record of a privacy marker transaction.
+func (e *Explorer) Transaction(ctx context.Context, pmtHash common.Hash) (*indexer.Record, error) {
+	records, err := e.store.Query(ctx, &indexer.Filter{TxHash: &pmtHash, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, ethereum.NotFound
+	}
+	return records[0], nil
+}
+
+// TransactionsByGroup .
+func (e *Explorer) TransactionsByGroup(ctx context.Context, privacyGroupID string, limit int) ([]*indexer.Record, error) {
+	return e.store.Query(ctx, &indexer.Filter{PrivacyGroupID: privacyGroupID, Limit: limit})
+}
+
+// TransactionsByContract returns the transactions calling or creating contract.
+func (e *Explorer) TransactionsByContract(ctx context.Context, contract common.Address, limit int) ([]*indexer.Record, error) {
+	return e.store.Query(ctx, &indexer.Filter{Contract: &contract, Limit: limit})
+}
+
+// TransactionsBySender .
+func (e *Explorer) TransactionsBySender(ctx context.Context, sender common.Address, limit int) ([]*indexer.Record, error) {
+	return e.store.Query(ctx, &indexer.Filter{From: &sender, Limit: limit})
+}
+
+// TransactionsByTimeRange returns the transactions of blocks mined between from and to inclusive.
+func (e *Explorer) TransactionsByTimeRange(ctx context.Context, from, to time.Time, limit int) ([]*indexer.Record, error) {
+	return e.store.Query(ctx, &indexer.Filter{FromTime: uint64(from.Unix()), ToTime: uint64(to.Unix()), Limit: limit})
+}
+
+// Query runs an arbitrary filter.
+func (e *Explorer) Query(ctx context.Context, filter *indexer.Filter) ([]*indexer.Record, error) {
+	return e.store.Query(ctx, filter)
+}
@@ -0,0 +1,178 @@
+// Package flexible manages the members of flexible, i.e. onchain, privacy
+// groups through the group management contract in their private state.
+package flexible
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bsostech/go-besu/client"
+	"github.com/bsostech/go-besu/privacy"
+	"github.com/bsostech/go-besu/types"
+)
+
+// ManagementAddress is the address of the proxy of the group management
+// contract in the private state of flexible groups.
+var ManagementAddress = common.HexToAddress("0x000000000000000000000000000000000000007c")
+
+// ManagementABI is the interface of the group management contract.
+const ManagementABI = `[{"constant":false,"inputs":[{"name":"_publicEnclaveKeys","type":"bytes32[]"}],"name":"addParticipants","outputs":[{"name":"","type":"bool"}],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":false,"inputs":[{"name":"_participant","type":"bytes32"}],"name":"removeParticipant","outputs":[{"name":"","type":"bool"}],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":true,"inputs":[],"name":"getParticipants","outputs":[{"name":"","type":"bytes32[]"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":false,"inputs":[],"name":"lock","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":false,"inputs":[],"name":"unlock","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":true,"inputs":[],"name":"canExecute","outputs":[{"name":"","type":"bool"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[],"name":"getVersion","outputs":[{"name":"","type":"bytes32"}],"payable":false,"stateMutability":"view","type":"function"}]`
+
+// Admin sends the management transactions of a flexible group. The
+// management contract only accepts them from the account which created the
+// group.
+type Admin struct {
+	Profile types.NetworkProfile
+
+	client  *client.Client
+	key     *ecdsa.PrivateKey
+	account common.Address
+	chainID *big.Int
+	self    privacy.PublicKey
+	group   *privacy.Group
+	abi     abi.ABI
+}
+
+// NewAdmin returns an admin of group sending with key from self, with the
+// defaults of types.FreeGasNetwork.
+func NewAdmin(c *client.Client, key *ecdsa.PrivateKey, chainID *big.Int, self privacy.PublicKey, group *privacy.Group) (*Admin, error) {
+	parsed, err := abi.JSON(strings.NewReader(ManagementABI))
+	if err != nil {
+		return nil, err
+	}
+	return &Admin{
+		Profile: types.FreeGasNetwork,
+		client:  c,
+		key:     key,
+		account: crypto.PubkeyToAddress(key.PublicKey),
+		chainID: chainID,
+		self:    self,
+		group:   group,
+		abi:     parsed,
+	}, nil
+}
+
+// Group returns the managed group.
+func (a *Admin) Group() *privacy.Group {
+	return a.group
+}
+
+// AddMembers adds members to the group and waits for the private receipt.
+func (a *Admin) AddMembers(ctx context.Context, members ...privacy.PublicKey) (*types.PrivateReceipt, error) {
+	keys := make([][32]byte, len(members))
+	for i, m := range members {
+		key, err := toBytes32(m)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = key
+	}
+	return a.transact(ctx, "addParticipants", keys)
+}
+
+// RemoveMember removes member from the group and waits for the private receipt.
+func (a *Admin) RemoveMember(ctx context.Context, member privacy.PublicKey) (*types.PrivateReceipt, error) {
+	key, err := toBytes32(member)
+	if err != nil {
+		return nil, err
+	}
+	return a.transact(ctx, "removeParticipant", key)
+}
+
+// Lock locks the group against other changes until the next membership change.
+func (a *Admin) Lock(ctx context.Context) (*types.PrivateReceipt, error) {
+	return a.transact(ctx, "lock")
+}
+
+// Unlock .
+func (a *Admin) Unlock(ctx context.Context) (*types.PrivateReceipt, error) {
+	return a.transact(ctx, "unlock")
+}
+
+// ListMembers returns the members of the group in the latest private state.
+func (a *Admin) ListMembers(ctx context.Context) ([]privacy.PublicKey, error) {
+	var keys [][32]byte
+	if err := a.call(ctx, &keys, "getParticipants"); err != nil {
+		return nil, err
+	}
+	members := make([]privacy.PublicKey, len(keys))
+	for i := range keys {
+		members[i] = privacy.PublicKey(common.CopyBytes(keys[i][:]))
+	}
+	return members, nil
+}
+
+// CanExecute reports whether the group is unlocked, i.e. accepts transactions.
+func (a *Admin) CanExecute(ctx context.Context) (bool, error) {
+	var ok bool
+	if err := a.call(ctx, &ok, "canExecute"); err != nil {
+		return false, err
+	}
+	return ok, nil
+}
+
+func (a *Admin) call(ctx context.Context, out interface{}, method string) error {
+	input, err := a.abi.Pack(method)
+	if err != nil {
+		return err
+	}
+	to := ManagementAddress
+	output, err := a.client.PrivateCallAt(ctx, a.group.ID, ethereum.CallMsg{From: a.account, To: &to, Data: input}, nil)
+	if err != nil {
+		return err
+	}
+	return a.abi.Unpack(out, method, output)
+}
+
+func (a *Admin) transact(ctx context.Context, method string, args ...interface{}) (*types.PrivateReceipt, error) {
+	input, err := a.abi.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	groupID, err := base64.StdEncoding.DecodeString(a.group.ID)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := a.client.NextNonce(a.account, a.group)
+	if err != nil {
+		return nil, err
+	}
+	to := ManagementAddress
+	tx := a.Profile.NewPrivateTransaction(nonce, &to, nil, input, a.self, groupID)
+	signed, err := tx.SignTx(a.chainID, a.key)
+	if err != nil {
+		a.client.ResetNonce(a.account, a.group)
+		return nil, err
+	}
+	pmtHash, err := a.client.SendTransaction(ctx, signed)
+	if err != nil {
+		a.client.ResetNonce(a.account, a.group)
+		return nil, err
+	}
+	receipt, err := a.client.WaitForReceipt(ctx, pmtHash)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status != 1 {
+		return receipt, fmt.Errorf("%v failed: %v", method, receipt.FailureReason())
+	}
+	return receipt, nil
+}
+
+func toBytes32(key privacy.PublicKey) ([32]byte, error) {
+	var b [32]byte
+	if len(key) != len(b) {
+		return b, fmt.Errorf("invalid enclave key %v", key.ToString())
+	}
+	copy(b[:], key)
+	return b, nil
+}
@@ -0,0 +1,95 @@
+package flexible
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/bsostech/go-besu/privacy"
+)
+
+// ErrGroupLocked means the group is locked by a membership change in progress,
+// possibly of another service.
+var ErrGroupLocked = errors.New("privacy group locked")
+
+// MembershipChange changes the members of a group through a, given its
+// current members, and returns the members expected afterwards.
+type MembershipChange func(ctx context.Context, a *Admin, members privacy.ParticipantSet) (privacy.ParticipantSet, error)
+
+// groupLocks serializes the membership changes of a group within the process.
+var groupLocks = struct {
+	sync.Mutex
+	locks map[string]*sync.Mutex
+}{locks: make(map[string]*sync.Mutex)}
+
+func groupLock(groupID string) *sync.Mutex {
+	groupLocks.Lock()
+	defer groupLocks.Unlock()
+	lock, ok := groupLocks.locks[groupID]
+	if !ok {
+		lock = new(sync.Mutex)
+		groupLocks.locks[groupID] = lock
+	}
+	return lock
+}
+
+// WithMembershipLock runs change with the group locked: it fails with
+// ErrGroupLocked if the group is already locked, locks it, runs change, and
+// checks the resulting members are the ones change expects. The group is
+// unlocked again if change leaves it locked, e.g. on failure. Changes of the
+// same group within the process are serialized.
+func (a *Admin) WithMembershipLock(ctx context.Context, change MembershipChange) error {
+	lock := groupLock(a.group.ID)
+	lock.Lock()
+	defer lock.Unlock()
+	unlocked, err := a.CanExecute(ctx)
+	if err != nil {
+		return err
+	}
+	if !unlocked {
+		return ErrGroupLocked
+	}
+	current, err := a.ListMembers(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := a.Lock(ctx); err != nil {
+		return err
+	}
+	want, err := change(ctx, a, participantSet(current))
+	if unlockErr := a.ensureUnlocked(ctx); unlockErr != nil && err == nil {
+		err = unlockErr
+	}
+	if err != nil {
+		return err
+	}
+	got, err := a.ListMembers(ctx)
+	if err != nil {
+		return err
+	}
+	if !participantSet(got).Equal(want) {
+		return fmt.Errorf("members are %v, expected %v", participantSet(got), want)
+	}
+	return nil
+}
+
+func (a *Admin) ensureUnlocked(ctx context.Context) error {
+	unlocked, err := a.CanExecute(ctx)
+	if err != nil {
+		return err
+	}
+	if unlocked {
+		return nil
+	}
+	_, err = a.Unlock(ctx)
+	return err
+}
+
+func participantSet(keys []privacy.PublicKey) privacy.ParticipantSet {
+	ptrs := make([]*privacy.PublicKey, len(keys))
+	for i := range keys {
+		ptrs[i] = &keys[i]
+	}
+	return privacy.NewParticipantSet(ptrs...)
+}
@@ -4,5 +4,7 @@ go 1.13
 
 require (
 	github.com/ethereum/go-ethereum v1.9.13
+	github.com/syndtr/goleveldb v1.0.1-0.20190923125748-758128399b1d
 	golang.org/x/crypto v0.0.0-20200510223506-06a226fb4e37
+	gopkg.in/yaml.v2 v2.2.2
 )
@@ -0,0 +1,156 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultBlockFields is the selection used by Block when no field is given.
+var DefaultBlockFields = []string{"number", "hash", "timestamp", "transactionCount"}
+
+// DefaultTransactionFields is the selection used by Transaction when no field is given.
+var DefaultTransactionFields = []string{"hash", "index", "from { address }", "to { address }", "inputData", "status", "block { number hash }"}
+
+// Client queries the GraphQL endpoint of a Besu node.
+type Client struct {
+	endpoint   string
+	httpClient *http.Client
+}
+
+// Error .
+type Error struct {
+	Messages []string
+}
+
+type request struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// NewClient returns a client for endpoint, e.g. http://localhost:8547/graphql.
+// http.DefaultClient is used if httpClient is nil.
+func NewClient(endpoint string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		endpoint:   endpoint,
+		httpClient: httpClient,
+	}
+}
+
+// Query runs query and decodes the data field into out.
+func (c *Client) Query(ctx context.Context, query string, variables map[string]interface{}, out interface{}) error {
+	body, err := json.Marshal(&request{Query: query, Variables: variables})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("graphql request failed: %v", resp.Status)
+	}
+	var rsp response
+	if err := json.NewDecoder(resp.Body).Decode(&rsp); err != nil {
+		return err
+	}
+	if len(rsp.Errors) > 0 {
+		e := &Error{}
+		for _, v := range rsp.Errors {
+			e.Messages = append(e.Messages, v.Message)
+		}
+		return e
+	}
+	if out == nil || len(rsp.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(rsp.Data, out)
+}
+
+// Block returns the selected fields of a block, the latest one if number is nil.
+func (c *Client) Block(ctx context.Context, number *big.Int, fields ...string) (map[string]interface{}, error) {
+	if len(fields) == 0 {
+		fields = DefaultBlockFields
+	}
+	args := ""
+	if number != nil {
+		args = fmt.Sprintf("(number: %v)", number)
+	}
+	var rsp struct {
+		Block map[string]interface{} `json:"block"`
+	}
+	query := fmt.Sprintf("{ block%s { %s } }", args, selection(fields))
+	if err := c.Query(ctx, query, nil, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp.Block, nil
+}
+
+// BlockTransactions returns the selected fields of all transactions in a block.
+func (c *Client) BlockTransactions(ctx context.Context, number *big.Int, fields ...string) ([]map[string]interface{}, error) {
+	if len(fields) == 0 {
+		fields = DefaultTransactionFields
+	}
+	block, err := c.Block(ctx, number, fmt.Sprintf("transactions { %s }", selection(fields)))
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(block["transactions"])
+	if err != nil {
+		return nil, err
+	}
+	var txs []map[string]interface{}
+	if err := json.Unmarshal(raw, &txs); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+// Transaction returns the selected fields of a transaction.
+func (c *Client) Transaction(ctx context.Context, hash common.Hash, fields ...string) (map[string]interface{}, error) {
+	if len(fields) == 0 {
+		fields = DefaultTransactionFields
+	}
+	var rsp struct {
+		Transaction map[string]interface{} `json:"transaction"`
+	}
+	query := fmt.Sprintf("{ transaction(hash: %q) { %s } }", hash.Hex(), selection(fields))
+	if err := c.Query(ctx, query, nil, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp.Transaction, nil
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("graphql: %v", strings.Join(e.Messages, "; "))
+}
+
+func selection(fields []string) string {
+	return strings.Join(fields, " ")
+}
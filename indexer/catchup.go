@@ -0,0 +1,67 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+)
+
+// Handler processes a private transaction on behalf of the application.
+type Handler func(ctx context.Context, r *Record) error
+
+// CatchupReport is the outcome of Catchup.
+type CatchupReport struct {
+	From, To uint64 // blocks scanned
+	Replayed int    // records passed to the handler
+	Skipped  int    // records already in the store
+}
+
+// Catchup replays the private transactions the application missed, e.g.
+// while it was down. It scans blocks from fromBlock up to the latest block
+// for privacy marker transactions the node is a participant of, and passes
+// those not yet in the store to h in block order, then stores them and
+// advances the checkpoint. Records already in the store were processed and
+// are skipped, so fromBlock may lie before the checkpoint to recheck blocks.
+// A record is stored only once h succeeded: delivery is at least once, h has
+// to be idempotent. Catchup stops at the first error of h.
+func (ix *Indexer) Catchup(ctx context.Context, fromBlock uint64, h Handler) (*CatchupReport, error) {
+	checkpoint, indexed, err := ix.store.Checkpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+	header, err := ix.client.Eth().HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	report := &CatchupReport{From: fromBlock, To: header.Number.Uint64()}
+	for n := fromBlock; n <= report.To; n++ {
+		records, err := ix.records(ctx, n)
+		if err != nil {
+			return report, err
+		}
+		for _, r := range records {
+			processed, err := ix.store.Query(ctx, &Filter{TxHash: &r.TxHash, Limit: 1})
+			if err != nil {
+				return report, err
+			}
+			if len(processed) > 0 {
+				report.Skipped++
+				continue
+			}
+			ix.Decode([]*Record{r})
+			if err := h(ctx, r); err != nil {
+				return report, fmt.Errorf("failed to replay %v of block %v, err: %v", r.TxHash.Hex(), n, err)
+			}
+			if err := ix.put(ctx, []*Record{r}); err != nil {
+				return report, err
+			}
+			report.Replayed++
+		}
+		if !indexed || n > checkpoint {
+			if err := ix.store.SetCheckpoint(ctx, n); err != nil {
+				return report, err
+			}
+			checkpoint, indexed = n, true
+		}
+	}
+	return report, nil
+}
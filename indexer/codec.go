@@ -0,0 +1,79 @@
+package indexer
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/bsostech/go-besu/labels"
+	"github.com/bsostech/go-besu/types"
+)
+
+type storedRecord struct {
+	TxHash          common.Hash           `json:"txHash"`
+	BlockHash       common.Hash           `json:"blockHash"`
+	BlockNumber     uint64                `json:"blockNumber"`
+	BlockTime       uint64                `json:"blockTime"`
+	Index           uint64                `json:"index"`
+	PrivacyGroupID  string                `json:"privacyGroupId"`
+	From            common.Address        `json:"from"`
+	To              *common.Address       `json:"to"`
+	ContractAddress common.Address        `json:"contractAddress"`
+	Status          uint64                `json:"status"`
+	Transaction     hexutil.Bytes         `json:"transaction"`
+	Receipt         *types.PrivateReceipt `json:"receipt"`
+	Labels          labels.Labels         `json:"labels,omitempty"`
+}
+
+// MarshalRecord encodes r as JSON, the transaction as RLP. The decoded call
+// and events are not encoded.
+func MarshalRecord(r *Record) ([]byte, error) {
+	rawTx, err := rlp.EncodeToBytes(r.Transaction)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&storedRecord{
+		TxHash:          r.TxHash,
+		BlockHash:       r.BlockHash,
+		BlockNumber:     r.BlockNumber,
+		BlockTime:       r.BlockTime,
+		Index:           r.Index,
+		PrivacyGroupID:  r.PrivacyGroupID,
+		From:            r.From,
+		To:              r.To,
+		ContractAddress: r.ContractAddress,
+		Status:          r.Status,
+		Transaction:     rawTx,
+		Receipt:         r.Receipt,
+		Labels:          r.Labels,
+	})
+}
+
+// UnmarshalRecord decodes a record encoded by MarshalRecord.
+func UnmarshalRecord(data []byte) (*Record, error) {
+	var stored storedRecord
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, err
+	}
+	tx := new(types.PrivateTransaction)
+	if err := rlp.DecodeBytes(stored.Transaction, tx); err != nil {
+		return nil, err
+	}
+	return &Record{
+		TxHash:          stored.TxHash,
+		BlockHash:       stored.BlockHash,
+		BlockNumber:     stored.BlockNumber,
+		BlockTime:       stored.BlockTime,
+		Index:           stored.Index,
+		PrivacyGroupID:  stored.PrivacyGroupID,
+		From:            stored.From,
+		To:              stored.To,
+		ContractAddress: stored.ContractAddress,
+		Status:          stored.Status,
+		Transaction:     tx,
+		Receipt:         stored.Receipt,
+		Labels:          stored.Labels,
+	}, nil
+}
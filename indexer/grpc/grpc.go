@@ -0,0 +1,239 @@
+// Package grpc streams indexed private transactions to gRPC clients, so that
+// services in other languages can consume them with stubs generated from
+// indexer.proto. The package does not depend on google.golang.org/grpc: the
+// caller registers the service on its server, forcing Codec for the messages
+// of this package, e.g.
+//
+//	srv := grpc.NewServer(grpc.ForceServerCodec(besugrpc.Codec{}))
+//	srv.RegisterService(&grpc.ServiceDesc{
+//		ServiceName: besugrpc.ServiceName,
+//		HandlerType: (*interface{})(nil),
+//		Streams: []grpc.StreamDesc{{
+//			StreamName:    "SubscribeReceipts",
+//			Handler:       func(_ interface{}, s grpc.ServerStream) error { return sink.SubscribeReceipts(s) },
+//			ServerStreams: true,
+//		}, {
+//			StreamName:    "SubscribeEvents",
+//			Handler:       func(_ interface{}, s grpc.ServerStream) error { return sink.SubscribeEvents(s) },
+//			ServerStreams: true,
+//		}},
+//	}, nil)
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/bsostech/go-besu/indexer"
+)
+
+// ServiceName is the full name of the service in indexer.proto.
+const ServiceName = "besu.indexer.v1.Indexer"
+
+// DefaultBuffer is the default number of messages buffered per subscriber.
+const DefaultBuffer = 256
+
+var (
+	// ErrSlowSubscriber ends the stream of a subscriber whose buffer is full.
+	ErrSlowSubscriber = errors.New("subscriber too slow")
+	// ErrClosed ends the streams of a closed Sink.
+	ErrClosed = errors.New("sink closed")
+)
+
+// Message is a message of the service.
+type Message interface {
+	Marshal() ([]byte, error)
+	Unmarshal(data []byte) error
+}
+
+// Codec encodes the messages of this package, it implements the
+// encoding.Codec interface of google.golang.org/grpc.
+type Codec struct{}
+
+// Marshal .
+func (Codec) Marshal(v interface{}) ([]byte, error) {
+	m, ok := v.(Message)
+	if !ok {
+		return nil, fmt.Errorf("unsupported message type %T", v)
+	}
+	return m.Marshal()
+}
+
+// Unmarshal .
+func (Codec) Unmarshal(data []byte, v interface{}) error {
+	m, ok := v.(Message)
+	if !ok {
+		return fmt.Errorf("unsupported message type %T", v)
+	}
+	return m.Unmarshal(data)
+}
+
+// Name returns "proto", the messages are encoded in the protobuf wire format.
+func (Codec) Name() string {
+	return "proto"
+}
+
+// ServerStream is the server side of a stream, implemented by
+// grpc.ServerStream.
+type ServerStream interface {
+	Context() context.Context
+	SendMsg(m interface{}) error
+	RecvMsg(m interface{}) error
+}
+
+// Config configures a Sink.
+type Config struct {
+	Buffer int // messages buffered per subscriber, DefaultBuffer if 0
+}
+
+// Sink is an indexer.Sink streaming records to the subscribers of the
+// service. Subscribers only receive the records published after they
+// subscribed; a subscriber which falls behind by more than its buffer is
+// disconnected with ErrSlowSubscriber rather than blocking the indexer.
+type Sink struct {
+	cfg Config
+
+	mu     sync.Mutex
+	subs   map[*subscriber]struct{}
+	closed bool
+}
+
+type subscriber struct {
+	groupID string
+	events  bool // events rather than receipts
+	msgs    chan Message
+	err     error // set before msgs is closed
+}
+
+// NewSink .
+func NewSink(cfg Config) *Sink {
+	if cfg.Buffer == 0 {
+		cfg.Buffer = DefaultBuffer
+	}
+	return &Sink{
+		cfg:  cfg,
+		subs: make(map[*subscriber]struct{}),
+	}
+}
+
+// Publish implements indexer.Sink. Records are not redelivered to
+// subscribers which were disconnected.
+func (s *Sink) Publish(ctx context.Context, records []*indexer.Record) error {
+	for _, r := range records {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		receipt, err := NewReceipt(r)
+		if err != nil {
+			return err
+		}
+		events, err := NewEvents(r)
+		if err != nil {
+			return err
+		}
+		s.mu.Lock()
+		for sub := range s.subs {
+			if sub.groupID != "" && sub.groupID != r.PrivacyGroupID {
+				continue
+			}
+			if !sub.events {
+				s.send(sub, receipt)
+				continue
+			}
+			for _, e := range events {
+				if !s.send(sub, e) {
+					break
+				}
+			}
+		}
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+// send queues msg to sub, disconnecting sub if its buffer is full. It must
+// be called with s.mu held.
+func (s *Sink) send(sub *subscriber, msg Message) bool {
+	select {
+	case sub.msgs <- msg:
+		return true
+	default:
+		s.remove(sub, ErrSlowSubscriber)
+		return false
+	}
+}
+
+// remove ends the stream of sub with err. It must be called with s.mu held.
+func (s *Sink) remove(sub *subscriber, err error) {
+	if _, ok := s.subs[sub]; !ok {
+		return
+	}
+	delete(s.subs, sub)
+	sub.err = err
+	close(sub.msgs)
+}
+
+// SubscribeReceipts serves the SubscribeReceipts stream until the client
+// cancels it or the sink is closed.
+func (s *Sink) SubscribeReceipts(stream ServerStream) error {
+	return s.subscribe(stream, false)
+}
+
+// SubscribeEvents serves the SubscribeEvents stream until the client cancels
+// it or the sink is closed.
+func (s *Sink) SubscribeEvents(stream ServerStream) error {
+	return s.subscribe(stream, true)
+}
+
+func (s *Sink) subscribe(stream ServerStream, events bool) error {
+	req := new(SubscribeRequest)
+	if err := stream.RecvMsg(req); err != nil {
+		return err
+	}
+	sub := &subscriber{
+		groupID: req.PrivacyGroupID,
+		events:  events,
+		msgs:    make(chan Message, s.cfg.Buffer),
+	}
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrClosed
+	}
+	s.subs[sub] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.remove(sub, nil)
+		s.mu.Unlock()
+	}()
+
+	ctx := stream.Context()
+	for {
+		select {
+		case msg, ok := <-sub.msgs:
+			if !ok {
+				return sub.err
+			}
+			if err := stream.SendMsg(msg); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Close ends the streams of the subscribers with ErrClosed, so that the
+// server can stop gracefully. Later subscriptions fail.
+func (s *Sink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for sub := range s.subs {
+		s.remove(sub, ErrClosed)
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/bsostech/go-besu/enrich"
+	"github.com/bsostech/go-besu/indexer"
+)
+
+// fakeStream is a ServerStream encoding the sent messages with Codec.
+type fakeStream struct {
+	ctx   context.Context
+	req   []byte
+	sent  chan []byte
+	block chan struct{} // SendMsg blocks until closed, if not nil
+}
+
+func newFakeStream(ctx context.Context, groupID string) *fakeStream {
+	req, _ := (&SubscribeRequest{PrivacyGroupID: groupID}).Marshal()
+	return &fakeStream{ctx: ctx, req: req, sent: make(chan []byte, 16)}
+}
+
+func (f *fakeStream) Context() context.Context { return f.ctx }
+
+func (f *fakeStream) RecvMsg(m interface{}) error {
+	return Codec{}.Unmarshal(f.req, m)
+}
+
+func (f *fakeStream) SendMsg(m interface{}) error {
+	if f.block != nil {
+		<-f.block
+	}
+	data, err := Codec{}.Marshal(m)
+	if err != nil {
+		return err
+	}
+	f.sent <- data
+	return nil
+}
+
+func (f *fakeStream) next(t *testing.T) []byte {
+	t.Helper()
+	select {
+	case data := <-f.sent:
+		return data
+	case <-time.After(time.Second):
+		t.Fatal("no message sent")
+		return nil
+	}
+}
+
+// serve runs subscribe and waits until the sink has n subscribers.
+func serve(t *testing.T, s *Sink, n int, subscribe func() error) <-chan error {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() { done <- subscribe() }()
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(time.Millisecond) {
+		s.mu.Lock()
+		subscribed := len(s.subs)
+		s.mu.Unlock()
+		if subscribed == n {
+			return done
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%v subscribers, expected %v", subscribed, n)
+		}
+	}
+}
+
+func wait(t *testing.T, done <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(time.Second):
+		t.Fatal("stream not ended")
+		return nil
+	}
+}
+
+func testRecord(groupID string, block uint64) *indexer.Record {
+	to := common.HexToAddress("0x2")
+	return &indexer.Record{
+		TxHash:         common.BigToHash(common.Big1),
+		BlockNumber:    block,
+		PrivacyGroupID: groupID,
+		From:           common.HexToAddress("0x1"),
+		To:             &to,
+		Status:         1,
+		Call:           &enrich.Call{Address: to, Method: "set", Args: map[string]interface{}{"v": 7}},
+		Events: []*enrich.Event{{
+			Address: to,
+			Name:    "Set",
+			Log: &ethtypes.Log{
+				Topics: []common.Hash{common.BigToHash(common.Big2)},
+				Data:   []byte{1, 2},
+				Index:  3,
+			},
+		}},
+	}
+}
+
+func TestWireFormat(t *testing.T) {
+	data, err := (&SubscribeRequest{PrivacyGroupID: "abc"}).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, []byte{0x0a, 3, 'a', 'b', 'c'}) {
+		t.Fatalf("unexpected encoding %x", data)
+	}
+	data, _ = (&Receipt{BlockNumber: 300, Call: &Call{}}).Marshal()
+	if !bytes.Equal(data, []byte{0x18, 0xac, 0x02, 0x5a, 0}) {
+		t.Fatalf("unexpected encoding %x", data)
+	}
+
+	r := testRecord("group", 5)
+	receipt, err := NewReceipt(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.Call.ArgsJSON != `{"v":7}` {
+		t.Fatalf("unexpected args %v", receipt.Call.ArgsJSON)
+	}
+	events, err := NewEvents(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range []Message{receipt, events[0]} {
+		data, err := m.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded := reflect.New(reflect.TypeOf(m).Elem()).Interface().(Message)
+		if err := decoded.Unmarshal(data); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(decoded, m) {
+			t.Fatalf("decoded %+v, expected %+v", decoded, m)
+		}
+	}
+	if err := new(Receipt).Unmarshal([]byte{0x0a, 5, 1}); err == nil {
+		t.Fatal("expected error for truncated field")
+	}
+}
+
+func TestSinkStreams(t *testing.T) {
+	s := NewSink(Config{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	receipts := newFakeStream(ctx, "")
+	events := newFakeStream(ctx, "a")
+	receiptsDone := serve(t, s, 1, func() error { return s.SubscribeReceipts(receipts) })
+	serve(t, s, 2, func() error { return s.SubscribeEvents(events) })
+
+	if err := s.Publish(ctx, []*indexer.Record{testRecord("b", 1), testRecord("a", 2)}); err != nil {
+		t.Fatal(err)
+	}
+	for _, block := range []uint64{1, 2} {
+		var receipt Receipt
+		if err := receipt.Unmarshal(receipts.next(t)); err != nil {
+			t.Fatal(err)
+		}
+		if receipt.BlockNumber != block {
+			t.Fatalf("receipt of block %v, expected %v", receipt.BlockNumber, block)
+		}
+	}
+	var event Event
+	if err := event.Unmarshal(events.next(t)); err != nil {
+		t.Fatal(err)
+	}
+	if event.PrivacyGroupID != "a" || event.Name != "Set" || event.LogIndex != 3 {
+		t.Fatalf("unexpected event %+v", event)
+	}
+	select {
+	case data := <-events.sent:
+		t.Fatalf("unexpected event of other group %x", data)
+	default:
+	}
+
+	cancel()
+	if err := wait(t, receiptsDone); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestSlowSubscriber(t *testing.T) {
+	s := NewSink(Config{Buffer: 1})
+	stream := newFakeStream(context.Background(), "")
+	stream.block = make(chan struct{})
+	done := serve(t, s, 1, func() error { return s.SubscribeReceipts(stream) })
+
+	// The first receipt may be taken by the blocked SendMsg, the buffer
+	// holds one more.
+	records := []*indexer.Record{testRecord("a", 1), testRecord("a", 2), testRecord("a", 3)}
+	if err := s.Publish(context.Background(), records); err != nil {
+		t.Fatal(err)
+	}
+	close(stream.block)
+	if err := wait(t, done); err != ErrSlowSubscriber {
+		t.Fatalf("expected ErrSlowSubscriber, got %v", err)
+	}
+}
+
+func TestClose(t *testing.T) {
+	s := NewSink(Config{})
+	stream := newFakeStream(context.Background(), "")
+	done := serve(t, s, 1, func() error { return s.SubscribeEvents(stream) })
+
+	s.Close()
+	if err := wait(t, done); err != ErrClosed {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+	if err := s.SubscribeReceipts(stream); err != ErrClosed {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
@@ -0,0 +1,324 @@
+package grpc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/bsostech/go-besu/enrich"
+	"github.com/bsostech/go-besu/indexer"
+)
+
+// SubscribeRequest is the request of both streams of the service.
+type SubscribeRequest struct {
+	PrivacyGroupID string // all groups if empty
+}
+
+// Call is the decoded call of a receipt.
+type Call struct {
+	Address      []byte
+	ContractName string
+	Method       string
+	Signature    string
+	ArgsJSON     string
+}
+
+// Receipt is the streamed form of a record.
+type Receipt struct {
+	TransactionHash  []byte
+	BlockHash        []byte
+	BlockNumber      uint64
+	BlockTime        uint64
+	TransactionIndex uint64
+	PrivacyGroupID   string
+	From             []byte
+	To               []byte // empty for contract creations
+	ContractAddress  []byte
+	Status           uint64
+	Call             *Call
+	Events           uint32
+}
+
+// Event is the streamed form of a decoded event of a record.
+type Event struct {
+	TransactionHash []byte
+	BlockNumber     uint64
+	BlockTime       uint64
+	PrivacyGroupID  string
+	LogIndex        uint32
+	Address         []byte
+	ContractName    string
+	Name            string
+	Signature       string
+	ArgsJSON        string
+	Topics          [][]byte
+	Data            []byte
+}
+
+// NewReceipt returns the receipt of r.
+func NewReceipt(r *indexer.Record) (*Receipt, error) {
+	msg := indexer.NewReceiptMessage(r)
+	receipt := &Receipt{
+		TransactionHash:  msg.TxHash.Bytes(),
+		BlockHash:        msg.BlockHash.Bytes(),
+		BlockNumber:      msg.BlockNumber,
+		BlockTime:        msg.BlockTime,
+		TransactionIndex: msg.Index,
+		PrivacyGroupID:   msg.PrivacyGroupID,
+		From:             msg.From.Bytes(),
+		ContractAddress:  msg.ContractAddress.Bytes(),
+		Status:           msg.Status,
+		Events:           uint32(msg.Events),
+	}
+	if msg.To != nil {
+		receipt.To = msg.To.Bytes()
+	}
+	if msg.Call != nil {
+		call, err := newCall(msg.Call)
+		if err != nil {
+			return nil, err
+		}
+		receipt.Call = call
+	}
+	return receipt, nil
+}
+
+func newCall(c *enrich.Call) (*Call, error) {
+	args, err := argsJSON(c.Args)
+	if err != nil {
+		return nil, err
+	}
+	return &Call{
+		Address:      c.Address.Bytes(),
+		ContractName: c.ContractName,
+		Method:       c.Method,
+		Signature:    c.Signature,
+		ArgsJSON:     args,
+	}, nil
+}
+
+// NewEvents returns the decoded events of r.
+func NewEvents(r *indexer.Record) ([]*Event, error) {
+	msgs := indexer.NewEventMessages(r)
+	events := make([]*Event, 0, len(msgs))
+	for _, msg := range msgs {
+		args, err := argsJSON(msg.Event.Args)
+		if err != nil {
+			return nil, err
+		}
+		event := &Event{
+			TransactionHash: msg.TxHash.Bytes(),
+			BlockNumber:     msg.BlockNumber,
+			BlockTime:       msg.BlockTime,
+			PrivacyGroupID:  msg.PrivacyGroupID,
+			LogIndex:        uint32(msg.LogIndex),
+			Address:         msg.Event.Address.Bytes(),
+			ContractName:    msg.Event.ContractName,
+			Name:            msg.Event.Name,
+			Signature:       msg.Event.Signature,
+			ArgsJSON:        args,
+		}
+		if l := msg.Event.Log; l != nil {
+			for _, topic := range l.Topics {
+				event.Topics = append(event.Topics, topic.Bytes())
+			}
+			event.Data = l.Data
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func argsJSON(args map[string]interface{}) (string, error) {
+	if len(args) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(args)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode args, err: %v", err)
+	}
+	return string(data), nil
+}
+
+// Marshal encodes m in the protobuf wire format.
+func (m *SubscribeRequest) Marshal() ([]byte, error) {
+	return appendString(nil, 1, m.PrivacyGroupID), nil
+}
+
+// Unmarshal decodes m from the protobuf wire format.
+func (m *SubscribeRequest) Unmarshal(data []byte) error {
+	*m = SubscribeRequest{}
+	return decodeFields(data, func(field int, wireType int, v uint64, b []byte) error {
+		if field == 1 && wireType == wireBytes {
+			m.PrivacyGroupID = string(b)
+		}
+		return nil
+	})
+}
+
+// Marshal encodes m in the protobuf wire format.
+func (m *Call) Marshal() ([]byte, error) {
+	var b []byte
+	b = appendBytes(b, 1, m.Address)
+	b = appendString(b, 2, m.ContractName)
+	b = appendString(b, 3, m.Method)
+	b = appendString(b, 4, m.Signature)
+	b = appendString(b, 5, m.ArgsJSON)
+	return b, nil
+}
+
+// Unmarshal decodes m from the protobuf wire format.
+func (m *Call) Unmarshal(data []byte) error {
+	*m = Call{}
+	return decodeFields(data, func(field int, wireType int, v uint64, b []byte) error {
+		if wireType != wireBytes {
+			return nil
+		}
+		switch field {
+		case 1:
+			m.Address = copyBytes(b)
+		case 2:
+			m.ContractName = string(b)
+		case 3:
+			m.Method = string(b)
+		case 4:
+			m.Signature = string(b)
+		case 5:
+			m.ArgsJSON = string(b)
+		}
+		return nil
+	})
+}
+
+// Marshal encodes m in the protobuf wire format.
+func (m *Receipt) Marshal() ([]byte, error) {
+	var b []byte
+	b = appendBytes(b, 1, m.TransactionHash)
+	b = appendBytes(b, 2, m.BlockHash)
+	b = appendUint(b, 3, m.BlockNumber)
+	b = appendUint(b, 4, m.BlockTime)
+	b = appendUint(b, 5, m.TransactionIndex)
+	b = appendString(b, 6, m.PrivacyGroupID)
+	b = appendBytes(b, 7, m.From)
+	b = appendBytes(b, 8, m.To)
+	b = appendBytes(b, 9, m.ContractAddress)
+	b = appendUint(b, 10, m.Status)
+	if m.Call != nil {
+		call, err := m.Call.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		// An empty call is still sent, to be set.
+		b = appendTag(b, 11, wireBytes)
+		b = appendVarint(b, uint64(len(call)))
+		b = append(b, call...)
+	}
+	b = appendUint(b, 12, uint64(m.Events))
+	return b, nil
+}
+
+// Unmarshal decodes m from the protobuf wire format.
+func (m *Receipt) Unmarshal(data []byte) error {
+	*m = Receipt{}
+	return decodeFields(data, func(field int, wireType int, v uint64, b []byte) error {
+		if wireType == wireVarint {
+			switch field {
+			case 3:
+				m.BlockNumber = v
+			case 4:
+				m.BlockTime = v
+			case 5:
+				m.TransactionIndex = v
+			case 10:
+				m.Status = v
+			case 12:
+				m.Events = uint32(v)
+			}
+			return nil
+		}
+		if wireType != wireBytes {
+			return nil
+		}
+		switch field {
+		case 1:
+			m.TransactionHash = copyBytes(b)
+		case 2:
+			m.BlockHash = copyBytes(b)
+		case 6:
+			m.PrivacyGroupID = string(b)
+		case 7:
+			m.From = copyBytes(b)
+		case 8:
+			m.To = copyBytes(b)
+		case 9:
+			m.ContractAddress = copyBytes(b)
+		case 11:
+			m.Call = new(Call)
+			return m.Call.Unmarshal(b)
+		}
+		return nil
+	})
+}
+
+// Marshal encodes m in the protobuf wire format.
+func (m *Event) Marshal() ([]byte, error) {
+	var b []byte
+	b = appendBytes(b, 1, m.TransactionHash)
+	b = appendUint(b, 2, m.BlockNumber)
+	b = appendUint(b, 3, m.BlockTime)
+	b = appendString(b, 4, m.PrivacyGroupID)
+	b = appendUint(b, 5, uint64(m.LogIndex))
+	b = appendBytes(b, 6, m.Address)
+	b = appendString(b, 7, m.ContractName)
+	b = appendString(b, 8, m.Name)
+	b = appendString(b, 9, m.Signature)
+	b = appendString(b, 10, m.ArgsJSON)
+	for _, topic := range m.Topics {
+		b = appendTag(b, 11, wireBytes)
+		b = appendVarint(b, uint64(len(topic)))
+		b = append(b, topic...)
+	}
+	b = appendBytes(b, 12, m.Data)
+	return b, nil
+}
+
+// Unmarshal decodes m from the protobuf wire format.
+func (m *Event) Unmarshal(data []byte) error {
+	*m = Event{}
+	return decodeFields(data, func(field int, wireType int, v uint64, b []byte) error {
+		if wireType == wireVarint {
+			switch field {
+			case 2:
+				m.BlockNumber = v
+			case 3:
+				m.BlockTime = v
+			case 5:
+				m.LogIndex = uint32(v)
+			}
+			return nil
+		}
+		if wireType != wireBytes {
+			return nil
+		}
+		switch field {
+		case 1:
+			m.TransactionHash = copyBytes(b)
+		case 4:
+			m.PrivacyGroupID = string(b)
+		case 6:
+			m.Address = copyBytes(b)
+		case 7:
+			m.ContractName = string(b)
+		case 8:
+			m.Name = string(b)
+		case 9:
+			m.Signature = string(b)
+		case 10:
+			m.ArgsJSON = string(b)
+		case 11:
+			m.Topics = append(m.Topics, append([]byte{}, b...))
+		case 12:
+			m.Data = copyBytes(b)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,208 @@
+package indexer
+
+import (
+	"context"
+	"encoding/base64"
+
+	"github.com/bsostech/go-besu/client"
+	"github.com/bsostech/go-besu/enrich"
+	"github.com/bsostech/go-besu/labels"
+	"github.com/bsostech/go-besu/privacy"
+)
+
+// Indexer stores the private transactions of the chain in a Store.
+type Indexer struct {
+	client   *client.Client
+	store    Store
+	labels   labels.Store
+	decoders *enrich.GroupRegistry
+	buffer   *BufferOptions
+}
+
+// New .
+func New(c *client.Client, store Store) *Indexer {
+	return &Indexer{
+		client: c,
+		store:  store,
+	}
+}
+
+// Store returns the store of the indexer.
+func (ix *Indexer) Store() Store {
+	return ix.store
+}
+
+// SetLabelStore sets the store the labels of records are read from, usually
+// the one set on the sending client.
+func (ix *Indexer) SetLabelStore(s labels.Store) {
+	ix.labels = s
+}
+
+// SetDecoders sets the ABIs the calls and events of records are decoded with.
+func (ix *Indexer) SetDecoders(r *enrich.GroupRegistry) {
+	ix.decoders = r
+}
+
+// Decode decodes the calls and events of records, e.g. those returned by a
+// Query, with the decoders of the indexer.
+func (ix *Indexer) Decode(records []*Record) {
+	if ix.decoders == nil {
+		return
+	}
+	for _, r := range records {
+		registry := ix.decoders.ForGroup(r.PrivacyGroupID)
+		if r.Transaction != nil {
+			r.Call = enrich.DecodeTransaction(r.Transaction, registry)
+		}
+		if r.Receipt != nil {
+			r.Events = enrich.EnrichReceipt(r.Receipt, registry).Events
+		}
+	}
+}
+
+// Index indexes blocks from to to inclusive, advancing the checkpoint after
+// each block, or each batch of blocks if a buffer is set.
+func (ix *Indexer) Index(ctx context.Context, from, to uint64) error {
+	if ix.buffer != nil && ix.buffer.BatchBlocks > 1 {
+		return ix.indexBatches(ctx, from, to)
+	}
+	for n := from; n <= to; n++ {
+		records, err := ix.records(ctx, n)
+		if err != nil {
+			return err
+		}
+		if len(records) > 0 {
+			ix.Decode(records)
+			if err := ix.put(ctx, records); err != nil {
+				return err
+			}
+		}
+		if err := ix.store.SetCheckpoint(ctx, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ix *Indexer) indexBatches(ctx context.Context, from, to uint64) error {
+	buf := newSpillBuffer(ix.buffer)
+	defer buf.close()
+	for n := from; n <= to; n++ {
+		records, err := ix.records(ctx, n)
+		if err != nil {
+			return err
+		}
+		if err := buf.add(records); err != nil {
+			return err
+		}
+		if (n-from+1)%ix.buffer.BatchBlocks != 0 && n != to {
+			continue
+		}
+		err = buf.drain(func(records []*Record) error {
+			ix.Decode(records)
+			return ix.put(ctx, records)
+		})
+		if err != nil {
+			return err
+		}
+		if err := ix.store.SetCheckpoint(ctx, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// put stores records, then deletes their labels from the label store if it
+// is a labels.Deleter, as they are kept with the records.
+func (ix *Indexer) put(ctx context.Context, records []*Record) error {
+	if err := ix.store.Put(ctx, records); err != nil {
+		return err
+	}
+	if d, ok := ix.labels.(labels.Deleter); ok {
+		for _, r := range records {
+			d.Delete(r.TxHash)
+		}
+	}
+	return nil
+}
+
+// records returns the records of the private transactions of block n.
+func (ix *Indexer) records(ctx context.Context, n uint64) ([]*Record, error) {
+	var records []*Record
+	err := ix.client.ForEachPrivateTransaction(ctx, n, n, func(btx *client.BlockPrivateTransaction) error {
+		r := NewRecord(ix.client.Privacy, btx)
+		if ix.labels != nil {
+			r.Labels, _ = ix.labels.Get(r.TxHash)
+		}
+		records = append(records, r)
+		return nil
+	})
+	return records, err
+}
+
+// Sync indexes all blocks after the checkpoint up to the latest block.
+func (ix *Indexer) Sync(ctx context.Context) error {
+	from, err := ix.next(ctx)
+	if err != nil {
+		return err
+	}
+	header, err := ix.client.Eth().HeaderByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+	latest := header.Number.Uint64()
+	if from > latest {
+		return nil
+	}
+	return ix.Index(ctx, from, latest)
+}
+
+func (ix *Indexer) next(ctx context.Context) (uint64, error) {
+	checkpoint, ok, err := ix.store.Checkpoint(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, nil
+	}
+	return checkpoint + 1, nil
+}
+
+// NewRecord builds the record of a resolved private transaction.
+func NewRecord(p *privacy.Privacy, btx *client.BlockPrivateTransaction) *Record {
+	r := &Record{
+		TxHash:          btx.Marker.Hash(),
+		BlockHash:       btx.BlockHash,
+		BlockNumber:     btx.BlockNumber,
+		BlockTime:       btx.BlockTime,
+		Index:           btx.Index,
+		To:              btx.Transaction.To(),
+		ContractAddress: btx.Receipt.ContractAddress,
+		Status:          btx.Receipt.Status,
+		Transaction:     btx.Transaction,
+		Receipt:         btx.Receipt,
+	}
+	if from, err := btx.Transaction.Sender(); err == nil {
+		r.From = from
+	}
+	r.PrivacyGroupID = GroupID(p, btx)
+	return r
+}
+
+// GroupID returns the privacy group ID of a private transaction, derived from
+// its participants for transactions sent with privateFor.
+func GroupID(p *privacy.Privacy, btx *client.BlockPrivateTransaction) string {
+	if btx.Receipt.PrivacyGroupID != "" {
+		return btx.Receipt.PrivacyGroupID
+	}
+	if id := btx.Transaction.PrivacyGroupID(); id != nil {
+		return base64.StdEncoding.EncodeToString(id)
+	}
+	privateFrom := privacy.PublicKey(btx.Transaction.PrivateFrom())
+	participants := []*privacy.PublicKey{&privateFrom}
+	for _, v := range btx.Transaction.PrivateFor() {
+		key := privacy.PublicKey(v)
+		participants = append(participants, &key)
+	}
+	return p.FindRootPrivacyGroup(participants).ID
+}
@@ -0,0 +1,105 @@
+package indexer
+
+import (
+	"context"
+
+	"github.com/bsostech/go-besu/enrich"
+)
+
+// DefaultQueryPageSize is the number of records queried at once by Records.
+const DefaultQueryPageSize = 500
+
+// RecordSeq is a lazy sequence of records, with the shape of
+// iter.Seq2[*Record, error], see client.LogSeq.
+type RecordSeq func(yield func(*Record, error) bool)
+
+// EventSeq is a lazy sequence of decoded events, see client.LogSeq. The
+// error is yielded with a nil event.
+type EventSeq func(yield func(*enrich.Event, error) bool)
+
+// Records returns the records of store matching filter, queried in pages of
+// pageSize records, DefaultQueryPageSize if 0, as they are consumed. The
+// sequence ends with ctx.Err() once ctx is done.
+func Records(ctx context.Context, store Store, filter Filter, pageSize int) RecordSeq {
+	if pageSize <= 0 {
+		pageSize = DefaultQueryPageSize
+	}
+	return func(yield func(*Record, error) bool) {
+		total, size := filter.Limit, pageSize
+		page := filter
+		var (
+			last    *Record
+			yielded int
+		)
+		for {
+			page.Limit = size
+			records, err := store.Query(ctx, &page)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			fresh := 0
+			for _, r := range records {
+				// pages restart at the block of the last record, skip those already yielded
+				if last != nil && (r.BlockNumber < last.BlockNumber ||
+					r.BlockNumber == last.BlockNumber && r.Index <= last.Index) {
+					continue
+				}
+				if err := ctx.Err(); err != nil {
+					yield(nil, err)
+					return
+				}
+				if !yield(r, nil) {
+					return
+				}
+				last = r
+				fresh++
+				yielded++
+				if total > 0 && yielded == total {
+					return
+				}
+			}
+			if len(records) < page.Limit {
+				return
+			}
+			if fresh == 0 {
+				// a single block holds more than a page
+				size *= 2
+				continue
+			}
+			page.FromBlock = last.BlockNumber
+		}
+	}
+}
+
+// Records returns the records of the store of ix matching filter like the
+// package function, decoded with the decoders of ix.
+func (ix *Indexer) Records(ctx context.Context, filter Filter) RecordSeq {
+	return func(yield func(*Record, error) bool) {
+		Records(ctx, ix.store, filter, 0)(func(r *Record, err error) bool {
+			if err == nil {
+				ix.Decode([]*Record{r})
+			}
+			return yield(r, err)
+		})
+	}
+}
+
+// Events returns the decoded events of the records of the store of ix
+// matching filter, in log order.
+func (ix *Indexer) Events(ctx context.Context, filter Filter) EventSeq {
+	return func(yield func(*enrich.Event, error) bool) {
+		ix.Records(ctx, filter)(func(r *Record, err error) bool {
+			if err != nil {
+				yield(nil, err)
+				return false
+			}
+			for _, e := range r.Events {
+				if !yield(e, nil) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+}
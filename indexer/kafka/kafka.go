@@ -0,0 +1,133 @@
+// Package kafka publishes indexed private transactions to Kafka: a message
+// per receipt and a message per decoded event, keyed by privacy group or
+// contract so that the messages of a key keep their order in one partition.
+// The caller supplies the Kafka client as a Producer, e.g. a
+// github.com/segmentio/kafka-go Writer or a sarama SyncProducer, so this
+// package does not depend on one.
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bsostech/go-besu/indexer"
+)
+
+// Message is a Kafka message.
+type Message struct {
+	Topic   string
+	Key     []byte
+	Value   []byte
+	Headers map[string]string
+}
+
+// Producer writes messages to Kafka, returning once they are acknowledged.
+type Producer interface {
+	Produce(ctx context.Context, msgs []Message) error
+}
+
+// ProducerFunc adapts a function to Producer.
+type ProducerFunc func(ctx context.Context, msgs []Message) error
+
+// Produce implements Producer.
+func (f ProducerFunc) Produce(ctx context.Context, msgs []Message) error {
+	return f(ctx, msgs)
+}
+
+// Partitioning selects the message key, which Kafka partitions by.
+type Partitioning int
+
+const (
+	// ByGroup keys messages by privacy group ID.
+	ByGroup Partitioning = iota
+	// ByContract keys messages by contract address, the emitting contract for
+	// events.
+	ByContract
+	// ByTransaction keys messages by privacy marker transaction hash,
+	// spreading the load without ordering guarantees across transactions.
+	ByTransaction
+)
+
+// Message headers.
+const (
+	HeaderType  = "besu-type" // "receipt" or "event"
+	HeaderGroup = "besu-privacy-group"
+)
+
+// Config configures a Sink.
+type Config struct {
+	ReceiptTopic string // receipts are not published if empty
+	EventTopic   string // events are not published if empty
+	Partitioning Partitioning
+}
+
+// Sink is an indexer.Sink publishing to Kafka.
+type Sink struct {
+	producer Producer
+	cfg      Config
+}
+
+// NewSink .
+func NewSink(p Producer, cfg Config) (*Sink, error) {
+	if cfg.ReceiptTopic == "" && cfg.EventTopic == "" {
+		return nil, fmt.Errorf("no topic configured")
+	}
+	return &Sink{
+		producer: p,
+		cfg:      cfg,
+	}, nil
+}
+
+// Publish implements indexer.Sink, producing the messages of records in one call.
+func (s *Sink) Publish(ctx context.Context, records []*indexer.Record) error {
+	var msgs []Message
+	for _, r := range records {
+		if s.cfg.ReceiptTopic != "" {
+			value, err := json.Marshal(indexer.NewReceiptMessage(r))
+			if err != nil {
+				return err
+			}
+			msgs = append(msgs, s.message(s.cfg.ReceiptTopic, "receipt", s.key(r, r.Contract().Hex()), r, value))
+		}
+		if s.cfg.EventTopic != "" {
+			for _, e := range indexer.NewEventMessages(r) {
+				value, err := json.Marshal(e)
+				if err != nil {
+					return err
+				}
+				msgs = append(msgs, s.message(s.cfg.EventTopic, "event", s.key(r, e.Event.Address.Hex()), r, value))
+			}
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	if err := s.producer.Produce(ctx, msgs); err != nil {
+		return fmt.Errorf("failed to publish %v messages, err: %v", len(msgs), err)
+	}
+	return nil
+}
+
+func (s *Sink) message(topic, typ string, key []byte, r *indexer.Record, value []byte) Message {
+	return Message{
+		Topic: topic,
+		Key:   key,
+		Value: value,
+		Headers: map[string]string{
+			HeaderType:  typ,
+			HeaderGroup: r.PrivacyGroupID,
+		},
+	}
+}
+
+func (s *Sink) key(r *indexer.Record, contract string) []byte {
+	switch s.cfg.Partitioning {
+	case ByContract:
+		return []byte(contract)
+	case ByTransaction:
+		return []byte(r.TxHash.Hex())
+	default:
+		return []byte(r.PrivacyGroupID)
+	}
+}
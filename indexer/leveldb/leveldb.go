@@ -0,0 +1,178 @@
+package leveldb
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/bsostech/go-besu/indexer"
+)
+
+// Key layout, all numbers big endian:
+//
+//	r + txHash                         -> record
+//	b + blockNumber + txIndex + txHash -> nil, orders records by position
+//	l + txHash + logIndex              -> log
+//	c                                  -> checkpoint
+var (
+	recordPrefix   = []byte("r")
+	positionPrefix = []byte("b")
+	logPrefix      = []byte("l")
+	checkpointKey  = []byte("c")
+)
+
+// Store is an embedded indexer.Store on LevelDB, with the same semantics as
+// the PostgreSQL store: records are upserted by transaction hash and logs by
+// transaction hash and log index.
+type Store struct {
+	db *leveldb.DB
+}
+
+// Open opens or creates the database at path.
+func Open(path string) (*Store, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{
+		db: db,
+	}, nil
+}
+
+// Close .
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Put implements indexer.Store.
+func (s *Store) Put(ctx context.Context, records []*indexer.Record) error {
+	batch := new(leveldb.Batch)
+	for _, r := range records {
+		old, err := s.get(r.TxHash)
+		if err != nil && err != leveldb.ErrNotFound {
+			return err
+		}
+		if old != nil {
+			batch.Delete(positionKey(old.BlockNumber, old.Index, old.TxHash))
+		}
+		value, err := indexer.MarshalRecord(r)
+		if err != nil {
+			return err
+		}
+		batch.Put(append(common.CopyBytes(recordPrefix), r.TxHash.Bytes()...), value)
+		batch.Put(positionKey(r.BlockNumber, r.Index, r.TxHash), nil)
+		for _, log := range r.Receipt.Logs {
+			value, err := json.Marshal(log)
+			if err != nil {
+				return err
+			}
+			batch.Put(logKey(r.TxHash, log.Index), value)
+		}
+	}
+	return s.db.Write(batch, nil)
+}
+
+// Query implements indexer.Store.
+func (s *Store) Query(ctx context.Context, filter *indexer.Filter) ([]*indexer.Record, error) {
+	if filter.TxHash != nil {
+		r, err := s.get(*filter.TxHash)
+		if err == leveldb.ErrNotFound {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !filter.Match(r) {
+			return nil, nil
+		}
+		return []*indexer.Record{r}, nil
+	}
+	rng := util.BytesPrefix(positionPrefix)
+	rng.Start = positionKey(filter.FromBlock, 0, common.Hash{})
+	it := s.db.NewIterator(rng, nil)
+	defer it.Release()
+	var records []*indexer.Record
+	for it.Next() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		key := it.Key()
+		number := binary.BigEndian.Uint64(key[len(positionPrefix):])
+		if filter.ToBlock != 0 && number > filter.ToBlock {
+			break
+		}
+		r, err := s.get(common.BytesToHash(key[len(key)-common.HashLength:]))
+		if err != nil {
+			return nil, err
+		}
+		if !filter.Match(r) {
+			continue
+		}
+		records = append(records, r)
+		if filter.Limit > 0 && len(records) >= filter.Limit {
+			break
+		}
+	}
+	return records, it.Error()
+}
+
+// Logs returns the stored logs of a transaction ordered by log index.
+func (s *Store) Logs(ctx context.Context, txHash common.Hash) ([]*ethtypes.Log, error) {
+	it := s.db.NewIterator(util.BytesPrefix(append(common.CopyBytes(logPrefix), txHash.Bytes()...)), nil)
+	defer it.Release()
+	var logs []*ethtypes.Log
+	for it.Next() {
+		log := new(ethtypes.Log)
+		if err := json.Unmarshal(it.Value(), log); err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
+	}
+	return logs, it.Error()
+}
+
+// Checkpoint implements indexer.Store.
+func (s *Store) Checkpoint(ctx context.Context) (uint64, bool, error) {
+	value, err := s.db.Get(checkpointKey, nil)
+	if err == leveldb.ErrNotFound {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return binary.BigEndian.Uint64(value), true, nil
+}
+
+// SetCheckpoint implements indexer.Store.
+func (s *Store) SetCheckpoint(ctx context.Context, block uint64) error {
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, block)
+	return s.db.Put(checkpointKey, value, nil)
+}
+
+func (s *Store) get(txHash common.Hash) (*indexer.Record, error) {
+	value, err := s.db.Get(append(common.CopyBytes(recordPrefix), txHash.Bytes()...), nil)
+	if err != nil {
+		return nil, err
+	}
+	return indexer.UnmarshalRecord(value)
+}
+
+func positionKey(number uint64, index uint64, txHash common.Hash) []byte {
+	key := make([]byte, len(positionPrefix)+8+4+common.HashLength)
+	copy(key, positionPrefix)
+	binary.BigEndian.PutUint64(key[len(positionPrefix):], number)
+	binary.BigEndian.PutUint32(key[len(positionPrefix)+8:], uint32(index))
+	copy(key[len(positionPrefix)+12:], txHash.Bytes())
+	return key
+}
+
+func logKey(txHash common.Hash, index uint) []byte {
+	key := append(common.CopyBytes(logPrefix), txHash.Bytes()...)
+	return append(key, byte(index>>24), byte(index>>16), byte(index>>8), byte(index))
+}
@@ -0,0 +1,73 @@
+package indexer
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// MemoryStore is a Store keeping records in memory.
+type MemoryStore struct {
+	mu         sync.RWMutex
+	records    map[common.Hash]*Record
+	checkpoint *uint64
+}
+
+// NewMemoryStore .
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records: make(map[common.Hash]*Record),
+	}
+}
+
+// Put implements Store.
+func (s *MemoryStore) Put(ctx context.Context, records []*Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range records {
+		s.records[r.TxHash] = r
+	}
+	return nil
+}
+
+// Query implements Store.
+func (s *MemoryStore) Query(ctx context.Context, filter *Filter) ([]*Record, error) {
+	s.mu.RLock()
+	var result []*Record
+	for _, r := range s.records {
+		if filter.Match(r) {
+			result = append(result, r)
+		}
+	}
+	s.mu.RUnlock()
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].BlockNumber != result[j].BlockNumber {
+			return result[i].BlockNumber < result[j].BlockNumber
+		}
+		return result[i].Index < result[j].Index
+	})
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result, nil
+}
+
+// Checkpoint implements Store.
+func (s *MemoryStore) Checkpoint(ctx context.Context) (uint64, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.checkpoint == nil {
+		return 0, false, nil
+	}
+	return *s.checkpoint, true, nil
+}
+
+// SetCheckpoint implements Store.
+func (s *MemoryStore) SetCheckpoint(ctx context.Context, block uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoint = &block
+	return nil
+}
@@ -0,0 +1,106 @@
+// Package nats publishes indexed private transactions to NATS subjects, for
+// deployments without a Kafka cluster. The caller supplies the connection:
+// *nats.Conn of github.com/nats-io/nats.go implements Publisher, as does a
+// JetStream context wrapped with PublisherFunc for persistent delivery.
+package nats
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bsostech/go-besu/indexer"
+)
+
+// Publisher publishes data to a subject.
+type Publisher interface {
+	Publish(subject string, data []byte) error
+}
+
+// PublisherFunc adapts a function to Publisher.
+type PublisherFunc func(subject string, data []byte) error
+
+// Publish implements Publisher.
+func (f PublisherFunc) Publish(subject string, data []byte) error {
+	return f(subject, data)
+}
+
+// Config configures a Sink.
+type Config struct {
+	ReceiptSubject string // receipts are not published if empty, e.g. "besu.receipts"
+	EventSubject   string // events are not published if empty, e.g. "besu.events"
+	// PerGroup appends the privacy group ID, base64url encoded, as a last
+	// token of the subjects, so consumers can subscribe to single groups,
+	// e.g. "besu.events.<group>", or all with "besu.events.*".
+	PerGroup bool
+}
+
+// Sink is an indexer.Sink publishing to NATS.
+type Sink struct {
+	publisher Publisher
+	cfg       Config
+}
+
+// NewSink .
+func NewSink(p Publisher, cfg Config) (*Sink, error) {
+	if cfg.ReceiptSubject == "" && cfg.EventSubject == "" {
+		return nil, fmt.Errorf("no subject configured")
+	}
+	return &Sink{
+		publisher: p,
+		cfg:       cfg,
+	}, nil
+}
+
+// Publish implements indexer.Sink. Core NATS delivers at most once; records
+// are not redelivered to subscribers which were disconnected.
+func (s *Sink) Publish(ctx context.Context, records []*indexer.Record) error {
+	for _, r := range records {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if s.cfg.ReceiptSubject != "" {
+			if err := s.publish(s.subject(s.cfg.ReceiptSubject, r), indexer.NewReceiptMessage(r)); err != nil {
+				return err
+			}
+		}
+		if s.cfg.EventSubject != "" {
+			for _, e := range indexer.NewEventMessages(r) {
+				if err := s.publish(s.subject(s.cfg.EventSubject, r), e); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Sink) publish(subject string, msg interface{}) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if err := s.publisher.Publish(subject, data); err != nil {
+		return fmt.Errorf("failed to publish to %v, err: %v", subject, err)
+	}
+	return nil
+}
+
+// subject returns the subject of r under base.
+func (s *Sink) subject(base string, r *indexer.Record) string {
+	if !s.cfg.PerGroup {
+		return base
+	}
+	return base + "." + groupToken(r.PrivacyGroupID)
+}
+
+// groupToken encodes a privacy group ID as a subject token, which may not
+// contain ".", "*", ">" or whitespace.
+func groupToken(groupID string) string {
+	id, err := base64.StdEncoding.DecodeString(groupID)
+	if err != nil || groupID == "" {
+		return "_"
+	}
+	return base64.RawURLEncoding.EncodeToString(id)
+}
@@ -0,0 +1,259 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/bsostech/go-besu/indexer"
+	"github.com/bsostech/go-besu/types"
+)
+
+// Store is an indexer.Store on PostgreSQL. The caller opens db with the driver
+// of its choice, e.g. github.com/lib/pq or github.com/jackc/pgx/v4/stdlib.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore migrates the schema of db to the latest version.
+func NewStore(ctx context.Context, db *sql.DB) (*Store, error) {
+	s := &Store{
+		db: db,
+	}
+	if err := s.Migrate(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Migrate applies the migrations newer than the schema version of the database.
+func (s *Store) Migrate(ctx context.Context) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS indexer_schema (version INTEGER NOT NULL)`); err != nil {
+		return err
+	}
+	// serialize concurrent migrations
+	if _, err := tx.ExecContext(ctx, `LOCK TABLE indexer_schema IN EXCLUSIVE MODE`); err != nil {
+		return err
+	}
+	version := 0
+	err = tx.QueryRowContext(ctx, `SELECT version FROM indexer_schema`).Scan(&version)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if version > schemaVersion {
+		return fmt.Errorf("schema version %v is newer than supported version %v", version, schemaVersion)
+	}
+	for _, migration := range migrations[version:] {
+		for _, stmt := range migration {
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM indexer_schema`); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO indexer_schema (version) VALUES ($1)`, schemaVersion); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Put implements indexer.Store. Records are upserted by transaction hash and
+// logs by transaction hash and log index.
+func (s *Store) Put(ctx context.Context, records []*indexer.Record) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	txStmt, err := tx.PrepareContext(ctx, `INSERT INTO private_transactions
+		(tx_hash, block_hash, block_number, block_time, tx_index, privacy_group_id, sender, recipient, contract_address, status, transaction, receipt, labels)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (tx_hash) DO UPDATE SET
+			block_hash = EXCLUDED.block_hash, block_number = EXCLUDED.block_number, block_time = EXCLUDED.block_time,
+			tx_index = EXCLUDED.tx_index, privacy_group_id = EXCLUDED.privacy_group_id, sender = EXCLUDED.sender,
+			recipient = EXCLUDED.recipient, contract_address = EXCLUDED.contract_address, status = EXCLUDED.status,
+			transaction = EXCLUDED.transaction, receipt = EXCLUDED.receipt, labels = EXCLUDED.labels`)
+	if err != nil {
+		return err
+	}
+	defer txStmt.Close()
+	logStmt, err := tx.PrepareContext(ctx, `INSERT INTO private_logs
+		(tx_hash, log_index, block_number, address, topics, data)
+		VALUES ($1, $2, $3, $4, $5::BYTEA[], $6)
+		ON CONFLICT (tx_hash, log_index) DO UPDATE SET
+			block_number = EXCLUDED.block_number, address = EXCLUDED.address, topics = EXCLUDED.topics, data = EXCLUDED.data`)
+	if err != nil {
+		return err
+	}
+	defer logStmt.Close()
+	for _, r := range records {
+		rawTx, err := rlp.EncodeToBytes(r.Transaction)
+		if err != nil {
+			return err
+		}
+		receipt, err := json.Marshal(r.Receipt)
+		if err != nil {
+			return err
+		}
+		var recordLabels interface{}
+		if len(r.Labels) > 0 {
+			l, err := json.Marshal(r.Labels)
+			if err != nil {
+				return err
+			}
+			recordLabels = string(l)
+		}
+		var recipient []byte
+		if r.To != nil {
+			recipient = r.To.Bytes()
+		}
+		_, err = txStmt.ExecContext(ctx, r.TxHash.Bytes(), r.BlockHash.Bytes(), r.BlockNumber, r.BlockTime, r.Index,
+			r.PrivacyGroupID, r.From.Bytes(), recipient, r.ContractAddress.Bytes(), r.Status, rawTx, string(receipt), recordLabels)
+		if err != nil {
+			return err
+		}
+		for _, log := range r.Receipt.Logs {
+			_, err = logStmt.ExecContext(ctx, r.TxHash.Bytes(), log.Index, r.BlockNumber, log.Address.Bytes(), topicsArray(log.Topics), log.Data)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// Query implements indexer.Store.
+func (s *Store) Query(ctx context.Context, filter *indexer.Filter) ([]*indexer.Record, error) {
+	var where []string
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	if filter.TxHash != nil {
+		where = append(where, "tx_hash = "+arg(filter.TxHash.Bytes()))
+	}
+	if filter.PrivacyGroupID != "" {
+		where = append(where, "privacy_group_id = "+arg(filter.PrivacyGroupID))
+	}
+	if filter.Contract != nil {
+		a := arg(filter.Contract.Bytes())
+		where = append(where, fmt.Sprintf("(recipient = %v OR contract_address = %v)", a, a))
+	}
+	if filter.From != nil {
+		where = append(where, "sender = "+arg(filter.From.Bytes()))
+	}
+	if filter.FromBlock > 0 {
+		where = append(where, "block_number >= "+arg(filter.FromBlock))
+	}
+	if filter.ToBlock > 0 {
+		where = append(where, "block_number <= "+arg(filter.ToBlock))
+	}
+	if filter.FromTime > 0 {
+		where = append(where, "block_time >= "+arg(filter.FromTime))
+	}
+	if filter.ToTime > 0 {
+		where = append(where, "block_time <= "+arg(filter.ToTime))
+	}
+	query := `SELECT tx_hash, block_hash, block_number, block_time, tx_index, privacy_group_id, sender, recipient,
+		contract_address, status, transaction, receipt, labels FROM private_transactions`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY block_number, tx_index"
+	if filter.Limit > 0 {
+		query += " LIMIT " + arg(filter.Limit)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var records []*indexer.Record
+	for rows.Next() {
+		r, err := scanRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
+
+// Checkpoint implements indexer.Store.
+func (s *Store) Checkpoint(ctx context.Context) (uint64, bool, error) {
+	var block uint64
+	err := s.db.QueryRowContext(ctx, `SELECT block_number FROM indexer_checkpoint WHERE id = 1`).Scan(&block)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return block, true, nil
+}
+
+// SetCheckpoint implements indexer.Store.
+func (s *Store) SetCheckpoint(ctx context.Context, block uint64) error {
+	_, err := s.db.ExecContext(ctx, `INSERT INTO indexer_checkpoint (id, block_number) VALUES (1, $1)
+		ON CONFLICT (id) DO UPDATE SET block_number = EXCLUDED.block_number`, block)
+	return err
+}
+
+func scanRecord(rows *sql.Rows) (*indexer.Record, error) {
+	var (
+		r                                        indexer.Record
+		txHash, blockHash, sender, contract, raw []byte
+		recipient                                []byte
+		receipt                                  string
+		recordLabels                             sql.NullString
+	)
+	err := rows.Scan(&txHash, &blockHash, &r.BlockNumber, &r.BlockTime, &r.Index, &r.PrivacyGroupID, &sender,
+		&recipient, &contract, &r.Status, &raw, &receipt, &recordLabels)
+	if err != nil {
+		return nil, err
+	}
+	r.TxHash = common.BytesToHash(txHash)
+	r.BlockHash = common.BytesToHash(blockHash)
+	r.From = common.BytesToAddress(sender)
+	if recipient != nil {
+		to := common.BytesToAddress(recipient)
+		r.To = &to
+	}
+	r.ContractAddress = common.BytesToAddress(contract)
+	r.Transaction = new(types.PrivateTransaction)
+	if err := rlp.DecodeBytes(raw, r.Transaction); err != nil {
+		return nil, err
+	}
+	r.Receipt = new(types.PrivateReceipt)
+	if err := json.Unmarshal([]byte(receipt), r.Receipt); err != nil {
+		return nil, err
+	}
+	if recordLabels.Valid {
+		if err := json.Unmarshal([]byte(recordLabels.String), &r.Labels); err != nil {
+			return nil, err
+		}
+	}
+	return &r, nil
+}
+
+// topicsArray formats topics as a PostgreSQL bytea array literal.
+func topicsArray(topics []common.Hash) string {
+	elems := make([]string, len(topics))
+	for i := range topics {
+		elems[i] = fmt.Sprintf(`"\\x%x"`, topics[i].Bytes())
+	}
+	return "{" + strings.Join(elems, ",") + "}"
+}
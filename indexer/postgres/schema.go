@@ -0,0 +1,46 @@
+package postgres
+
+// schemaVersion is bumped whenever migrations is appended to.
+const schemaVersion = 2
+
+// migrations[i] migrates the schema from version i to i+1.
+var migrations = [][]string{
+	{
+		`CREATE TABLE IF NOT EXISTS private_transactions (
+			tx_hash          BYTEA PRIMARY KEY,
+			block_hash       BYTEA NOT NULL,
+			block_number     BIGINT NOT NULL,
+			block_time       BIGINT NOT NULL,
+			tx_index         INTEGER NOT NULL,
+			privacy_group_id TEXT NOT NULL,
+			sender           BYTEA NOT NULL,
+			recipient        BYTEA,
+			contract_address BYTEA NOT NULL,
+			status           BIGINT NOT NULL,
+			transaction      BYTEA NOT NULL,
+			receipt          JSONB NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS private_transactions_block_idx ON private_transactions (block_number, tx_index)`,
+		`CREATE INDEX IF NOT EXISTS private_transactions_group_idx ON private_transactions (privacy_group_id)`,
+		`CREATE INDEX IF NOT EXISTS private_transactions_sender_idx ON private_transactions (sender)`,
+		`CREATE INDEX IF NOT EXISTS private_transactions_recipient_idx ON private_transactions (recipient)`,
+		`CREATE INDEX IF NOT EXISTS private_transactions_contract_idx ON private_transactions (contract_address)`,
+		`CREATE TABLE IF NOT EXISTS private_logs (
+			tx_hash      BYTEA NOT NULL,
+			log_index    INTEGER NOT NULL,
+			block_number BIGINT NOT NULL,
+			address      BYTEA NOT NULL,
+			topics       BYTEA[] NOT NULL,
+			data         BYTEA NOT NULL,
+			PRIMARY KEY (tx_hash, log_index)
+		)`,
+		`CREATE INDEX IF NOT EXISTS private_logs_address_idx ON private_logs (address, block_number)`,
+		`CREATE TABLE IF NOT EXISTS indexer_checkpoint (
+			id           INTEGER PRIMARY KEY,
+			block_number BIGINT NOT NULL
+		)`,
+	},
+	{
+		`ALTER TABLE private_transactions ADD COLUMN IF NOT EXISTS labels JSONB`,
+	},
+}
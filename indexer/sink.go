@@ -0,0 +1,110 @@
+package indexer
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/bsostech/go-besu/enrich"
+)
+
+// Sink publishes indexed records, e.g. to an event bus. Records are decoded
+// with the decoders of the indexer before they are published.
+type Sink interface {
+	Publish(ctx context.Context, records []*Record) error
+}
+
+// Tee returns a Store putting records into store and then publishing them to
+// sinks, in order. As Put is retried after failures, sinks may receive
+// records more than once.
+func Tee(store Store, sinks ...Sink) Store {
+	return &tee{Store: store, sinks: sinks}
+}
+
+type tee struct {
+	Store
+	sinks []Sink
+}
+
+func (t *tee) Put(ctx context.Context, records []*Record) error {
+	if err := t.Store.Put(ctx, records); err != nil {
+		return err
+	}
+	for _, s := range t.sinks {
+		if err := s.Publish(ctx, records); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReceiptMessage is the published form of a record.
+type ReceiptMessage struct {
+	TxHash          common.Hash     `json:"transactionHash"`
+	BlockHash       common.Hash     `json:"blockHash"`
+	BlockNumber     uint64          `json:"blockNumber"`
+	BlockTime       uint64          `json:"blockTime"`
+	Index           uint64          `json:"transactionIndex"`
+	PrivacyGroupID  string          `json:"privacyGroupId"`
+	From            common.Address  `json:"from"`
+	To              *common.Address `json:"to"`
+	ContractAddress common.Address  `json:"contractAddress"`
+	Status          uint64          `json:"status"`
+	Call            *enrich.Call    `json:"call,omitempty"`
+	Events          int             `json:"events"`
+}
+
+// EventMessage is the published form of a decoded event of a record.
+type EventMessage struct {
+	TxHash         common.Hash   `json:"transactionHash"`
+	BlockNumber    uint64        `json:"blockNumber"`
+	BlockTime      uint64        `json:"blockTime"`
+	PrivacyGroupID string        `json:"privacyGroupId"`
+	LogIndex       uint          `json:"logIndex"`
+	Event          *enrich.Event `json:"event"`
+}
+
+// NewReceiptMessage .
+func NewReceiptMessage(r *Record) *ReceiptMessage {
+	return &ReceiptMessage{
+		TxHash:          r.TxHash,
+		BlockHash:       r.BlockHash,
+		BlockNumber:     r.BlockNumber,
+		BlockTime:       r.BlockTime,
+		Index:           r.Index,
+		PrivacyGroupID:  r.PrivacyGroupID,
+		From:            r.From,
+		To:              r.To,
+		ContractAddress: r.ContractAddress,
+		Status:          r.Status,
+		Call:            r.Call,
+		Events:          len(r.Events),
+	}
+}
+
+// NewEventMessages returns the messages of the decoded events of r.
+func NewEventMessages(r *Record) []*EventMessage {
+	msgs := make([]*EventMessage, 0, len(r.Events))
+	for _, e := range r.Events {
+		msg := &EventMessage{
+			TxHash:         r.TxHash,
+			BlockNumber:    r.BlockNumber,
+			BlockTime:      r.BlockTime,
+			PrivacyGroupID: r.PrivacyGroupID,
+			Event:          e,
+		}
+		if e.Log != nil {
+			msg.LogIndex = e.Log.Index
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+// Contract returns the contract of r: the recipient, or the created contract.
+func (r *Record) Contract() common.Address {
+	if r.To != nil {
+		return *r.To
+	}
+	return r.ContractAddress
+}
@@ -0,0 +1,150 @@
+package indexer
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// DefaultMemoryLimit is the size of the records a buffer holds in memory
+// before spilling them to disk.
+const DefaultMemoryLimit = 64 << 20
+
+// BufferOptions batches the records of several blocks into one Put, e.g. for
+// backfills to stores favouring large writes.
+type BufferOptions struct {
+	BatchBlocks uint64 // blocks per batch, the checkpoint advancing after each batch
+	MemoryLimit int64  // encoded size of the records held in memory, DefaultMemoryLimit if 0
+	Dir         string // directory of the spill files, os.TempDir() if empty
+}
+
+// SetBuffer makes Index put the records of opts.BatchBlocks blocks at once.
+// Records beyond the memory limit are spilled to a temporary file and put in
+// chunks of at most the memory limit. A BatchBlocks of 0 or 1 puts every block
+// separately, which is the default.
+func (ix *Indexer) SetBuffer(opts BufferOptions) {
+	if opts.MemoryLimit <= 0 {
+		opts.MemoryLimit = DefaultMemoryLimit
+	}
+	ix.buffer = &opts
+}
+
+// spillBuffer holds encoded records in memory up to a limit, and the
+// records beyond it in a temporary file, preserving their order.
+type spillBuffer struct {
+	limit int64
+	dir   string
+
+	mem     [][]byte
+	memSize int64
+	file    *os.File
+	w       *bufio.Writer
+}
+
+func newSpillBuffer(opts *BufferOptions) *spillBuffer {
+	return &spillBuffer{
+		limit: opts.MemoryLimit,
+		dir:   opts.Dir,
+	}
+}
+
+func (b *spillBuffer) add(records []*Record) error {
+	for _, r := range records {
+		data, err := MarshalRecord(r)
+		if err != nil {
+			return err
+		}
+		b.mem = append(b.mem, data)
+		b.memSize += int64(len(data))
+		if b.memSize > b.limit {
+			if err := b.spill(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// spill appends the records in memory to the spill file, one JSON record per line.
+func (b *spillBuffer) spill() error {
+	if b.file == nil {
+		f, err := ioutil.TempFile(b.dir, "besu-indexer-*")
+		if err != nil {
+			return err
+		}
+		b.file, b.w = f, bufio.NewWriter(f)
+	}
+	for _, data := range b.mem {
+		if _, err := b.w.Write(data); err != nil {
+			return err
+		}
+		if err := b.w.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	b.mem, b.memSize = nil, 0
+	return nil
+}
+
+// drain passes the buffered records to put in order, in chunks of at most
+// the memory limit, and empties the buffer.
+func (b *spillBuffer) drain(put func([]*Record) error) error {
+	defer b.close()
+	var chunk []*Record
+	var size int64
+	add := func(data []byte) error {
+		r, err := UnmarshalRecord(data)
+		if err != nil {
+			return err
+		}
+		chunk = append(chunk, r)
+		size += int64(len(data))
+		if size > b.limit {
+			err = put(chunk)
+			chunk, size = nil, 0
+		}
+		return err
+	}
+	if b.file != nil {
+		if err := b.w.Flush(); err != nil {
+			return err
+		}
+		if _, err := b.file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		reader := bufio.NewReader(b.file)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(line) > 1 {
+				if err := add(line[:len(line)-1]); err != nil {
+					return err
+				}
+			}
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for _, data := range b.mem {
+		if err := add(data); err != nil {
+			return err
+		}
+	}
+	if len(chunk) > 0 {
+		return put(chunk)
+	}
+	return nil
+}
+
+// close drops the buffered records and removes the spill file.
+func (b *spillBuffer) close() {
+	if b.file != nil {
+		b.file.Close()
+		os.Remove(b.file.Name())
+	}
+	b.mem, b.memSize, b.file, b.w = nil, 0, nil, nil
+}
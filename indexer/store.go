@@ -0,0 +1,79 @@
+package indexer
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/bsostech/go-besu/enrich"
+	"github.com/bsostech/go-besu/labels"
+	"github.com/bsostech/go-besu/types"
+)
+
+// Record is an indexed private transaction.
+type Record struct {
+	TxHash          common.Hash // hash of the privacy marker transaction
+	BlockHash       common.Hash
+	BlockNumber     uint64
+	BlockTime       uint64
+	Index           uint64
+	PrivacyGroupID  string
+	From            common.Address
+	To              *common.Address // nil means contract creation
+	ContractAddress common.Address
+	Status          uint64
+	Transaction     *types.PrivateTransaction
+	Receipt         *types.PrivateReceipt
+	Labels          labels.Labels // recorded by the sender, not on-chain
+
+	// Decoded with the decoders of the indexer, not persisted by stores.
+	Call   *enrich.Call
+	Events []*enrich.Event
+}
+
+// Filter selects records, zero fields match all records.
+type Filter struct {
+	TxHash         *common.Hash
+	PrivacyGroupID string
+	Contract       *common.Address // the recipient or the created contract
+	From           *common.Address
+	FromBlock      uint64
+	ToBlock        uint64 // 0 means no upper bound
+	FromTime       uint64
+	ToTime         uint64 // 0 means no upper bound
+	Limit          int    // 0 means no limit
+}
+
+// Store persists indexed records. Put must be idempotent, as blocks may be
+// indexed again after a restart.
+type Store interface {
+	Put(ctx context.Context, records []*Record) error
+	// Query returns matching records ordered by block number and index.
+	Query(ctx context.Context, filter *Filter) ([]*Record, error)
+	// Checkpoint returns the last indexed block, ok is false if none.
+	Checkpoint(ctx context.Context) (block uint64, ok bool, err error)
+	SetCheckpoint(ctx context.Context, block uint64) error
+}
+
+// Match reports whether r matches f.
+func (f *Filter) Match(r *Record) bool {
+	if f.TxHash != nil && *f.TxHash != r.TxHash {
+		return false
+	}
+	if f.PrivacyGroupID != "" && f.PrivacyGroupID != r.PrivacyGroupID {
+		return false
+	}
+	if f.Contract != nil && (r.To == nil || *r.To != *f.Contract) && r.ContractAddress != *f.Contract {
+		return false
+	}
+	if f.From != nil && *f.From != r.From {
+		return false
+	}
+	if r.BlockNumber < f.FromBlock || (f.ToBlock != 0 && r.BlockNumber > f.ToBlock) {
+		return false
+	}
+	if r.BlockTime < f.FromTime || (f.ToTime != 0 && r.BlockTime > f.ToTime) {
+		return false
+	}
+	return true
+}
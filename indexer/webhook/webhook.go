@@ -0,0 +1,307 @@
+// Package webhook delivers indexed private transactions to an HTTP endpoint
+// as signed JSON POST requests, retrying failed deliveries and handing those
+// which keep failing to a dead letter queue.
+//
+// Each request carries the headers
+//
+//	X-Besu-Delivery:  unique ID of the delivery, the same across retries
+//	X-Besu-Type:      "receipt" or "event"
+//	X-Besu-Timestamp: Unix time of the attempt in seconds
+//	X-Besu-Signature: "sha256=" + hex HMAC-SHA256 of timestamp + "." + body
+//
+// Receivers verify the signature with the shared secret, see Verify, and
+// reject old timestamps to prevent replays.
+package webhook
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bsostech/go-besu/clock"
+	"github.com/bsostech/go-besu/indexer"
+	"github.com/bsostech/go-besu/retry"
+)
+
+// Headers of deliveries.
+const (
+	HeaderDelivery  = "X-Besu-Delivery"
+	HeaderType      = "X-Besu-Type"
+	HeaderTimestamp = "X-Besu-Timestamp"
+	HeaderSignature = "X-Besu-Signature"
+)
+
+// Delivery is a message to deliver.
+type Delivery struct {
+	ID   string          `json:"id"`
+	Type string          `json:"type"` // "receipt" or "event"
+	Body json.RawMessage `json:"body"`
+}
+
+// StatusError is returned for non 2xx responses.
+type StatusError struct {
+	Code       int
+	Status     string
+	RetryAfter time.Duration // Retry-After of 429 and 503 responses, 0 if none
+}
+
+func (e *StatusError) Error() string {
+	return e.Status
+}
+
+// DeadLetterQueue keeps deliveries which failed after all retries.
+type DeadLetterQueue interface {
+	Add(ctx context.Context, d *Delivery, err error) error
+}
+
+// Config configures a Sink.
+type Config struct {
+	URL    string
+	Secret []byte // HMAC key shared with the receiver
+	// Events selects the deliveries: receipts, and decoded events if set.
+	Events bool
+	// Retry is the policy of attempts per delivery, retry.DefaultPolicy if
+	// MaxAttempts is 0. Transport failures, 408, 429 and 5xx responses are
+	// retried, waiting at least as long as a Retry-After header asks.
+	Retry      retry.Policy
+	HTTPClient *http.Client // http.DefaultClient if nil, retries are up to Retry
+	// DeadLetters keeps failed deliveries. If nil, Publish fails instead.
+	DeadLetters DeadLetterQueue
+}
+
+// Sink is an indexer.Sink posting to a webhook.
+type Sink struct {
+	cfg Config
+}
+
+// NewSink .
+func NewSink(cfg Config) (*Sink, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("url not set")
+	}
+	if len(cfg.Secret) == 0 {
+		return nil, fmt.Errorf("secret not set")
+	}
+	if cfg.Retry.MaxAttempts == 0 {
+		cfg.Retry = retry.DefaultPolicy
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = http.DefaultClient
+	}
+	return &Sink{
+		cfg: cfg,
+	}, nil
+}
+
+// Publish implements indexer.Sink, delivering the receipts, and events if
+// configured, of records in order. Deliveries failing after all retries go to
+// the dead letter queue; Publish fails if there is none, or if ctx is done.
+func (s *Sink) Publish(ctx context.Context, records []*indexer.Record) error {
+	for _, r := range records {
+		d, err := newDelivery(r.TxHash.Hex(), "receipt", indexer.NewReceiptMessage(r))
+		if err != nil {
+			return err
+		}
+		deliveries := []*Delivery{d}
+		if s.cfg.Events {
+			for _, e := range indexer.NewEventMessages(r) {
+				d, err := newDelivery(r.TxHash.Hex()+"-"+strconv.FormatUint(uint64(e.LogIndex), 10), "event", e)
+				if err != nil {
+					return err
+				}
+				deliveries = append(deliveries, d)
+			}
+		}
+		for _, d := range deliveries {
+			if err := s.Deliver(ctx, d); err != nil {
+				if ctx.Err() != nil || s.cfg.DeadLetters == nil {
+					return err
+				}
+				if err := s.cfg.DeadLetters.Add(ctx, d, err); err != nil {
+					return fmt.Errorf("failed to dead letter delivery %v, err: %v", d.ID, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func newDelivery(id, typ string, msg interface{}) (*Delivery, error) {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	return &Delivery{ID: id, Type: typ, Body: body}, nil
+}
+
+// Deliver posts d with retries, e.g. to redeliver dead letters.
+func (s *Sink) Deliver(ctx context.Context, d *Delivery) error {
+	p := s.cfg.Retry
+	clk := clock.Or(p.Clock)
+	var err error
+	for attempt := 0; attempt < p.MaxAttempts || attempt == 0; attempt++ {
+		if attempt > 0 {
+			delay := p.Delay(attempt)
+			if retryAfter := retryAfter(err); retryAfter > delay {
+				delay = retryAfter
+			}
+			timer := clk.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			case <-timer.C():
+			}
+		}
+		if err = s.post(ctx, d, clk.Now()); err == nil || !retryable(err) {
+			return err
+		}
+	}
+	return err
+}
+
+func (s *Sink) post(ctx context.Context, d *Delivery, now time.Time) error {
+	req, err := http.NewRequest(http.MethodPost, s.cfg.URL, bytes.NewReader(d.Body))
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderDelivery, d.ID)
+	req.Header.Set(HeaderType, d.Type)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(s.cfg.Secret, timestamp, d.Body))
+	resp, err := s.cfg.HTTPClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode/100 != 2 {
+		statusErr := &StatusError{Code: resp.StatusCode, Status: resp.Status}
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+			statusErr.RetryAfter = retry.ParseRetryAfter(resp.Header.Get("Retry-After"), now)
+		}
+		return statusErr
+	}
+	return nil
+}
+
+func retryable(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Code >= 500 || statusErr.Code == http.StatusRequestTimeout || statusErr.Code == http.StatusTooManyRequests
+	}
+	return retry.Retryable(err)
+}
+
+// retryAfter returns the delay the receiver asked for before retrying err.
+func retryAfter(err error) time.Duration {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.RetryAfter
+	}
+	d, _ := retry.RateLimited(err)
+	return d
+}
+
+// Sign returns the signature header of body sent at timestamp.
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of a received delivery and that its timestamp
+// is at most maxAge old, returning the body.
+func Verify(r *http.Request, secret []byte, maxAge time.Duration, now time.Time) ([]byte, error) {
+	timestamp := r.Header.Get(HeaderTimestamp)
+	sent, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp %q", timestamp)
+	}
+	if age := now.Sub(time.Unix(sent, 0)); age > maxAge || age < -maxAge {
+		return nil, fmt.Errorf("timestamp %v out of range", timestamp)
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal([]byte(r.Header.Get(HeaderSignature)), []byte(Sign(secret, timestamp, body))) {
+		return nil, fmt.Errorf("invalid signature")
+	}
+	return body, nil
+}
+
+// DeadLetter is a failed delivery as kept by FileDeadLetterQueue.
+type DeadLetter struct {
+	Delivery *Delivery `json:"delivery"`
+	Error    string    `json:"error"`
+	Time     time.Time `json:"time"`
+}
+
+// FileDeadLetterQueue appends dead letters to a file as JSON lines.
+type FileDeadLetterQueue struct {
+	Path  string
+	Clock clock.Clock // clock.Real if nil
+
+	mu sync.Mutex
+}
+
+// Add implements DeadLetterQueue.
+func (q *FileDeadLetterQueue) Add(ctx context.Context, d *Delivery, err error) error {
+	line, merr := json.Marshal(&DeadLetter{Delivery: d, Error: err.Error(), Time: clock.Or(q.Clock).Now()})
+	if merr != nil {
+		return merr
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	f, ferr := os.OpenFile(q.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if ferr != nil {
+		return ferr
+	}
+	if _, werr := f.Write(append(line, '\n')); werr != nil {
+		f.Close()
+		return werr
+	}
+	return f.Close()
+}
+
+// ReadDeadLetters returns the dead letters of the file at path, to be
+// redelivered with Sink.Deliver.
+func ReadDeadLetters(path string) ([]*DeadLetter, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var letters []*DeadLetter
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 16<<20)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		letter := new(DeadLetter)
+		if err := json.Unmarshal(scanner.Bytes(), letter); err != nil {
+			return nil, fmt.Errorf("failed to decode dead letter %v, err: %v", len(letters), err)
+		}
+		letters = append(letters, letter)
+	}
+	return letters, scanner.Err()
+}
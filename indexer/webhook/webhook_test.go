@@ -0,0 +1,104 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bsostech/go-besu/clock"
+	"github.com/bsostech/go-besu/retry"
+)
+
+// statusServer answers the first responses with their status and Retry-After
+// header, and the following requests with 200.
+func statusServer(t *testing.T, responses ...[2]string) (*httptest.Server, *int32) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&calls, 1))
+		if n > len(responses) {
+			return
+		}
+		if retryAfter := responses[n-1][1]; retryAfter != "" {
+			w.Header().Set("Retry-After", retryAfter)
+		}
+		switch responses[n-1][0] {
+		case "429":
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "503":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "400":
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func newTestSink(t *testing.T, url string, clk clock.Clock) *Sink {
+	s, err := NewSink(Config{
+		URL:    url,
+		Secret: []byte("secret"),
+		Retry:  retry.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Clock: clk},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+var testDelivery = &Delivery{ID: "0x01", Type: "receipt", Body: []byte(`{}`)}
+
+func TestDeliverRetryAfter(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	for _, retryAfter := range []string{"30", now.Add(30 * time.Second).UTC().Format(http.TimeFormat)} {
+		srv, calls := statusServer(t, [2]string{"429", retryAfter})
+		clk := clock.NewFake(now)
+		s := newTestSink(t, srv.URL, clk)
+		done := make(chan error, 1)
+		go func() { done <- s.Deliver(context.Background(), testDelivery) }()
+		clk.BlockUntil(1)
+		clk.Advance(29 * time.Second)
+		select {
+		case err := <-done:
+			t.Fatalf("Retry-After %q: delivered before the delay, %v", retryAfter, err)
+		case <-time.After(20 * time.Millisecond):
+		}
+		clk.Advance(time.Second)
+		if err := <-done; err != nil {
+			t.Fatalf("Retry-After %q: %v", retryAfter, err)
+		}
+		if n := atomic.LoadInt32(calls); n != 2 {
+			t.Fatalf("Retry-After %q: %v requests, want 2", retryAfter, n)
+		}
+	}
+}
+
+func TestDeliverRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses [][2]string
+		requests  int32
+		code      int
+	}{
+		{name: "rate limited", responses: [][2]string{{"429", ""}}, requests: 2},
+		{name: "unavailable", responses: [][2]string{{"503", ""}, {"503", ""}}, requests: 3},
+		{name: "exhausted", responses: [][2]string{{"429", ""}, {"429", ""}, {"429", ""}}, requests: 3, code: http.StatusTooManyRequests},
+		{name: "bad request", responses: [][2]string{{"400", ""}}, requests: 1, code: http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		srv, calls := statusServer(t, test.responses...)
+		err := newTestSink(t, srv.URL, nil).Deliver(context.Background(), testDelivery)
+		var statusErr *StatusError
+		if test.code == 0 && err != nil || test.code != 0 && (!errors.As(err, &statusErr) || statusErr.Code != test.code) {
+			t.Errorf("%v: got %v, want status %v", test.name, err, test.code)
+		}
+		// the default client must not retry itself
+		if n := atomic.LoadInt32(calls); n != test.requests {
+			t.Errorf("%v: %v requests, want %v", test.name, n, test.requests)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package decode
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Uint64 decodes a hex quantity, with or without 0x prefix and leading zeros.
+func Uint64(s string) (uint64, error) {
+	v, err := hexutil.DecodeUint64(s)
+	if err == nil {
+		return v, nil
+	}
+	v, perr := strconv.ParseUint(trim(s), 16, 64)
+	if perr != nil {
+		return 0, fmt.Errorf("invalid hex quantity %q: %v", s, err)
+	}
+	return v, nil
+}
+
+// Big decodes a hex quantity, with or without 0x prefix and leading zeros.
+func Big(s string) (*big.Int, error) {
+	v, err := hexutil.DecodeBig(s)
+	if err == nil {
+		return v, nil
+	}
+	digits := trim(s)
+	v, ok := new(big.Int).SetString(digits, 16)
+	if !ok || digits[0] == '-' || digits[0] == '+' {
+		return nil, fmt.Errorf("invalid hex quantity %q: %v", s, err)
+	}
+	if v.BitLen() > 256 {
+		return nil, fmt.Errorf("invalid hex quantity %q: larger than 256 bits", s)
+	}
+	return v, nil
+}
+
+// Bytes decodes hex data, with or without 0x prefix.
+func Bytes(s string) ([]byte, error) {
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		s = "0x" + s
+	}
+	return hexutil.Decode(s)
+}
+
+// Hash decodes a 32 byte hash, failing on other lengths.
+func Hash(s string) (common.Hash, error) {
+	b, err := Bytes(s)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if len(b) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("invalid hash %q: %v bytes", s, len(b))
+	}
+	return common.BytesToHash(b), nil
+}
+
+// Address decodes a 20 byte address, failing on other lengths.
+func Address(s string) (common.Address, error) {
+	b, err := Bytes(s)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(b) != common.AddressLength {
+		return common.Address{}, fmt.Errorf("invalid address %q: %v bytes", s, len(b))
+	}
+	return common.BytesToAddress(b), nil
+}
+
+func trim(s string) string {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if s == "" {
+		return "0"
+	}
+	return s
+}
+
+// UnknownFields returns the keys of r which are not in known, sorted.
+func UnknownFields(r map[string]interface{}, known ...string) []string {
+	set := make(map[string]bool, len(known))
+	for _, k := range known {
+		set[k] = true
+	}
+	var unknown []string
+	for k := range r {
+		if !set[k] {
+			unknown = append(unknown, k)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
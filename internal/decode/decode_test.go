@@ -0,0 +1,151 @@
+package decode
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestUint64(t *testing.T) {
+	tests := []struct {
+		in   string
+		want uint64
+		err  bool
+	}{
+		{in: "0x0", want: 0},
+		{in: "0x1a", want: 26},
+		{in: "0x001a", want: 26},
+		{in: "1a", want: 26},
+		{in: "0x", want: 0},
+		{in: "0xffffffffffffffff", want: 1<<64 - 1},
+		{in: "0x10000000000000000", err: true},
+		{in: "0xzz", err: true},
+		{in: "-0x1", err: true},
+		{in: "0x1 ", err: true},
+	}
+	for _, test := range tests {
+		got, err := Uint64(test.in)
+		if (err != nil) != test.err || got != test.want {
+			t.Errorf("Uint64(%q) = %v, %v, want %v, error %v", test.in, got, err, test.want, test.err)
+		}
+	}
+}
+
+func TestBig(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		err  bool
+	}{
+		{in: "0x0", want: "0"},
+		{in: "0x00ff", want: "255"},
+		{in: "ff", want: "255"},
+		{in: "0x" + strings.Repeat("f", 64), want: new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1)).String()},
+		{in: "0x1" + strings.Repeat("0", 64), err: true},
+		{in: "0x" + strings.Repeat("00", 40) + "1", want: "1"},
+		{in: "0xg", err: true},
+		{in: "0x-1", err: true},
+		{in: "+1", err: true},
+	}
+	for _, test := range tests {
+		got, err := Big(test.in)
+		if (err != nil) != test.err || (err == nil && got.String() != test.want) {
+			t.Errorf("Big(%q) = %v, %v, want %v, error %v", test.in, got, err, test.want, test.err)
+		}
+	}
+}
+
+func TestBytes(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		err  bool
+	}{
+		{in: "0x", want: ""},
+		{in: "", want: ""},
+		{in: "0xabcd", want: "abcd"},
+		{in: "abcd", want: "abcd"},
+		{in: "0XABCD", want: "abcd"},
+		{in: "0xabc", err: true},
+		{in: "0xabzz", err: true},
+	}
+	for _, test := range tests {
+		got, err := Bytes(test.in)
+		if (err != nil) != test.err || (err == nil && string(got) != string(mustHex(test.want))) {
+			t.Errorf("Bytes(%q) = %x, %v, want %v, error %v", test.in, got, err, test.want, test.err)
+		}
+	}
+}
+
+func TestHashAndAddress(t *testing.T) {
+	hash := "0x" + strings.Repeat("ab", 32)
+	address := "0x" + strings.Repeat("cd", 20)
+	if h, err := Hash(hash); err != nil || h.Hex() != hash {
+		t.Errorf("Hash(%q) = %v, %v", hash, h.Hex(), err)
+	}
+	if a, err := Address(address); err != nil || !strings.EqualFold(a.Hex(), address) {
+		t.Errorf("Address(%q) = %v, %v", address, a.Hex(), err)
+	}
+	for _, in := range []string{"0x", hash[:len(hash)-2], hash + "00", hash[:len(hash)-1], address} {
+		if _, err := Hash(in); err == nil {
+			t.Errorf("Hash(%q) succeeded", in)
+		}
+	}
+	for _, in := range []string{"0x", address[:len(address)-2], address + "00", hash} {
+		if _, err := Address(in); err == nil {
+			t.Errorf("Address(%q) succeeded", in)
+		}
+	}
+}
+
+func TestFields(t *testing.T) {
+	var r map[string]interface{}
+	if err := json.Unmarshal([]byte(`{"s":"x","n":null,"i":1,"a":["x",1],"o":{}}`), &r); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		key   string
+		ok    bool
+		err   bool
+		array bool
+	}{
+		{key: "s", ok: true},
+		{key: "n"},
+		{key: "missing"},
+		{key: "i", err: true},
+		{key: "o", err: true},
+		{key: "a", err: true},
+		{key: "a", ok: true, array: true},
+		{key: "s", err: true, array: true},
+		{key: "n", array: true},
+		{key: "o", err: true, array: true},
+	}
+	for _, test := range tests {
+		var ok bool
+		var err error
+		if test.array {
+			_, ok, err = Array(r, test.key)
+		} else {
+			_, ok, err = String(r, test.key)
+		}
+		if ok != test.ok || (err != nil) != test.err {
+			t.Errorf("%v (array %v): ok %v, err %v, want ok %v, error %v", test.key, test.array, ok, err, test.ok, test.err)
+		}
+	}
+	if _, err := RequiredString(r, "n"); err == nil {
+		t.Error("RequiredString of null succeeded")
+	}
+	a, _, _ := Array(r, "a")
+	if _, err := StringElement("a", a, 1); err == nil {
+		t.Error("StringElement of number succeeded")
+	}
+}
+
+func mustHex(s string) []byte {
+	b, err := Bytes(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
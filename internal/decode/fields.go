@@ -0,0 +1,52 @@
+package decode
+
+import "fmt"
+
+// String returns the string field key of r. ok is false if the field is
+// missing or null, err is set if it holds another type.
+func String(r map[string]interface{}, key string) (s string, ok bool, err error) {
+	v, present := r[key]
+	if !present || v == nil {
+		return "", false, nil
+	}
+	s, isString := v.(string)
+	if !isString {
+		return "", false, fmt.Errorf("invalid %v: expected string, got %T", key, v)
+	}
+	return s, true, nil
+}
+
+// RequiredString returns the string field key of r, failing if it is missing or null.
+func RequiredString(r map[string]interface{}, key string) (string, error) {
+	s, ok, err := String(r, key)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("%v not found", key)
+	}
+	return s, nil
+}
+
+// Array returns the array field key of r. ok is false if the field is
+// missing or null, err is set if it holds another type.
+func Array(r map[string]interface{}, key string) (a []interface{}, ok bool, err error) {
+	v, present := r[key]
+	if !present || v == nil {
+		return nil, false, nil
+	}
+	a, isArray := v.([]interface{})
+	if !isArray {
+		return nil, false, fmt.Errorf("invalid %v: expected array, got %T", key, v)
+	}
+	return a, true, nil
+}
+
+// StringElement returns element i of the array field key as a string.
+func StringElement(key string, a []interface{}, i int) (string, error) {
+	s, ok := a[i].(string)
+	if !ok {
+		return "", fmt.Errorf("invalid %v[%v]: expected string, got %T", key, i, a[i])
+	}
+	return s, nil
+}
@@ -0,0 +1,28 @@
+//go:build go1.18
+// +build go1.18
+
+package decode
+
+import (
+	"testing"
+)
+
+func FuzzQuantity(f *testing.F) {
+	for _, seed := range []string{"0x0", "0x1e7", "1e7", "0x", "", "0X00ff", "0x10000000000000000", "0x-1", "+1"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		u, uerr := Uint64(s)
+		b, berr := Big(s)
+		if berr == nil && b.BitLen() > 256 {
+			t.Fatalf("Big(%q) = %v, larger than 256 bits", s, b)
+		}
+		if berr == nil && b.Sign() < 0 {
+			t.Fatalf("Big(%q) = %v, negative", s, b)
+		}
+		// quantities fitting 64 bits decode the same way
+		if uerr == nil && (berr != nil || !b.IsUint64() || b.Uint64() != u) {
+			t.Fatalf("Uint64(%q) = %v, but Big = %v, %v", s, u, b, berr)
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package decode
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mode controls how RPC responses with unexpected content are decoded. It is
+// exported as privacy.DecodeMode.
+type Mode int
+
+const (
+	// Lenient skips malformed list entries, e.g. group members or logs, and
+	// ignores unknown fields. Use it against nodes newer than this library.
+	Lenient Mode = iota
+	// Strict fails on malformed list entries, unknown and missing fields.
+	// Use it in CI and tests.
+	Strict
+)
+
+// CheckFields returns an error in Strict mode if r has fields not in known.
+func (mode Mode) CheckFields(r map[string]interface{}, known ...string) error {
+	if mode != Strict {
+		return nil
+	}
+	if unknown := UnknownFields(r, known...); len(unknown) > 0 {
+		return fmt.Errorf("unknown fields: %v", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// Skip returns nil in Lenient mode, meaning the malformed entry can be skipped, and err in Strict mode.
+func (mode Mode) Skip(err error) error {
+	if mode != Strict {
+		return nil
+	}
+	return err
+}
+
+func (mode Mode) String() string {
+	switch mode {
+	case Lenient:
+		return "lenient"
+	case Strict:
+		return "strict"
+	default:
+		return fmt.Sprintf("DecodeMode(%d)", int(mode))
+	}
+}
@@ -0,0 +1,31 @@
+// Package encoding holds the encoding helpers shared by the public packages.
+package encoding
+
+import (
+	"hash"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"golang.org/x/crypto/sha3"
+)
+
+// hashers reuses Keccak-256 states, as each transaction signed or verified
+// hashes its RLP encoding.
+var hashers = sync.Pool{
+	New: func() interface{} { return sha3.NewLegacyKeccak256() },
+}
+
+// RLPHash returns the Keccak-256 hash of the RLP encoding of x, the zero hash
+// if x can not be encoded.
+func RLPHash(x interface{}) (h common.Hash) {
+	hw := hashers.Get().(hash.Hash)
+	defer hashers.Put(hw)
+	hw.Reset()
+	err := rlp.Encode(hw, x)
+	if err != nil {
+		return common.Hash{}
+	}
+	hw.Sum(h[:0])
+	return h
+}
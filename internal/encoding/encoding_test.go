@@ -0,0 +1,65 @@
+package encoding
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func TestRLPHash(t *testing.T) {
+	to := common.HexToAddress("0x42699a7612a82f1d9c36148af9c77354759b210b")
+	values := []interface{}{
+		uint64(0),
+		"restricted",
+		[]byte{},
+		make([]byte, 1024),
+		big.NewInt(2018),
+		[]interface{}{uint64(1), &to, [][]byte{{1}, {2, 3}}, rlp.RawValue{0x82, 0x07, 0xe2}},
+	}
+	for _, v := range values {
+		enc, err := rlp.EncodeToBytes(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := RLPHash(v), crypto.Keccak256Hash(enc); got != want {
+			t.Errorf("RLPHash(%v) = %v, want %v", v, got.Hex(), want.Hex())
+		}
+	}
+	// keccak256(rlp("")) = keccak256(0x80)
+	if got := RLPHash([]byte{}); got != common.HexToHash("0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421") {
+		t.Errorf("RLPHash of the empty string = %v", got.Hex())
+	}
+}
+
+func TestRLPHashUnencodable(t *testing.T) {
+	if got := RLPHash(map[string]int{"a": 1}); got != (common.Hash{}) {
+		t.Errorf("RLPHash of a map = %v, want zero", got.Hex())
+	}
+	// a failed encoding must not leave state in the pooled hasher
+	if got, want := RLPHash(uint64(1)), crypto.Keccak256Hash([]byte{1}); got != want {
+		t.Errorf("RLPHash after a failure = %v, want %v", got.Hex(), want.Hex())
+	}
+}
+
+func TestRLPHashConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				v := []uint64{uint64(i), uint64(j)}
+				enc, _ := rlp.EncodeToBytes(v)
+				if got, want := RLPHash(v), crypto.Keccak256Hash(enc); got != want {
+					t.Errorf("RLPHash(%v) = %v, want %v", v, got.Hex(), want.Hex())
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,109 @@
+// Package labels attaches application-level labels to private transactions.
+// Labels never go on-chain: they are carried by the context of a send and
+// recorded off-chain by the hash of its privacy marker transaction.
+package labels
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/bsostech/go-besu/cache"
+)
+
+type labelsKey struct{}
+
+// Labels are key-value pairs describing a transaction, e.g. {"flow": "settlement"}.
+type Labels map[string]string
+
+// String formats l as sorted key=value pairs separated by commas.
+func (l Labels) String() string {
+	keys := make([]string, 0, len(l))
+	for k := range l {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + l[k]
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Store records the labels of sent transactions.
+type Store interface {
+	Put(txHash common.Hash, l Labels)
+	// Get returns the labels of txHash, ok is false if none were recorded.
+	Get(txHash common.Hash) (l Labels, ok bool)
+}
+
+// WithLabels returns a context carrying l, merged over the labels of ctx.
+func WithLabels(ctx context.Context, l Labels) context.Context {
+	merged := make(Labels)
+	for k, v := range FromContext(ctx) {
+		merged[k] = v
+	}
+	for k, v := range l {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, labelsKey{}, merged)
+}
+
+// FromContext returns the labels of ctx, nil if none.
+func FromContext(ctx context.Context) Labels {
+	l, _ := ctx.Value(labelsKey{}).(Labels)
+	return l
+}
+
+// Deleter is implemented by stores which can remove labels, e.g. once they
+// are indexed.
+type Deleter interface {
+	Delete(txHash common.Hash)
+}
+
+// DefaultMemoryStoreSize is the number of transactions a MemoryStore keeps
+// the labels of by default.
+const DefaultMemoryStoreSize = 100000
+
+// MemoryStore is a Store in memory, keeping the labels of a bounded number
+// of transactions and evicting the least recently used ones.
+type MemoryStore struct {
+	labels *cache.LRU
+}
+
+// NewMemoryStore returns a MemoryStore keeping the labels of at most size
+// transactions, DefaultMemoryStoreSize if size is 0.
+func NewMemoryStore(size int) *MemoryStore {
+	if size == 0 {
+		size = DefaultMemoryStoreSize
+	}
+	return &MemoryStore{
+		labels: cache.NewLRU(size),
+	}
+}
+
+// Put implements Store.
+func (s *MemoryStore) Put(txHash common.Hash, l Labels) {
+	s.labels.Add(txHash.Hex(), l)
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(txHash common.Hash) (Labels, bool) {
+	l, ok := s.labels.Get(txHash.Hex())
+	if !ok {
+		return nil, false
+	}
+	return l.(Labels), true
+}
+
+// Delete implements Deleter.
+func (s *MemoryStore) Delete(txHash common.Hash) {
+	s.labels.Remove(txHash.Hex())
+}
+
+// Len returns the number of transactions labels are kept for.
+func (s *MemoryStore) Len() int {
+	return s.labels.Len()
+}
@@ -0,0 +1,43 @@
+package labels
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestMemoryStoreBounded(t *testing.T) {
+	s := NewMemoryStore(2)
+	for i := byte(1); i <= 3; i++ {
+		s.Put(common.Hash{i}, Labels{"n": string('0' + i)})
+	}
+	if s.Len() != 2 {
+		t.Fatalf("%v labels kept, want 2", s.Len())
+	}
+	if _, ok := s.Get(common.Hash{1}); ok {
+		t.Fatal("oldest labels not evicted")
+	}
+	if l, ok := s.Get(common.Hash{3}); !ok || l["n"] != "3" {
+		t.Fatalf("got %v, %v", l, ok)
+	}
+	var d Deleter = s
+	d.Delete(common.Hash{3})
+	if _, ok := s.Get(common.Hash{3}); ok || s.Len() != 1 {
+		t.Fatal("labels not deleted")
+	}
+	if NewMemoryStore(0).labels == nil {
+		t.Fatal("default store not created")
+	}
+}
+
+func TestWithLabels(t *testing.T) {
+	ctx := WithLabels(context.Background(), Labels{"flow": "settlement", "app": "treasury"})
+	ctx = WithLabels(ctx, Labels{"flow": "refund"})
+	if got := FromContext(ctx).String(); got != "app=treasury,flow=refund" {
+		t.Fatalf("labels %v", got)
+	}
+	if FromContext(context.Background()) != nil {
+		t.Fatal("labels of an empty context")
+	}
+}
@@ -0,0 +1,193 @@
+// Package latency breaks down the end-to-end latency of private transactions
+// into signing, distribution, marker inclusion and private receipt
+// availability, per transaction and as histograms.
+package latency
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/bsostech/go-besu/client"
+	"github.com/bsostech/go-besu/loadtest"
+	"github.com/bsostech/go-besu/types"
+)
+
+// Stage is a stage of a private transaction.
+type Stage int
+
+// Stage .
+const (
+	// Sign is the signing of the private transaction.
+	Sign Stage = iota
+	// Distribute is eea_sendRawTransaction, distributing the payload to the
+	// enclaves and submitting the privacy marker transaction.
+	Distribute
+	// Inclusion is from submission to the block of the marker.
+	Inclusion
+	// Receipt is from the block of the marker to the private receipt being received.
+	Receipt
+	// Total is from signing to the private receipt being received.
+	Total
+)
+
+// Stages are all stages, in order.
+var Stages = []Stage{Sign, Distribute, Inclusion, Receipt, Total}
+
+var stageNames = []string{"sign", "distribute", "inclusion", "receipt", "total"}
+
+func (s Stage) String() string {
+	if int(s) < len(stageNames) {
+		return stageNames[s]
+	}
+	return "unknown"
+}
+
+// Breakdown is the latency of a transaction per stage. Inclusion and Receipt
+// are split at the block timestamp, so they have second resolution.
+type Breakdown struct {
+	Hash       common.Hash
+	Sign       time.Duration
+	Distribute time.Duration
+	Inclusion  time.Duration
+	Receipt    time.Duration
+	Total      time.Duration
+}
+
+// Duration returns the latency of stage.
+func (b *Breakdown) Duration(stage Stage) time.Duration {
+	switch stage {
+	case Sign:
+		return b.Sign
+	case Distribute:
+		return b.Distribute
+	case Inclusion:
+		return b.Inclusion
+	case Receipt:
+		return b.Receipt
+	default:
+		return b.Total
+	}
+}
+
+// Tracker measures the transactions sent with its Send. The private receipts
+// have to be awaited through the client, e.g. with WaitForReceipt or
+// SendAsync, for the last stages to be measured.
+type Tracker struct {
+	// Handler is called with the breakdown of each completed transaction.
+	Handler func(Breakdown)
+
+	client      *client.Client
+	unsubscribe func()
+	histograms  map[Stage]*loadtest.Histogram
+
+	mu      sync.Mutex
+	pending map[common.Hash]*pendingTx
+}
+
+type pendingTx struct {
+	breakdown Breakdown
+	started   time.Time
+	submitted time.Time
+}
+
+// NewTracker returns a tracker with histograms of loadtest.DefaultBounds.
+func NewTracker(c *client.Client) *Tracker {
+	t := &Tracker{
+		client:     c,
+		histograms: make(map[Stage]*loadtest.Histogram),
+		pending:    make(map[common.Hash]*pendingTx),
+	}
+	for _, stage := range Stages {
+		t.histograms[stage] = loadtest.NewHistogram(loadtest.DefaultBounds)
+	}
+	t.unsubscribe = c.Subscribe(t.handle)
+	return t
+}
+
+// Close stops tracking.
+func (t *Tracker) Close() {
+	t.unsubscribe()
+}
+
+// Send signs and sends tx, measuring its stages.
+func (t *Tracker) Send(ctx context.Context, tx *types.PrivateTransaction, key *ecdsa.PrivateKey, chainID *big.Int) (common.Hash, error) {
+	p := &pendingTx{started: time.Now()}
+	signed, err := tx.SignTx(chainID, key)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	p.breakdown.Sign = time.Since(p.started)
+	sending := time.Now()
+	pmtHash, err := t.client.SendTransaction(ctx, signed)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	p.submitted = time.Now()
+	p.breakdown.Hash = pmtHash
+	p.breakdown.Distribute = p.submitted.Sub(sending)
+	t.mu.Lock()
+	t.pending[pmtHash] = p
+	t.mu.Unlock()
+	return pmtHash, nil
+}
+
+// Histogram returns the histogram of stage.
+func (t *Tracker) Histogram(stage Stage) *loadtest.Histogram {
+	return t.histograms[stage]
+}
+
+// Pending returns the number of transactions without private receipt.
+func (t *Tracker) Pending() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.pending)
+}
+
+func (t *Tracker) handle(e client.Event) {
+	if e.Type != client.PrivateReceiptAvailable && e.Type != client.Failed {
+		return
+	}
+	t.mu.Lock()
+	p, ok := t.pending[e.Hash]
+	if ok {
+		delete(t.pending, e.Hash)
+	}
+	t.mu.Unlock()
+	if !ok || e.Type == client.Failed && e.Receipt == nil {
+		return
+	}
+	// the block timestamp is fetched aside, event handlers must not block
+	go t.complete(p, e.Receipt, e.Time)
+}
+
+func (t *Tracker) complete(p *pendingTx, receipt *types.PrivateReceipt, received time.Time) {
+	b := p.breakdown
+	b.Total = received.Sub(p.started)
+	b.Receipt = received.Sub(p.submitted)
+	if receipt.BlockNumber != nil {
+		header, err := t.client.Eth().HeaderByNumber(context.Background(), receipt.BlockNumber)
+		if err == nil {
+			mined := time.Unix(int64(header.Time), 0)
+			b.Inclusion = clamp(mined.Sub(p.submitted))
+			b.Receipt = clamp(received.Sub(mined))
+		}
+	}
+	for _, stage := range Stages {
+		t.histograms[stage].Observe(b.Duration(stage))
+	}
+	if t.Handler != nil {
+		t.Handler(b)
+	}
+}
+
+func clamp(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
@@ -0,0 +1,202 @@
+// Package loadtest drives private sends at a configured rate across privacy
+// groups and reports latencies and errors, for capacity planning of Besu and
+// Tessera clusters.
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/bsostech/go-besu/client"
+	"github.com/bsostech/go-besu/privacy"
+	"github.com/bsostech/go-besu/types"
+)
+
+// SendFunc sends one private transaction to group and returns once it is done,
+// either accepted or mined depending on the SendFunc.
+type SendFunc func(ctx context.Context, group *privacy.Group) error
+
+// BuildFunc builds the next signed private transaction of group.
+type BuildFunc func(ctx context.Context, group *privacy.Group) (*types.PrivateTransaction, error)
+
+// Config .
+type Config struct {
+	Groups      []*privacy.Group
+	Rate        float64       // sends per second across all groups
+	Duration    time.Duration // how long sends are started for
+	Concurrency int           // maximum sends in flight, 0 means unlimited
+	Bounds      []time.Duration
+}
+
+// Report is the result of a run.
+type Report struct {
+	Started   uint64
+	Succeeded uint64
+	Failed    uint64
+	Skipped   uint64 // sends not started because Concurrency sends were in flight
+	Elapsed   time.Duration
+	Latency   *Histogram            // latency of successful sends
+	Groups    map[string]*Histogram // latency of successful sends by group ID
+	Errors    map[string]uint64     // failed sends by error class
+}
+
+// NewClientSend returns a SendFunc sending the transactions of build with c,
+// and waiting for their private receipt if waitReceipt is set.
+func NewClientSend(c *client.Client, build BuildFunc, waitReceipt bool) SendFunc {
+	return func(ctx context.Context, group *privacy.Group) error {
+		tx, err := build(ctx, group)
+		if err != nil {
+			return err
+		}
+		pmtHash, err := c.SendTransaction(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if !waitReceipt {
+			return nil
+		}
+		receipt, err := c.WaitForReceipt(ctx, pmtHash)
+		if err != nil {
+			return err
+		}
+		if receipt.Status != 1 {
+			return errors.New("transaction failed")
+		}
+		return nil
+	}
+}
+
+// Run starts sends at cfg.Rate for cfg.Duration, round robin across the
+// groups, and waits for the started sends to finish.
+func Run(ctx context.Context, cfg Config, send SendFunc) (*Report, error) {
+	if len(cfg.Groups) == 0 {
+		return nil, errors.New("no privacy groups")
+	}
+	if cfg.Rate <= 0 {
+		return nil, fmt.Errorf("invalid rate %v", cfg.Rate)
+	}
+	bounds := cfg.Bounds
+	if bounds == nil {
+		bounds = DefaultBounds
+	}
+	report := &Report{
+		Latency: NewHistogram(bounds),
+		Groups:  make(map[string]*Histogram),
+		Errors:  make(map[string]uint64),
+	}
+	for _, g := range cfg.Groups {
+		report.Groups[g.ID] = NewHistogram(bounds)
+	}
+	var slots chan struct{}
+	if cfg.Concurrency > 0 {
+		slots = make(chan struct{}, cfg.Concurrency)
+	}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / cfg.Rate))
+	defer ticker.Stop()
+	start := time.Now()
+	deadline := time.NewTimer(cfg.Duration)
+	defer deadline.Stop()
+loop:
+	for n := 0; ; n++ {
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-deadline.C:
+			break loop
+		case <-ticker.C:
+		}
+		if slots != nil {
+			select {
+			case slots <- struct{}{}:
+			default:
+				mu.Lock()
+				report.Skipped++
+				mu.Unlock()
+				continue
+			}
+		}
+		group := cfg.Groups[n%len(cfg.Groups)]
+		mu.Lock()
+		report.Started++
+		mu.Unlock()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sent := time.Now()
+			err := send(ctx, group)
+			latency := time.Since(sent)
+			if slots != nil {
+				<-slots
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				report.Failed++
+				report.Errors[ErrorClass(err)]++
+				return
+			}
+			report.Succeeded++
+			report.Latency.Observe(latency)
+			report.Groups[group.ID].Observe(latency)
+		}()
+	}
+	wg.Wait()
+	report.Elapsed = time.Since(start)
+	return report, nil
+}
+
+// ErrorClass groups errors for the error breakdown: JSON-RPC errors by code
+// and message, others by message.
+func ErrorClass(err error) string {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return fmt.Sprintf("rpc %v: %v", rpcErr.ErrorCode(), rpcErr.Error())
+	}
+	return err.Error()
+}
+
+// Throughput returns the successful sends per second.
+func (r *Report) Throughput() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Succeeded) / r.Elapsed.Seconds()
+}
+
+// String formats the report for the terminal.
+func (r *Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "elapsed %v, started %v, succeeded %v, failed %v, skipped %v, %.2f tx/s\n",
+		r.Elapsed, r.Started, r.Succeeded, r.Failed, r.Skipped, r.Throughput())
+	fmt.Fprintf(&b, "latency min %v, mean %v, p50 %v, p90 %v, p99 %v, max %v\n",
+		r.Latency.Min(), r.Latency.Mean(), r.Latency.Quantile(0.5), r.Latency.Quantile(0.9), r.Latency.Quantile(0.99), r.Latency.Max())
+	ids := make([]string, 0, len(r.Groups))
+	for id := range r.Groups {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		h := r.Groups[id]
+		fmt.Fprintf(&b, "group %v: %v sends, mean %v, p99 %v\n", id, h.Count(), h.Mean(), h.Quantile(0.99))
+	}
+	classes := make([]string, 0, len(r.Errors))
+	for class := range r.Errors {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	for _, class := range classes {
+		fmt.Fprintf(&b, "error %v: %v\n", class, r.Errors[class])
+	}
+	return b.String()
+}
@@ -0,0 +1,141 @@
+// Package merkle builds Merkle trees over document sets, commits their roots
+// privately with a blob.Store, and proves membership of documents against a
+// committed root, a common audit pattern: the documents stay off-chain while
+// the root proves the set existed at the block it was committed in.
+//
+// Leaves are keccak256(0x00 || document) and nodes keccak256(0x01 || a || b)
+// with a <= b, so proofs need no positions. A node without sibling is
+// promoted to the next level.
+package merkle
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bsostech/go-besu/blob"
+)
+
+// Tree is a Merkle tree over a set of documents.
+type Tree struct {
+	levels [][]common.Hash // leaves first, root last
+}
+
+// Proof proves a leaf is in the tree of a root.
+type Proof struct {
+	Leaf     common.Hash   `json:"leaf"`
+	Siblings []common.Hash `json:"siblings"`
+}
+
+// LeafHash returns the leaf of doc.
+func LeafHash(doc []byte) common.Hash {
+	return crypto.Keccak256Hash([]byte{0}, doc)
+}
+
+func nodeHash(a, b common.Hash) common.Hash {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+	return crypto.Keccak256Hash([]byte{1}, a[:], b[:])
+}
+
+// New returns the tree of docs.
+func New(docs [][]byte) *Tree {
+	leaves := make([]common.Hash, len(docs))
+	for i, doc := range docs {
+		leaves[i] = LeafHash(doc)
+	}
+	return NewFromLeaves(leaves)
+}
+
+// NewFromLeaves returns the tree of leaves, e.g. computed by LeafHash elsewhere.
+func NewFromLeaves(leaves []common.Hash) *Tree {
+	t := &Tree{levels: [][]common.Hash{leaves}}
+	for level := leaves; len(level) > 1; {
+		next := make([]common.Hash, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+			} else {
+				next = append(next, nodeHash(level[i], level[i+1]))
+			}
+		}
+		t.levels = append(t.levels, next)
+		level = next
+	}
+	return t
+}
+
+// Root returns the root of the tree, the zero hash if empty.
+func (t *Tree) Root() common.Hash {
+	top := t.levels[len(t.levels)-1]
+	if len(top) == 0 {
+		return common.Hash{}
+	}
+	return top[0]
+}
+
+// Len returns the number of leaves.
+func (t *Tree) Len() int {
+	return len(t.levels[0])
+}
+
+// Proof returns the proof of leaf i.
+func (t *Tree) Proof(i int) (*Proof, error) {
+	if i < 0 || i >= t.Len() {
+		return nil, fmt.Errorf("leaf %v not found", i)
+	}
+	p := &Proof{Leaf: t.levels[0][i]}
+	for _, level := range t.levels[:len(t.levels)-1] {
+		if sibling := i ^ 1; sibling < len(level) {
+			p.Siblings = append(p.Siblings, level[sibling])
+		}
+		i /= 2
+	}
+	return p, nil
+}
+
+// Root returns the root the proof leads to.
+func (p *Proof) Root() common.Hash {
+	h := p.Leaf
+	for _, sibling := range p.Siblings {
+		h = nodeHash(h, sibling)
+	}
+	return h
+}
+
+// Verify reports whether p proves doc is in the tree of root.
+func (p *Proof) Verify(root common.Hash, doc []byte) bool {
+	return LeafHash(doc) == p.Leaf && p.Root() == root
+}
+
+// Commit stores root in st, the privacy group of st witnessing it.
+func Commit(ctx context.Context, st *blob.Store, root common.Hash) (*blob.Ref, error) {
+	return st.StorePrivateBlob(ctx, root.Bytes())
+}
+
+// Committed returns the block root was first committed in with st, 0 if never.
+func Committed(ctx context.Context, st *blob.Store, root common.Hash) (uint64, error) {
+	return st.Recorded(ctx, crypto.Keccak256Hash(root.Bytes()))
+}
+
+// VerifyCommitted checks that p proves doc is in a tree whose root was
+// committed with st, queried with priv_call, and returns the block it was
+// committed in.
+func VerifyCommitted(ctx context.Context, st *blob.Store, doc []byte, p *Proof) (uint64, error) {
+	if LeafHash(doc) != p.Leaf {
+		return 0, fmt.Errorf("document does not match leaf %v", p.Leaf.Hex())
+	}
+	root := p.Root()
+	block, err := Committed(ctx, st, root)
+	if err != nil {
+		return 0, err
+	}
+	if block == 0 {
+		return 0, fmt.Errorf("root %v not committed", root.Hex())
+	}
+	return block, nil
+}
@@ -0,0 +1,199 @@
+// Package multisig coordinates private transactions requiring the approval
+// of several parties, Gnosis Safe style: a proposal is created, owners sign
+// it off-chain and a submitter sends it to the safe contract once the
+// threshold is reached.
+package multisig
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bsostech/go-besu/types"
+)
+
+// SafeABI is the interface of the reference safe contract, executing a call
+// once signatures of enough owners, sorted by owner address, are given.
+const SafeABI = `[{"constant":false,"inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"},{"name":"data","type":"bytes"},{"name":"nonce","type":"uint256"},{"name":"signatures","type":"bytes"}],"name":"execTransaction","outputs":[{"name":"success","type":"bool"}],"payable":false,"stateMutability":"nonpayable","type":"function"}]`
+
+// Policy are the owners of a safe and how many of them have to sign.
+type Policy struct {
+	Owners    []common.Address
+	Threshold int
+}
+
+// Valid .
+func (p Policy) Valid() error {
+	if p.Threshold <= 0 || p.Threshold > len(p.Owners) {
+		return fmt.Errorf("invalid threshold %v of %v owners", p.Threshold, len(p.Owners))
+	}
+	return nil
+}
+
+func (p Policy) isOwner(addr common.Address) bool {
+	for _, owner := range p.Owners {
+		if owner == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// Proposal is a call of a safe in the private state of a privacy group,
+// collecting the signatures of its owners. Proposals are exchanged as JSON.
+type Proposal struct {
+	Safe           common.Address                   `json:"safe"`
+	PrivacyGroupID string                           `json:"privacyGroupId"`
+	To             common.Address                   `json:"to"`
+	Value          *hexutil.Big                     `json:"value"`
+	Data           hexutil.Bytes                    `json:"data"`
+	Nonce          uint64                           `json:"nonce"` // nonce of the safe
+	Signatures     map[common.Address]hexutil.Bytes `json:"signatures"`
+
+	mu sync.Mutex
+}
+
+// NewProposal .
+func NewProposal(safe common.Address, privacyGroupID string, to common.Address, value *big.Int, data []byte, nonce uint64) *Proposal {
+	if value == nil {
+		value = new(big.Int)
+	}
+	return &Proposal{
+		Safe:           safe,
+		PrivacyGroupID: privacyGroupID,
+		To:             to,
+		Value:          (*hexutil.Big)(value),
+		Data:           data,
+		Nonce:          nonce,
+		Signatures:     make(map[common.Address]hexutil.Bytes),
+	}
+}
+
+// Hash returns the hash owners sign, binding the call to the safe and the privacy group.
+func (p *Proposal) Hash() common.Hash {
+	var nonce [8]byte
+	binary.BigEndian.PutUint64(nonce[:], p.Nonce)
+	return crypto.Keccak256Hash(
+		p.Safe.Bytes(),
+		[]byte(p.PrivacyGroupID),
+		p.To.Bytes(),
+		common.LeftPadBytes(p.Value.ToInt().Bytes(), 32),
+		crypto.Keccak256(p.Data),
+		nonce[:],
+	)
+}
+
+// Sign signs the proposal with key and attaches the signature.
+func (p *Proposal) Sign(key *ecdsa.PrivateKey) ([]byte, error) {
+	sig, err := crypto.Sign(p.Hash().Bytes(), key)
+	if err != nil {
+		return nil, err
+	}
+	sig[64] += 27
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.Signatures == nil {
+		p.Signatures = make(map[common.Address]hexutil.Bytes)
+	}
+	p.Signatures[crypto.PubkeyToAddress(key.PublicKey)] = sig
+	return sig, nil
+}
+
+// AddSignature attaches a signature collected from an owner, returning the signer.
+func (p *Proposal) AddSignature(policy Policy, sig []byte) (common.Address, error) {
+	signer, err := p.recover(sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if !policy.isOwner(signer) {
+		return common.Address{}, fmt.Errorf("signer %v is not an owner", signer.Hex())
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.Signatures == nil {
+		p.Signatures = make(map[common.Address]hexutil.Bytes)
+	}
+	p.Signatures[signer] = common.CopyBytes(sig)
+	return signer, nil
+}
+
+func (p *Proposal) recover(sig []byte) (common.Address, error) {
+	if len(sig) != 65 || (sig[64] != 27 && sig[64] != 28) {
+		return common.Address{}, fmt.Errorf("invalid signature")
+	}
+	s := common.CopyBytes(sig)
+	s[64] -= 27
+	pub, err := crypto.SigToPub(p.Hash().Bytes(), s)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// Signers returns the owners with a valid signature, sorted by address.
+func (p *Proposal) Signers(policy Policy) []common.Address {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var signers []common.Address
+	for addr, sig := range p.Signatures {
+		if signer, err := p.recover(sig); err == nil && signer == addr && policy.isOwner(addr) {
+			signers = append(signers, addr)
+		}
+	}
+	sort.Slice(signers, func(i, j int) bool {
+		return bytes.Compare(signers[i].Bytes(), signers[j].Bytes()) < 0
+	})
+	return signers
+}
+
+// Ready reports whether enough owners signed.
+func (p *Proposal) Ready(policy Policy) bool {
+	return len(p.Signers(policy)) >= policy.Threshold
+}
+
+// Input returns the input of the execTransaction call of the safe, with the
+// signatures of the first threshold signers sorted by address.
+func (p *Proposal) Input(policy Policy) ([]byte, error) {
+	if err := policy.Valid(); err != nil {
+		return nil, err
+	}
+	signers := p.Signers(policy)
+	if len(signers) < policy.Threshold {
+		return nil, fmt.Errorf("%v of %v signatures", len(signers), policy.Threshold)
+	}
+	var sigs []byte
+	p.mu.Lock()
+	for _, signer := range signers[:policy.Threshold] {
+		sigs = append(sigs, p.Signatures[signer]...)
+	}
+	p.mu.Unlock()
+	safeABI, err := abi.JSON(strings.NewReader(SafeABI))
+	if err != nil {
+		return nil, err
+	}
+	return safeABI.Pack("execTransaction", p.To, p.Value.ToInt(), []byte(p.Data), new(big.Int).SetUint64(p.Nonce), sigs)
+}
+
+// Transaction returns the unsigned private transaction of the submitter executing the proposal.
+func (p *Proposal) Transaction(policy Policy, profile types.NetworkProfile, nonce uint64, privateFrom []byte) (*types.PrivateTransaction, error) {
+	input, err := p.Input(policy)
+	if err != nil {
+		return nil, err
+	}
+	groupID, err := base64.StdEncoding.DecodeString(p.PrivacyGroupID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid privacyGroupId %v, err: %v", p.PrivacyGroupID, err)
+	}
+	return profile.NewPrivateTransaction(nonce, &p.Safe, nil, input, privateFrom, groupID), nil
+}
@@ -0,0 +1,180 @@
+// Package onboard creates the privacy groups of a network in bulk, e.g. when
+// organizations join: from a counterparty matrix or a list of organization
+// tuples, it plans the bilateral and multilateral groups and creates those
+// which do not exist yet. Besu only creates groups its own enclave key is a
+// member of, so each group is created through the node of one of its
+// members.
+package onboard
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bsostech/go-besu/privacy"
+)
+
+// Org is an organization of the network and the enclave key of its node.
+type Org struct {
+	Name string
+	Key  privacy.PublicKey
+}
+
+// Spec is a planned privacy group.
+type Spec struct {
+	Name        string
+	Description string
+	Orgs        []Org
+}
+
+// Members returns the enclave keys of the orgs of s.
+func (s *Spec) Members() []*privacy.PublicKey {
+	members := make([]*privacy.PublicKey, len(s.Orgs))
+	for i := range s.Orgs {
+		members[i] = &s.Orgs[i].Key
+	}
+	return members
+}
+
+// Plan is the list of groups to create, without duplicate participant sets.
+type Plan struct {
+	Groups []Spec
+}
+
+// FromMatrix plans a bilateral group for each pair of orgs marked in matrix,
+// an N×N matrix over orgs where matrix[i][j] or matrix[j][i] means orgs i and
+// j transact. The diagonal is ignored. Groups are named "a-b" after their orgs.
+func FromMatrix(orgs []Org, matrix [][]bool) (*Plan, error) {
+	if len(matrix) != len(orgs) {
+		return nil, fmt.Errorf("matrix has %v rows for %v orgs", len(matrix), len(orgs))
+	}
+	for i := range matrix {
+		if len(matrix[i]) != len(orgs) {
+			return nil, fmt.Errorf("row %v of matrix has %v columns for %v orgs", i, len(matrix[i]), len(orgs))
+		}
+	}
+	plan := &Plan{}
+	for i := range orgs {
+		for j := i + 1; j < len(orgs); j++ {
+			if matrix[i][j] || matrix[j][i] {
+				plan.add(Spec{Orgs: []Org{orgs[i], orgs[j]}})
+			}
+		}
+	}
+	return plan, nil
+}
+
+// FromTuples plans a group for each tuple of org names, bilateral or
+// multilateral. Groups are named after their orgs joined by "-".
+func FromTuples(orgs []Org, tuples [][]string) (*Plan, error) {
+	byName := make(map[string]Org, len(orgs))
+	for _, org := range orgs {
+		byName[org.Name] = org
+	}
+	plan := &Plan{}
+	for i, tuple := range tuples {
+		spec := Spec{}
+		for _, name := range tuple {
+			org, ok := byName[name]
+			if !ok {
+				return nil, fmt.Errorf("org %v of tuple %v not found", name, i)
+			}
+			spec.Orgs = append(spec.Orgs, org)
+		}
+		if privacy.NewParticipantSet(spec.Members()...).Len() < 2 {
+			return nil, fmt.Errorf("tuple %v has less than two orgs", i)
+		}
+		plan.add(spec)
+	}
+	return plan, nil
+}
+
+// add appends spec unless a group of the same participants is planned,
+// naming it after its orgs if it has no name.
+func (p *Plan) add(spec Spec) {
+	set := privacy.NewParticipantSet(spec.Members()...)
+	for i := range p.Groups {
+		if privacy.NewParticipantSet(p.Groups[i].Members()...).Equal(set) {
+			return
+		}
+	}
+	if spec.Name == "" {
+		names := make([]string, len(spec.Orgs))
+		for i, org := range spec.Orgs {
+			names[i] = org.Name
+		}
+		sort.Strings(names)
+		spec.Name = strings.Join(names, "-")
+	}
+	p.Groups = append(p.Groups, spec)
+}
+
+// Progress reports the outcome of one group of a plan.
+type Progress struct {
+	Done    int // groups processed, including this one
+	Total   int
+	Spec    *Spec
+	Group   *privacy.Group // nil if failed or skipped
+	Created bool           // false if the group existed
+	Err     error
+}
+
+// Report is the outcome of Apply.
+type Report struct {
+	Created  []*privacy.Group
+	Existing []*privacy.Group
+	// Skipped are the groups none of whose members has a node in nodes.
+	Skipped []Spec
+	Failed  map[string]error // by group name
+}
+
+// Apply creates the groups of p which do not exist, each through the node
+// of the first of its orgs found in nodes, keyed by enclave key in base64.
+// It is idempotent: existing groups are left unchanged, so it can be run
+// again after failures. A failing group does not stop the others; progress,
+// if not nil, is called after each group. Apply stops when ctx is done.
+func (p *Plan) Apply(ctx context.Context, nodes map[string]*privacy.Privacy, progress func(Progress)) (*Report, error) {
+	report := &Report{Failed: make(map[string]error)}
+	for i := range p.Groups {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		spec := &p.Groups[i]
+		pr := Progress{Done: i + 1, Total: len(p.Groups), Spec: spec}
+		node := spec.node(nodes)
+		if node == nil {
+			report.Skipped = append(report.Skipped, *spec)
+			pr.Err = fmt.Errorf("no node of the members of group %v", spec.Name)
+		} else {
+			pr.Group, pr.Created, pr.Err = node.CreateIfNotExists(spec.Members(), spec.Name, spec.Description)
+			switch {
+			case pr.Err != nil:
+				report.Failed[spec.Name] = pr.Err
+			case pr.Created:
+				report.Created = append(report.Created, pr.Group)
+			default:
+				report.Existing = append(report.Existing, pr.Group)
+			}
+		}
+		if progress != nil {
+			progress(pr)
+		}
+	}
+	return report, nil
+}
+
+// node returns the node of the first org of s found in nodes.
+func (s *Spec) node(nodes map[string]*privacy.Privacy) *privacy.Privacy {
+	for _, org := range s.Orgs {
+		if node, ok := nodes[org.Key.ToString()]; ok {
+			return node
+		}
+	}
+	return nil
+}
+
+// OK reports whether all groups exist.
+func (r *Report) OK() bool {
+	return len(r.Skipped) == 0 && len(r.Failed) == 0
+}
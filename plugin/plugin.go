@@ -0,0 +1,43 @@
+// Package plugin supports Besu privacy plugins, which replace the enclave:
+// the payload of a privacy marker transaction is produced and consumed by
+// the plugin instead of being a Tessera or Orion enclave key.
+package plugin
+
+import (
+	"context"
+
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/bsostech/go-besu/types"
+)
+
+// PayloadProcessor produces and consumes the payload of privacy marker
+// transactions, mirroring the PrivacyPluginPayloadProvider of the plugin the
+// nodes run.
+type PayloadProcessor interface {
+	// MarkerPayload returns the payload of the privacy marker transaction of tx.
+	MarkerPayload(ctx context.Context, tx *types.PrivateTransaction, privacyUserID string) ([]byte, error)
+	// PrivateTransaction returns the private transaction of a privacy marker transaction.
+	PrivateTransaction(ctx context.Context, marker *ethtypes.Transaction) (*types.PrivateTransaction, error)
+}
+
+// RLPProcessor is a PayloadProcessor using the RLP encoding of the signed
+// private transaction as payload, as the reference plugin of Besu does. It
+// provides no confidentiality and is meant for tests or as a base for
+// processors encrypting the payload.
+type RLPProcessor struct{}
+
+// MarkerPayload implements PayloadProcessor.
+func (RLPProcessor) MarkerPayload(ctx context.Context, tx *types.PrivateTransaction, privacyUserID string) ([]byte, error) {
+	return rlp.EncodeToBytes(tx)
+}
+
+// PrivateTransaction implements PayloadProcessor.
+func (RLPProcessor) PrivateTransaction(ctx context.Context, marker *ethtypes.Transaction) (*types.PrivateTransaction, error) {
+	tx := new(types.PrivateTransaction)
+	if err := rlp.DecodeBytes(marker.Data(), tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
@@ -0,0 +1,232 @@
+// Package policy enforces compliance rules on private transactions before
+// they are sent, such as gas limits, allowed recipients and groups, and
+// business hours.
+package policy
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/bsostech/go-besu/clock"
+	"github.com/bsostech/go-besu/types"
+)
+
+// ErrDenied is wrapped by the errors of denied transactions.
+var ErrDenied = errors.New("transaction denied by policy")
+
+// Action .
+type Action int
+
+// Action .
+const (
+	Allow Action = iota
+	Deny
+	// Modify replaces the transaction by Decision.Transaction. Signed
+	// transactions can not be modified, so modifications only apply to
+	// transactions checked before signing.
+	Modify
+)
+
+// Decision is the outcome of a policy check.
+type Decision struct {
+	Action      Action
+	Reason      string                    // why the transaction is denied or modified
+	Transaction *types.PrivateTransaction // the replacement, for Modify
+}
+
+// Policy checks a transaction before it is sent. Policies may see the same
+// transaction twice, before signing and when sent, so modifications must be
+// idempotent: a modified transaction is allowed when checked again.
+type Policy interface {
+	Check(ctx context.Context, tx *types.PrivateTransaction) (Decision, error)
+}
+
+// Recorder is implemented by policies accounting for sent transactions, such
+// as Quota. Record is called once a transaction the policy allowed is sent.
+type Recorder interface {
+	Record(ctx context.Context, tx *types.PrivateTransaction)
+}
+
+// Func is a Policy function.
+type Func func(ctx context.Context, tx *types.PrivateTransaction) (Decision, error)
+
+// Check implements Policy.
+func (f Func) Check(ctx context.Context, tx *types.PrivateTransaction) (Decision, error) {
+	return f(ctx, tx)
+}
+
+// DeniedError .
+type DeniedError struct {
+	Reason string
+}
+
+func (e *DeniedError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrDenied, e.Reason)
+}
+
+// Is reports whether target is ErrDenied.
+func (e *DeniedError) Is(target error) bool {
+	return target == ErrDenied
+}
+
+// Chain checks policies in order, each seeing the transaction modified by
+// the previous ones, and stops at the first denial. Sent transactions are
+// recorded by the policies which are Recorders.
+func Chain(policies ...Policy) Policy {
+	return chain(policies)
+}
+
+type chain []Policy
+
+// Check implements Policy.
+func (c chain) Check(ctx context.Context, tx *types.PrivateTransaction) (Decision, error) {
+	result := Decision{Action: Allow}
+	for _, p := range c {
+		d, err := p.Check(ctx, tx)
+		if err != nil {
+			return Decision{}, err
+		}
+		switch d.Action {
+		case Deny:
+			return d, nil
+		case Modify:
+			if d.Transaction == nil {
+				return Decision{}, fmt.Errorf("modify decision without transaction")
+			}
+			tx = d.Transaction
+			result = d
+		}
+	}
+	if result.Action == Modify {
+		result.Transaction = tx
+	}
+	return result, nil
+}
+
+// Record implements Recorder.
+func (c chain) Record(ctx context.Context, tx *types.PrivateTransaction) {
+	for _, p := range c {
+		if r, ok := p.(Recorder); ok {
+			r.Record(ctx, tx)
+		}
+	}
+}
+
+// Apply checks tx against p, returning the transaction to send or a
+// *DeniedError. Modifications of signed transactions are denied.
+func Apply(ctx context.Context, p Policy, tx *types.PrivateTransaction, signed bool) (*types.PrivateTransaction, error) {
+	d, err := p.Check(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	switch d.Action {
+	case Allow:
+		return tx, nil
+	case Modify:
+		if signed {
+			return nil, &DeniedError{Reason: fmt.Sprintf("signed transaction would be modified: %v", d.Reason)}
+		}
+		if d.Transaction == nil {
+			return nil, fmt.Errorf("modify decision without transaction")
+		}
+		return d.Transaction, nil
+	}
+	return nil, &DeniedError{Reason: d.Reason}
+}
+
+// MaxGas denies transactions with a gas limit above limit.
+func MaxGas(limit uint64) Policy {
+	return Func(func(ctx context.Context, tx *types.PrivateTransaction) (Decision, error) {
+		if tx.Gas() > limit {
+			return Decision{Action: Deny, Reason: fmt.Sprintf("gas %v exceeds %v", tx.Gas(), limit)}, nil
+		}
+		return Decision{Action: Allow}, nil
+	})
+}
+
+// AllowedRecipients denies transactions to other contracts, and contract
+// creations unless creations is true.
+func AllowedRecipients(creations bool, recipients ...common.Address) Policy {
+	allowed := make(map[common.Address]bool)
+	for _, r := range recipients {
+		allowed[r] = true
+	}
+	return Func(func(ctx context.Context, tx *types.PrivateTransaction) (Decision, error) {
+		to := tx.To()
+		if to == nil {
+			if !creations {
+				return Decision{Action: Deny, Reason: "contract creation not allowed"}, nil
+			}
+		} else if !allowed[*to] {
+			return Decision{Action: Deny, Reason: fmt.Sprintf("recipient %v not allowed", to.Hex())}, nil
+		}
+		return Decision{Action: Allow}, nil
+	})
+}
+
+// AllowedGroups denies transactions not addressed to one of the privacy
+// groups by ID. Transactions addressed by privateFor are denied.
+func AllowedGroups(groupIDs ...string) Policy {
+	allowed := make(map[string]bool)
+	for _, id := range groupIDs {
+		allowed[id] = true
+	}
+	return Func(func(ctx context.Context, tx *types.PrivateTransaction) (Decision, error) {
+		id := tx.PrivacyGroupID()
+		if id == nil {
+			return Decision{Action: Deny, Reason: "transaction not addressed to a privacy group"}, nil
+		}
+		if groupID := base64.StdEncoding.EncodeToString(id); !allowed[groupID] {
+			return Decision{Action: Deny, Reason: fmt.Sprintf("privacy group %v not allowed", groupID)}, nil
+		}
+		return Decision{Action: Allow}, nil
+	})
+}
+
+// Window allows transactions on Days between Start and End, offsets from
+// midnight in Location, e.g. business hours.
+type Window struct {
+	Location *time.Location // time.Local if nil
+	Days     []time.Weekday // every day if empty
+	Start    time.Duration
+	End      time.Duration
+	Clock    clock.Clock // clock.Real if nil
+}
+
+// Check implements Policy.
+func (w *Window) Check(ctx context.Context, tx *types.PrivateTransaction) (Decision, error) {
+	loc := w.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	now := clock.Or(w.Clock).Now().In(loc)
+	if len(w.Days) > 0 {
+		allowed := false
+		for _, d := range w.Days {
+			allowed = allowed || d == now.Weekday()
+		}
+		if !allowed {
+			return Decision{Action: Deny, Reason: fmt.Sprintf("not allowed on %v", now.Weekday())}, nil
+		}
+	}
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if offset := now.Sub(midnight); offset < w.Start || offset >= w.End {
+		return Decision{Action: Deny, Reason: fmt.Sprintf("not allowed at %v", now.Format("15:04 MST"))}, nil
+	}
+	return Decision{Action: Allow}, nil
+}
+
+// BusinessHours allows transactions from 9:00 to 17:00, Monday to Friday, in loc.
+func BusinessHours(loc *time.Location) *Window {
+	return &Window{
+		Location: loc,
+		Days:     []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		Start:    9 * time.Hour,
+		End:      17 * time.Hour,
+	}
+}
@@ -0,0 +1,208 @@
+package policy
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bsostech/go-besu/clock"
+	"github.com/bsostech/go-besu/privacy"
+	"github.com/bsostech/go-besu/types"
+)
+
+// Quota scopes.
+const (
+	ScopeAccount = "account"
+	ScopeGroup   = "group"
+)
+
+// QuotaExceededError is returned by the Check of a Quota denying a transaction.
+type QuotaExceededError struct {
+	Scope string // ScopeAccount or ScopeGroup
+	Key   string // hex address or base64 privacy group ID
+	Used  uint64 // gas used within the window, without the transaction
+	Gas   uint64 // gas of the transaction
+	Limit uint64
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("%v: gas quota of %v %v exceeded, used %v + %v of %v", ErrDenied, e.Scope, e.Key, e.Used, e.Gas, e.Limit)
+}
+
+// Is reports whether target is ErrDenied.
+func (e *QuotaExceededError) Is(target error) bool {
+	return target == ErrDenied
+}
+
+// QuotaMetrics observes the usage of quotas.
+type QuotaMetrics interface {
+	ObserveGas(scope, key string, used, limit uint64)
+	QuotaExceeded(scope, key string)
+}
+
+// Quota is a Policy limiting the gas of the transactions of each sender and
+// each privacy group within a rolling window. The gas limit of transactions
+// is counted, as the gas used is only known once mined. Signed transactions
+// are checked against the gas counted so far, and counted by Record once
+// sent, so denied and failed sends use no quota; unsigned transactions are
+// only checked against the group limits. Transactions sent with privateFor
+// count against their root privacy group. Limits of 0 mean no limit.
+type Quota struct {
+	Window       time.Duration
+	AccountLimit uint64
+	GroupLimit   uint64
+	Limits       map[string]uint64 // limits by hex address or base64 group ID, overriding the defaults
+	Metrics      QuotaMetrics
+	Clock        clock.Clock // clock.Real if nil
+	// GroupIDs derives the root privacy group of privateFor transactions,
+	// privacy.LegacyGroupID if nil, as for privacy.Privacy.
+	GroupIDs privacy.GroupIDStrategy
+
+	mu    sync.Mutex
+	usage map[string][]gasEntry
+}
+
+type gasEntry struct {
+	time time.Time
+	gas  uint64
+}
+
+type quotaKey struct{ scope, key string }
+
+// NewQuota returns a quota of accountLimit gas per sender and groupLimit gas
+// per privacy group within window.
+func NewQuota(window time.Duration, accountLimit, groupLimit uint64) *Quota {
+	return &Quota{
+		Window:       window,
+		AccountLimit: accountLimit,
+		GroupLimit:   groupLimit,
+	}
+}
+
+// Check implements Policy. Concurrent sends checked before either is
+// recorded may together exceed a limit.
+func (q *Quota) Check(ctx context.Context, tx *types.PrivateTransaction) (Decision, error) {
+	now := clock.Or(q.Clock).Now()
+	keys := q.keys(tx)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, k := range keys {
+		limit := q.limit(k.scope, k.key)
+		if limit == 0 {
+			continue
+		}
+		used := q.used(k.scope+":"+k.key, now)
+		if used+tx.Gas() > limit {
+			if q.Metrics != nil {
+				q.Metrics.QuotaExceeded(k.scope, k.key)
+			}
+			return Decision{}, &QuotaExceededError{Scope: k.scope, Key: k.key, Used: used, Gas: tx.Gas(), Limit: limit}
+		}
+	}
+	return Decision{Action: Allow}, nil
+}
+
+// Record implements Recorder, counting the gas of a sent signed transaction.
+func (q *Quota) Record(ctx context.Context, tx *types.PrivateTransaction) {
+	if !signed(tx) {
+		return
+	}
+	now := clock.Or(q.Clock).Now()
+	keys := q.keys(tx)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, k := range keys {
+		id := k.scope + ":" + k.key
+		if q.usage == nil {
+			q.usage = make(map[string][]gasEntry)
+		}
+		q.usage[id] = append(q.usage[id], gasEntry{time: now, gas: tx.Gas()})
+		if q.Metrics != nil {
+			q.Metrics.ObserveGas(k.scope, k.key, q.used(id, now), q.limit(k.scope, k.key))
+		}
+	}
+}
+
+// keys returns the sender of tx if signed, and its privacy group.
+func (q *Quota) keys(tx *types.PrivateTransaction) []quotaKey {
+	var keys []quotaKey
+	if signed(tx) {
+		if sender, err := tx.Sender(); err == nil {
+			keys = append(keys, quotaKey{ScopeAccount, sender.Hex()})
+		}
+	}
+	if id := q.groupID(tx); id != "" {
+		keys = append(keys, quotaKey{ScopeGroup, id})
+	}
+	return keys
+}
+
+// groupID returns the privacy group ID of tx, derived from its participants
+// for transactions sent with privateFor, empty if it can not be derived.
+func (q *Quota) groupID(tx *types.PrivateTransaction) string {
+	if id := tx.PrivacyGroupID(); id != nil {
+		return base64.StdEncoding.EncodeToString(id)
+	}
+	if len(tx.PrivateFrom()) == 0 {
+		return ""
+	}
+	privateFrom := privacy.PublicKey(tx.PrivateFrom())
+	participants := []*privacy.PublicKey{&privateFrom}
+	for _, v := range tx.PrivateFor() {
+		key := privacy.PublicKey(v)
+		participants = append(participants, &key)
+	}
+	strategy := q.GroupIDs
+	if strategy == nil {
+		strategy = privacy.LegacyGroupID
+	}
+	id, err := strategy.GroupID(privacy.NewParticipantSet(participants...))
+	if err != nil {
+		return ""
+	}
+	return id
+}
+
+func signed(tx *types.PrivateTransaction) bool {
+	v, _, _ := tx.RawSignatureValues()
+	return v != nil && v.Sign() > 0
+}
+
+// Usage returns the gas counted within the window for a hex address or base64 group ID.
+func (q *Quota) Usage(scope, key string) uint64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.used(scope+":"+key, clock.Or(q.Clock).Now())
+}
+
+func (q *Quota) limit(scope, key string) uint64 {
+	if limit, ok := q.Limits[key]; ok {
+		return limit
+	}
+	if scope == ScopeAccount {
+		return q.AccountLimit
+	}
+	return q.GroupLimit
+}
+
+// used drops the entries of id older than the window and sums the others.
+func (q *Quota) used(id string, now time.Time) uint64 {
+	entries := q.usage[id]
+	i := 0
+	for i < len(entries) && now.Sub(entries[i].time) >= q.Window {
+		i++
+	}
+	entries = entries[i:]
+	if len(entries) == 0 {
+		delete(q.usage, id)
+	} else {
+		q.usage[id] = entries
+	}
+	var sum uint64
+	for _, e := range entries {
+		sum += e.gas
+	}
+	return sum
+}
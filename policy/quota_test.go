@@ -0,0 +1,119 @@
+package policy
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bsostech/go-besu/clock"
+	"github.com/bsostech/go-besu/privacy"
+	"github.com/bsostech/go-besu/types"
+)
+
+var (
+	testKey, _  = crypto.HexToECDSA("8f2a55949038a9610f50fb23b5883af3b4ecb3c3bb792cbcefbd1542c692be63")
+	privateFrom = common.LeftPadBytes([]byte{1}, 32)
+	privateFor  = [][]byte{common.LeftPadBytes([]byte{2}, 32)}
+)
+
+func privateForTx(t *testing.T, nonce uint64, gas uint64) *types.PrivateTransaction {
+	tx := types.NewTransaction(nonce, &common.Address{1}, big.NewInt(0), gas, big.NewInt(0), nil, privateFrom, privateFor)
+	signed, err := tx.SignTx(big.NewInt(2018), testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func rootGroupID(t *testing.T) string {
+	from, to := privacy.PublicKey(privateFrom), privacy.PublicKey(privateFor[0])
+	id, err := privacy.LegacyGroupID.GroupID(privacy.NewParticipantSet(&from, &to))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func TestQuotaCountsRecordedSends(t *testing.T) {
+	clk := clock.NewFake(time.Unix(0, 0))
+	q := NewQuota(time.Hour, 0, 100000)
+	q.Clock = clk
+	ctx := context.Background()
+	tx := privateForTx(t, 0, 60000)
+	// checks do not count, only recorded sends do
+	for i := 0; i < 3; i++ {
+		if _, err := q.Check(ctx, tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	group := rootGroupID(t)
+	if used := q.Usage(ScopeGroup, group); used != 0 {
+		t.Fatalf("%v gas counted before sending", used)
+	}
+	q.Record(ctx, tx)
+	if used := q.Usage(ScopeGroup, group); used != 60000 {
+		t.Fatalf("%v gas counted for the root group of privateFor, want 60000", used)
+	}
+	_, err := q.Check(ctx, privateForTx(t, 1, 60000))
+	var exceeded *QuotaExceededError
+	if !errors.As(err, &exceeded) || !errors.Is(err, ErrDenied) || exceeded.Scope != ScopeGroup || exceeded.Key != group {
+		t.Fatalf("got %v, want group quota exceeded", err)
+	}
+	clk.Advance(time.Hour)
+	if _, err := q.Check(ctx, privateForTx(t, 1, 60000)); err != nil {
+		t.Fatalf("quota not renewed after the window, %v", err)
+	}
+}
+
+func TestQuotaAccountAndGroupIDs(t *testing.T) {
+	q := NewQuota(time.Hour, 50000, 0)
+	q.GroupIDs = privacy.SaltedGroupID([]byte("salt"))
+	ctx := context.Background()
+	tx := privateForTx(t, 0, 40000)
+	q.Record(ctx, tx)
+	sender := crypto.PubkeyToAddress(testKey.PublicKey).Hex()
+	if used := q.Usage(ScopeAccount, sender); used != 40000 {
+		t.Fatalf("%v gas counted for the sender", used)
+	}
+	from, to := privacy.PublicKey(privateFrom), privacy.PublicKey(privateFor[0])
+	salted, _ := q.GroupIDs.GroupID(privacy.NewParticipantSet(&from, &to))
+	if used := q.Usage(ScopeGroup, salted); used != 40000 {
+		t.Fatalf("%v gas counted for the group of the strategy", used)
+	}
+	if _, err := q.Check(ctx, privateForTx(t, 1, 20000)); !errors.Is(err, ErrDenied) {
+		t.Fatalf("got %v, want account quota exceeded", err)
+	}
+	// unsigned transactions are not counted
+	unsigned := types.NewPrivateTransaction(0, &common.Address{1}, big.NewInt(0), 40000, big.NewInt(0), nil, privateFrom, make([]byte, 32))
+	q.Record(ctx, unsigned)
+	if used := q.Usage(ScopeGroup, base64.StdEncoding.EncodeToString(make([]byte, 32))); used != 0 {
+		t.Fatalf("%v gas counted for an unsigned transaction", used)
+	}
+}
+
+func TestChainRecords(t *testing.T) {
+	q := NewQuota(time.Hour, 0, 100000)
+	p := Chain(q, MaxGas(50000))
+	ctx := context.Background()
+	tx := privateForTx(t, 0, 60000)
+	if _, err := Apply(ctx, p, tx, true); !errors.Is(err, ErrDenied) {
+		t.Fatalf("got %v, want denied by MaxGas", err)
+	}
+	if used := q.Usage(ScopeGroup, rootGroupID(t)); used != 0 {
+		t.Fatalf("%v gas counted for a denied transaction", used)
+	}
+	r, ok := p.(Recorder)
+	if !ok {
+		t.Fatal("chain is not a Recorder")
+	}
+	r.Record(ctx, tx)
+	if used := q.Usage(ScopeGroup, rootGroupID(t)); used != 60000 {
+		t.Fatalf("%v gas recorded through the chain", used)
+	}
+}
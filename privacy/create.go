@@ -0,0 +1,39 @@
+package privacy
+
+import "sync"
+
+// CreateIfNotExists returns the privacy group of exactly members, creating it
+// with name and description if none exists. Concurrent calls for the same
+// members through the same Privacy create at most one group, as duplicate
+// groups of the same participants confuse nonce tracking. created reports
+// whether the group was created by this call.
+func (p *Privacy) CreateIfNotExists(members []*PublicKey, name, description string) (group *Group, created bool, err error) {
+	lock := p.creationLock(members)
+	lock.Lock()
+	defer lock.Unlock()
+	group, err = p.FindPrivacyGroup(members)
+	if err != nil {
+		return nil, false, err
+	}
+	if group != nil {
+		return group, false, nil
+	}
+	group, err = p.CreatePrivacyGroupWithOptions(members, name, GroupOptions{Description: description})
+	if err != nil {
+		return nil, false, err
+	}
+	p.groupCache().Add(groupCacheKey(groupKey(members)), group)
+	return group, true, nil
+}
+
+func (p *Privacy) creationLock(members []*PublicKey) *sync.Mutex {
+	key := groupKey(members)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	lock, ok := p.creating[key]
+	if !ok {
+		lock = new(sync.Mutex)
+		p.creating[key] = lock
+	}
+	return lock
+}
@@ -0,0 +1,31 @@
+package privacy
+
+import (
+	"github.com/bsostech/go-besu/internal/decode"
+)
+
+// DecodeMode controls how RPC responses with unexpected content are decoded.
+type DecodeMode = decode.Mode
+
+const (
+	// Lenient skips malformed list entries, e.g. group members or logs, and
+	// ignores unknown fields. Use it against nodes newer than this library.
+	Lenient = decode.Lenient
+	// Strict fails on malformed list entries, unknown and missing fields.
+	// Use it in CI and tests.
+	Strict = decode.Strict
+)
+
+// SetDecodeMode sets the decode mode of responses, Lenient by default.
+func (p *Privacy) SetDecodeMode(mode DecodeMode) {
+	p.mu.Lock()
+	p.decodeMode = mode
+	p.mu.Unlock()
+}
+
+// DecodeMode returns the decode mode of responses.
+func (p *Privacy) DecodeMode() DecodeMode {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.decodeMode
+}
@@ -0,0 +1,40 @@
+package privacy
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/bsostech/go-besu/types"
+)
+
+// Format writes g to w with verbosity v. Groups have no payloads, so Full
+// writes the same as Detailed.
+func (g *Group) Format(w io.Writer, v types.Verbosity) error {
+	if v == types.Summary {
+		_, err := fmt.Fprintf(w, "Group{id: %v, name: %q, type: %v, members: %v}", g.ID, g.Name, g.Type, len(g.Members))
+		return err
+	}
+	lines := []string{
+		"Group",
+		fmt.Sprintf("  %-18s %v", "id:", g.ID),
+		fmt.Sprintf("  %-18s %q", "name:", g.Name),
+		fmt.Sprintf("  %-18s %q", "description:", g.Description),
+		fmt.Sprintf("  %-18s %v", "type:", g.Type),
+		fmt.Sprintf("  %-18s %v", "members:", len(g.Members)),
+	}
+	for _, m := range g.Members {
+		if m != nil {
+			lines = append(lines, "    "+m.ToString())
+		}
+	}
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *Group) String() string {
+	return types.FormatString(g.Format)
+}
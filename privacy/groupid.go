@@ -0,0 +1,86 @@
+package privacy
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/bsostech/go-besu/internal/encoding"
+)
+
+// GroupIDStrategy derives the ID of the root privacy group of participants,
+// the group Besu sends transactions with privateFor to. Networks which
+// derive IDs differently, e.g. with a salt, or use random IDs, set their own
+// strategy with SetGroupIDStrategy.
+type GroupIDStrategy interface {
+	GroupID(participants ParticipantSet) (string, error)
+}
+
+// GroupIDFunc adapts a function to GroupIDStrategy.
+type GroupIDFunc func(participants ParticipantSet) (string, error)
+
+// GroupID implements GroupIDStrategy.
+func (f GroupIDFunc) GroupID(participants ParticipantSet) (string, error) {
+	return f(participants)
+}
+
+// LegacyGroupID is the derivation of Besu: the RLP hash of the participants
+// sorted by their Java hash code, base64 encoded. It is the default.
+var LegacyGroupID GroupIDStrategy = GroupIDFunc(func(participants ParticipantSet) (string, error) {
+	hash := encoding.RLPHash(sortByHash(participants.Keys()))
+	return base64.StdEncoding.EncodeToString(hash.Bytes()), nil
+})
+
+// SaltedGroupID returns the strategy hashing salt along with the participants
+// ordered like LegacyGroupID.
+func SaltedGroupID(salt []byte) GroupIDStrategy {
+	return GroupIDFunc(func(participants ParticipantSet) (string, error) {
+		hash := encoding.RLPHash([]interface{}{salt, sortByHash(participants.Keys())})
+		return base64.StdEncoding.EncodeToString(hash.Bytes()), nil
+	})
+}
+
+// LookupGroupID returns the strategy of networks with random group IDs,
+// which looks up the group of the participants with priv_findPrivacyGroup.
+// It fails if the group does not exist.
+func LookupGroupID(p *Privacy) GroupIDStrategy {
+	return GroupIDFunc(func(participants ParticipantSet) (string, error) {
+		group, err := p.FindPrivacyGroup(participants.Keys())
+		if err != nil {
+			return "", err
+		}
+		if group == nil {
+			return "", fmt.Errorf("privacy group of %v not found", participants)
+		}
+		return group.ID, nil
+	})
+}
+
+// SetGroupIDStrategy sets the strategy root privacy group IDs are derived
+// with, LegacyGroupID if nil.
+func (p *Privacy) SetGroupIDStrategy(s GroupIDStrategy) {
+	p.mu.Lock()
+	p.groupIDs = s
+	p.mu.Unlock()
+}
+
+// GroupIDStrategy returns the strategy root privacy group IDs are derived with.
+func (p *Privacy) GroupIDStrategy() GroupIDStrategy {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.groupIDs == nil {
+		return LegacyGroupID
+	}
+	return p.groupIDs
+}
+
+// RootPrivacyGroup returns the root privacy group of participants, whose ID
+// is derived with the group ID strategy of p.
+func (p *Privacy) RootPrivacyGroup(participants []*PublicKey) (*Group, error) {
+	id, err := p.GroupIDStrategy().GroupID(NewParticipantSet(participants...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive privacy group id, err: %v", err)
+	}
+	return &Group{
+		ID: id,
+	}, nil
+}
@@ -0,0 +1,141 @@
+// Package keys defines the enclave public keys and participant sets of
+// private transactions. It has no RPC dependencies, so that packages which
+// only encode or sign private transactions, such as types, stay light; the
+// privacy package re-exports its names.
+package keys
+
+import (
+	"encoding/base64"
+	"sort"
+	"strings"
+)
+
+// PublicKey is an enclave public key.
+type PublicKey []byte
+
+// ToPublicKey .
+func ToPublicKey(key string) (PublicKey, error) {
+	return base64.StdEncoding.DecodeString(key)
+}
+
+// ToString .
+func (pub PublicKey) ToString() string {
+	return base64.StdEncoding.EncodeToString(pub)
+}
+
+// Hash .
+func (pub PublicKey) Hash() int {
+	result := int(1)
+	for _, v := range pub {
+		result = int(int32((31*result + int((int32(v)<<24)>>24)) & 0xffffffff))
+	}
+	return result
+}
+
+// ParticipantSet is an immutable set of public keys, deduplicated and sorted
+// by their base64 encoding. The zero value is the empty set.
+type ParticipantSet struct {
+	keys []string // base64, sorted
+}
+
+// NewParticipantSet returns the set of keys, skipping nil and empty keys.
+func NewParticipantSet(keys ...*PublicKey) ParticipantSet {
+	encoded := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if k != nil && len(*k) > 0 {
+			encoded = append(encoded, k.ToString())
+		}
+	}
+	return newParticipantSet(encoded)
+}
+
+// ParseParticipantSet returns the set of base64 encoded keys.
+func ParseParticipantSet(keys ...string) (ParticipantSet, error) {
+	encoded := make([]string, 0, len(keys))
+	for _, k := range keys {
+		key, err := ToPublicKey(k)
+		if err != nil {
+			return ParticipantSet{}, err
+		}
+		if len(key) > 0 {
+			encoded = append(encoded, key.ToString())
+		}
+	}
+	return newParticipantSet(encoded), nil
+}
+
+func newParticipantSet(encoded []string) ParticipantSet {
+	sort.Strings(encoded)
+	keys := encoded[:0]
+	for i, k := range encoded {
+		if i == 0 || k != encoded[i-1] {
+			keys = append(keys, k)
+		}
+	}
+	return ParticipantSet{keys: keys}
+}
+
+// Len .
+func (s ParticipantSet) Len() int {
+	return len(s.keys)
+}
+
+// Contains .
+func (s ParticipantSet) Contains(key PublicKey) bool {
+	k := key.ToString()
+	i := sort.SearchStrings(s.keys, k)
+	return i < len(s.keys) && s.keys[i] == k
+}
+
+// Equal .
+func (s ParticipantSet) Equal(other ParticipantSet) bool {
+	if len(s.keys) != len(other.keys) {
+		return false
+	}
+	for i := range s.keys {
+		if s.keys[i] != other.keys[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Union returns the keys in s or other.
+func (s ParticipantSet) Union(other ParticipantSet) ParticipantSet {
+	keys := make([]string, 0, len(s.keys)+len(other.keys))
+	keys = append(keys, s.keys...)
+	keys = append(keys, other.keys...)
+	return newParticipantSet(keys)
+}
+
+// Intersect returns the keys in both s and other.
+func (s ParticipantSet) Intersect(other ParticipantSet) ParticipantSet {
+	var keys []string
+	for _, k := range s.keys {
+		i := sort.SearchStrings(other.keys, k)
+		if i < len(other.keys) && other.keys[i] == k {
+			keys = append(keys, k)
+		}
+	}
+	return ParticipantSet{keys: keys}
+}
+
+// Keys returns the keys in canonical order.
+func (s ParticipantSet) Keys() []*PublicKey {
+	keys := make([]*PublicKey, len(s.keys))
+	for i, k := range s.keys {
+		key, _ := ToPublicKey(k)
+		keys[i] = &key
+	}
+	return keys
+}
+
+// Strings returns the base64 encoded keys in canonical order.
+func (s ParticipantSet) Strings() []string {
+	return append([]string(nil), s.keys...)
+}
+
+// String returns the keys joined by commas, identifying the set.
+func (s ParticipantSet) String() string {
+	return strings.Join(s.keys, ",")
+}
@@ -0,0 +1,72 @@
+package privacy
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type nonceKey struct {
+	account common.Address
+	groupID string
+}
+
+type nonceEntry struct {
+	mu     sync.Mutex
+	nonce  uint64
+	loaded bool
+}
+
+// NextNonce reserves and returns the next private nonce of account in
+// privacyGroup, so concurrent senders never get the same nonce. The first call
+// for an account and group fetches the nonce from the node.
+func (p *Privacy) NextNonce(account common.Address, privacyGroup *Group) (uint64, error) {
+	entry := p.nonceEntry(account, privacyGroup.ID)
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if !entry.loaded {
+		nonce, err := p.PrivateNonce(account, privacyGroup)
+		if err != nil {
+			return 0, err
+		}
+		entry.nonce, entry.loaded = nonce, true
+	}
+	nonce := entry.nonce
+	entry.nonce++
+	return nonce, nil
+}
+
+// ResetNonce drops the reserved nonces of account in privacyGroup, e.g. after a
+// failed send, so the next NextNonce fetches it from the node again.
+func (p *Privacy) ResetNonce(account common.Address, privacyGroup *Group) {
+	entry := p.nonceEntry(account, privacyGroup.ID)
+	entry.mu.Lock()
+	entry.loaded = false
+	entry.mu.Unlock()
+}
+
+func (p *Privacy) nonceEntry(account common.Address, groupID string) *nonceEntry {
+	key := nonceKey{account: account, groupID: groupID}
+	p.mu.RLock()
+	entry, ok := p.nonces[key]
+	p.mu.RUnlock()
+	if ok {
+		return entry
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if entry, ok = p.nonces[key]; !ok {
+		entry = &nonceEntry{}
+		p.nonces[key] = entry
+	}
+	return entry
+}
+
+// ReservedNonce returns the next nonce NextNonce would reserve for account in
+// privacyGroup, ok is false if none has been loaded from the node.
+func (p *Privacy) ReservedNonce(account common.Address, privacyGroup *Group) (nonce uint64, ok bool) {
+	entry := p.nonceEntry(account, privacyGroup.ID)
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	return entry.nonce, entry.loaded
+}
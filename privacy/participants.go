@@ -0,0 +1,23 @@
+package privacy
+
+import (
+	"github.com/bsostech/go-besu/privacy/keys"
+)
+
+// ParticipantSet is an immutable set of public keys, see keys.ParticipantSet.
+type ParticipantSet = keys.ParticipantSet
+
+// NewParticipantSet returns the set of keys, skipping nil and empty keys.
+func NewParticipantSet(k ...*PublicKey) ParticipantSet {
+	return keys.NewParticipantSet(k...)
+}
+
+// ParseParticipantSet returns the set of base64 encoded keys.
+func ParseParticipantSet(k ...string) (ParticipantSet, error) {
+	return keys.ParseParticipantSet(k...)
+}
+
+// Participants returns the members of the group as a set.
+func (g *Group) Participants() ParticipantSet {
+	return NewParticipantSet(g.Members...)
+}
@@ -2,19 +2,44 @@ package privacy
 
 import (
 	"context"
-	"encoding/base64"
+	"errors"
+	"fmt"
 	"sort"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/common/hexutil"
-	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/rpc"
-	"golang.org/x/crypto/sha3"
+
+	"github.com/bsostech/go-besu/cache"
+	"github.com/bsostech/go-besu/internal/decode"
+	"github.com/bsostech/go-besu/privacy/keys"
+)
+
+// Privacy group types returned by Besu.
+const (
+	GroupTypeLegacy   = "LEGACY"
+	GroupTypePantheon = "PANTHEON"
+	GroupTypeOnchain  = "ONCHAIN"
 )
 
-// Privacy .
+// DefaultGroupCacheSize is the number of privacy groups cached by default.
+const DefaultGroupCacheSize = 1024
+
+// ErrGroupUpdateUnsupported .
+var ErrGroupUpdateUnsupported = errors.New("privacy group metadata can not be updated")
+
+// Privacy is safe for concurrent use. Privacy group lookups and reserved
+// nonces are cached and shared by all goroutines using the same Privacy.
 type Privacy struct {
 	client *rpc.Client
+
+	mu         sync.RWMutex
+	groups     cache.Cache
+	nonces     map[nonceKey]*nonceEntry
+	creating   map[string]*sync.Mutex
+	decodeMode DecodeMode
+	version    string
+	groupIDs   GroupIDStrategy
 }
 
 // Group .
@@ -26,39 +51,49 @@ type Group struct {
 	Members     []*PublicKey
 }
 
-// PublicKey .
-type PublicKey []byte
+// PublicKey is an enclave public key, see keys.PublicKey.
+type PublicKey = keys.PublicKey
 
 // NewPrivacy .
 func NewPrivacy(c *rpc.Client) *Privacy {
 	return &Privacy{
-		client: c,
+		client:   c,
+		groups:   cache.NewLRU(DefaultGroupCacheSize),
+		nonces:   make(map[nonceKey]*nonceEntry),
+		creating: make(map[string]*sync.Mutex),
 	}
 }
 
 // PrivateNonceByParticipants .
 func (p *Privacy) PrivateNonceByParticipants(account common.Address, participants []*PublicKey) (uint64, error) {
-	rootGroup := p.FindRootPrivacyGroup(participants)
+	rootGroup, err := p.RootPrivacyGroup(participants)
+	if err != nil {
+		return 0, err
+	}
 	return p.PrivateNonce(account, rootGroup)
 }
 
-// FindRootPrivacyGroup .
+// FindRootPrivacyGroup returns the root privacy group of participants like
+// RootPrivacyGroup. Its ID is empty if the group ID strategy fails.
 func (p *Privacy) FindRootPrivacyGroup(participants []*PublicKey) *Group {
-	sortParticipants := p.sort(participants)
-	hash := rlpHash(sortParticipants)
-	return &Group{
-		ID: base64.StdEncoding.EncodeToString(hash.Bytes()),
+	group, err := p.RootPrivacyGroup(participants)
+	if err != nil {
+		return &Group{}
 	}
+	return group
 }
 
 // PrivateNonce .
 func (p *Privacy) PrivateNonce(account common.Address, privacyGroup *Group) (uint64, error) {
-	var getTransactionCountRsp interface{}
+	var getTransactionCountRsp *string
 	err := p.client.CallContext(context.TODO(), &getTransactionCountRsp, "priv_getTransactionCount", account.Hex(), privacyGroup.ID)
 	if err != nil {
 		return 0, err
 	}
-	nonce, err := hexutil.DecodeUint64(getTransactionCountRsp.(string))
+	if getTransactionCountRsp == nil {
+		return 0, fmt.Errorf("transaction count not found")
+	}
+	nonce, err := decode.Uint64(*getTransactionCountRsp)
 	if err != nil {
 		return 0, err
 	}
@@ -67,12 +102,14 @@ func (p *Privacy) PrivateNonce(account common.Address, privacyGroup *Group) (uin
 
 // FindPrivacyGroup .
 func (p *Privacy) FindPrivacyGroup(participants []*PublicKey) (*Group, error) {
-	publicKeysString := make([]string, len(participants))
-	for i := range participants {
-		publicKeysString[i] = participants[i].ToString()
+	set := NewParticipantSet(participants...)
+	key := set.String()
+	groups := p.groupCache()
+	if cached, ok := groups.Get(groupCacheKey(key)); ok {
+		return cached.(*Group), nil
 	}
 	var findPrivacyGroupRsp []map[string]interface{}
-	err := p.client.CallContext(context.TODO(), &findPrivacyGroupRsp, "priv_findPrivacyGroup", participants)
+	err := p.client.CallContext(context.TODO(), &findPrivacyGroupRsp, "priv_findPrivacyGroup", set.Strings())
 	if err != nil {
 		return nil, err
 	}
@@ -80,70 +117,161 @@ func (p *Privacy) FindPrivacyGroup(participants []*PublicKey) (*Group, error) {
 	if len(findPrivacyGroupRsp) == 0 {
 		return nil, nil
 	}
-	ms := findPrivacyGroupRsp[0]["members"].([]interface{})
+	mode := p.DecodeMode()
+	if err := mode.CheckFields(findPrivacyGroupRsp[0], "privacyGroupId", "name", "description", "type", "members"); err != nil {
+		return nil, err
+	}
+	rsp := findPrivacyGroupRsp[0]
+	ms, ok, err := decode.Array(rsp, "members")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("members not found")
+	}
 	var members []*PublicKey
-	for _, v := range ms {
-		m, err := ToPublicKey(v.(string))
+	for i := range ms {
+		v, err := decode.StringElement("members", ms, i)
+		if err != nil {
+			return nil, err
+		}
+		m, err := ToPublicKey(v)
 		if err != nil {
+			if err := mode.Skip(fmt.Errorf("invalid member %v: %v", v, err)); err != nil {
+				return nil, err
+			}
 			continue
 		}
 		members = append(members, &m)
 	}
-	privacyGroup.ID = findPrivacyGroupRsp[0]["privacyGroupId"].(string)
-	privacyGroup.Name = findPrivacyGroupRsp[0]["name"].(string)
-	privacyGroup.Description = findPrivacyGroupRsp[0]["description"].(string)
-	privacyGroup.Type = findPrivacyGroupRsp[0]["type"].(string)
+	if privacyGroup.ID, err = decode.RequiredString(rsp, "privacyGroupId"); err != nil {
+		return nil, err
+	}
+	// name, description and type may be null
+	for key, field := range map[string]*string{"name": &privacyGroup.Name, "description": &privacyGroup.Description, "type": &privacyGroup.Type} {
+		if *field, _, err = decode.String(rsp, key); err != nil {
+			return nil, err
+		}
+	}
 	privacyGroup.Members = members
+	groups.Add(groupCacheKey(key), &privacyGroup)
 	return &privacyGroup, nil
 }
 
+// GroupOptions are the optional settings of a new privacy group.
+type GroupOptions struct {
+	Description string
+}
+
 // CreatePrivacyGroup .
 func (p *Privacy) CreatePrivacyGroup(members []*PublicKey, name string) (*Group, error) {
-	args := getCreatePrivacyGroupArgs(members, name)
-	var createPrivacyGroupRsp interface{}
-	err := p.client.CallContext(context.TODO(), &createPrivacyGroupRsp, "priv_createPrivacyGroup", args)
+	return p.CreatePrivacyGroupWithOptions(members, name, GroupOptions{})
+}
+
+// CreatePrivacyGroupWithOptions creates a privacy group like
+// CreatePrivacyGroup, with the settings of opts.
+func (p *Privacy) CreatePrivacyGroupWithOptions(members []*PublicKey, name string, opts GroupOptions) (*Group, error) {
+	args := p.createPrivacyGroupArgs(members, name, opts.Description)
+	var createPrivacyGroupRsp string
+	err := p.client.CallContext(context.TODO(), &createPrivacyGroupRsp, "priv_createPrivacyGroup", args...)
+	if err != nil {
+		return nil, err
+	}
+	if createPrivacyGroupRsp == "" {
+		return nil, fmt.Errorf("privacyGroupId not found")
+	}
+	p.forgetGroup(members)
+	return &Group{
+		ID:          createPrivacyGroupRsp,
+		Name:        name,
+		Description: opts.Description,
+		Type:        GroupTypePantheon,
+		Members:     members,
+	}, nil
+}
+
+// UpdatePrivacyGroup updates name and description of a privacy group.
+// Besu can not change the metadata of an existing group, so a group created by
+// priv_createPrivacyGroup is recreated with the same members: the returned group
+// has a new ID, while the old group, its private state and nonces are left as
+// they are. Other group types return ErrGroupUpdateUnsupported.
+func (p *Privacy) UpdatePrivacyGroup(group *Group, name, description string) (*Group, error) {
+	if group.Type != GroupTypePantheon {
+		return nil, ErrGroupUpdateUnsupported
+	}
+	args := p.createPrivacyGroupArgs(group.Members, name, description)
+	var createPrivacyGroupRsp string
+	err := p.client.CallContext(context.TODO(), &createPrivacyGroupRsp, "priv_createPrivacyGroup", args...)
 	if err != nil {
 		return nil, err
 	}
+	if createPrivacyGroupRsp == "" {
+		return nil, fmt.Errorf("privacyGroupId not found")
+	}
+	p.forgetGroup(group.Members)
 	return &Group{
-		ID:      createPrivacyGroupRsp.(string),
-		Name:    name,
-		Members: members,
+		ID:          createPrivacyGroupRsp,
+		Name:        name,
+		Description: description,
+		Type:        GroupTypePantheon,
+		Members:     group.Members,
 	}, nil
 }
 
 // ToPublicKey .
 func ToPublicKey(key string) (PublicKey, error) {
-	return base64.StdEncoding.DecodeString(key)
+	return keys.ToPublicKey(key)
 }
 
-// ToString .
-func (pub PublicKey) ToString() string {
-	return base64.StdEncoding.EncodeToString(pub)
+// forgetGroup drops the cached group of members, as a new one has been created.
+func (p *Privacy) forgetGroup(members []*PublicKey) {
+	p.groupCache().Remove(groupCacheKey(groupKey(members)))
 }
 
-// Hash .
-func (pub PublicKey) Hash() int {
-	result := int(1)
-	for _, v := range pub {
-		result = int(int32((31*result + int((int32(v)<<24)>>24)) & 0xffffffff))
-	}
-	return result
+// SetCache sets the cache privacy groups are kept in, an LRU of
+// DefaultGroupCacheSize groups by default. It may be shared with other
+// caches of the client, keys being prefixed by kind.
+func (p *Privacy) SetCache(c cache.Cache) {
+	p.mu.Lock()
+	p.groups = c
+	p.mu.Unlock()
+}
+
+func (p *Privacy) groupCache() cache.Cache {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.groups
+}
+
+func groupCacheKey(key string) string {
+	return "group:" + key
+}
+
+// groupKey identifies a set of participants regardless of their order and duplicates.
+func groupKey(participants []*PublicKey) string {
+	return NewParticipantSet(participants...).String()
 }
 
-func getCreatePrivacyGroupArgs(publicKeys []*PublicKey, name string) map[string]interface{} {
-	publicKeysString := make([]string, len(publicKeys))
-	for i := range publicKeys {
-		publicKeysString[i] = publicKeys[i].ToString()
+// createPrivacyGroupArgs shapes the priv_createPrivacyGroup params for the
+// connected node: Pantheon takes positional params, Besu a single object.
+func (p *Privacy) createPrivacyGroupArgs(publicKeys []*PublicKey, name, description string) []interface{} {
+	args := getCreatePrivacyGroupArgs(publicKeys, name, description)
+	if isPantheon(p.clientVersion()) {
+		return []interface{}{args["addresses"], name, description}
 	}
+	return []interface{}{args}
+}
+
+func getCreatePrivacyGroupArgs(publicKeys []*PublicKey, name, description string) map[string]interface{} {
 	result := make(map[string]interface{})
-	result["addresses"] = publicKeysString
+	result["addresses"] = NewParticipantSet(publicKeys...).Strings()
 	result["name"] = name
+	result["description"] = description
 	return result
 }
 
 // hack from web3js-eea src/privacyGroup.js
-func (p *Privacy) sort(participants []*PublicKey) []*PublicKey {
+func sortByHash(participants []*PublicKey) []*PublicKey {
 	hashMap := make(map[int]*PublicKey)
 	for i := range participants {
 		hashMap[participants[i].Hash()] = participants[i]
@@ -159,13 +287,3 @@ func (p *Privacy) sort(participants []*PublicKey) []*PublicKey {
 	}
 	return output
 }
-
-func rlpHash(x interface{}) (h common.Hash) {
-	hw := sha3.NewLegacyKeccak256()
-	err := rlp.Encode(hw, x)
-	if err != nil {
-		return common.Hash{}
-	}
-	hw.Sum(h[:0])
-	return h
-}
@@ -0,0 +1,143 @@
+package privacy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/bsostech/go-besu/cache"
+)
+
+// resultServer answers every JSON-RPC call with result, raw JSON.
+func resultServer(t *testing.T, result string) *Privacy {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":%s}`, result)
+	}))
+	t.Cleanup(srv.Close)
+	c, err := rpc.DialHTTP(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewPrivacy(c)
+}
+
+const testMember = "A1aVtMxLCUHmBVHXoZzzBgPbW/wj5axDpW9X8l91SGo="
+
+func TestFindPrivacyGroupMalformed(t *testing.T) {
+	tests := []struct {
+		name   string
+		result string
+		found  bool
+		err    string
+	}{
+		{name: "valid", result: `[{"privacyGroupId":"DyAOiF/ynpc+JXa2YAGB0bCitSlOMNm+ShmB/7M6C4w=","name":"g","description":"d","type":"PANTHEON","members":["` + testMember + `"]}]`, found: true},
+		{name: "metadata null", result: `[{"privacyGroupId":"DyAOiF/ynpc+JXa2YAGB0bCitSlOMNm+ShmB/7M6C4w=","name":null,"description":null,"type":null,"members":["` + testMember + `"]}]`, found: true},
+		{name: "result null", result: `null`},
+		{name: "no groups", result: `[]`},
+		{name: "result object", result: `{}`, err: "cannot unmarshal"},
+		{name: "group null", result: `[null]`, err: "not found"},
+		{name: "members null", result: `[{"privacyGroupId":"x","members":null}]`, err: "members not found"},
+		{name: "members string", result: `[{"privacyGroupId":"x","members":"` + testMember + `"}]`, err: "expected array"},
+		{name: "member number", result: `[{"privacyGroupId":"x","members":[1]}]`, err: "expected string"},
+		{name: "privacyGroupId null", result: `[{"privacyGroupId":null,"members":[]}]`, err: "privacyGroupId not found"},
+		{name: "privacyGroupId number", result: `[{"privacyGroupId":1,"members":[]}]`, err: "expected string"},
+		{name: "name number", result: `[{"privacyGroupId":"x","name":1,"members":[]}]`, err: "expected string"},
+	}
+	member, err := ToPublicKey(testMember)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		p := resultServer(t, test.result)
+		group, err := p.FindPrivacyGroup([]*PublicKey{&member})
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%v: got error %v, want %q", test.name, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: %v", test.name, err)
+			continue
+		}
+		if (group != nil) != test.found {
+			t.Errorf("%v: got group %v, want found %v", test.name, group, test.found)
+		}
+	}
+}
+
+func TestSetCacheConcurrently(t *testing.T) {
+	p := resultServer(t, `[{"privacyGroupId":"DyAOiF/ynpc+JXa2YAGB0bCitSlOMNm+ShmB/7M6C4w=","members":["`+testMember+`"]}]`)
+	member, err := ToPublicKey(testMember)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			p.SetCache(cache.NewLRU(16))
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := p.FindPrivacyGroup([]*PublicKey{&member}); err != nil {
+				t.Error(err)
+			}
+			p.ExportState()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestCreatePrivacyGroup(t *testing.T) {
+	var params []map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string
+			Params []map[string]interface{}
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		if req.Method == "web3_clientVersion" {
+			fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":"besu/v1.4.4"}`)
+			return
+		}
+		params = req.Params
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":"DyAOiF/ynpc+JXa2YAGB0bCitSlOMNm+ShmB/7M6C4w="}`)
+	}))
+	t.Cleanup(srv.Close)
+	c, err := rpc.DialHTTP(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := NewPrivacy(c)
+	member, err := ToPublicKey(testMember)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, description := range []string{"", "settlement"} {
+		var group *Group
+		if description == "" {
+			group, err = p.CreatePrivacyGroup([]*PublicKey{&member}, "g")
+		} else {
+			group, err = p.CreatePrivacyGroupWithOptions([]*PublicKey{&member}, "g", GroupOptions{Description: description})
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if group.Name != "g" || group.Description != description {
+			t.Errorf("created %+v, want description %q", group, description)
+		}
+		if len(params) != 1 || params[0]["name"] != "g" || params[0]["description"] != description {
+			t.Errorf("sent params %v, want description %q", params, description)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package privacy
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/bsostech/go-besu/internal/decode"
+)
+
+// Self-only groups have the sending node as their sole member and keep
+// node-private records. Besu rejects an empty privateFor, so transactions to
+// a self-only group are sent with privateFor holding privateFrom only. Besu
+// derives the legacy group of such a transaction from privateFrom and
+// privateFor deduplicated, which is the root group of self alone.
+
+// SelfGroup returns the self-only group of self.
+func (p *Privacy) SelfGroup(self *PublicKey) *Group {
+	group := p.FindRootPrivacyGroup([]*PublicKey{self})
+	group.Members = []*PublicKey{self}
+	return group
+}
+
+// SelfPrivateFor returns the privateFor of transactions to the self-only group of self.
+func SelfPrivateFor(self PublicKey) [][]byte {
+	return [][]byte{common.CopyBytes(self)}
+}
+
+// IsSelfOnly reports whether privateFrom and privateFor address a self-only group.
+func IsSelfOnly(privateFrom []byte, privateFor [][]byte) bool {
+	if len(privateFor) == 0 {
+		return false
+	}
+	for _, v := range privateFor {
+		if string(v) != string(privateFrom) {
+			return false
+		}
+	}
+	return true
+}
+
+// SelfNonce returns the private nonce of account in the self-only group of
+// self with priv_getEeaTransactionCount, which resolves the group the same
+// way as eea_sendRawTransaction does.
+func (p *Privacy) SelfNonce(account common.Address, self *PublicKey) (uint64, error) {
+	var rsp string
+	err := p.client.CallContext(context.TODO(), &rsp, "priv_getEeaTransactionCount", account.Hex(), self.ToString(), []string{self.ToString()})
+	if err != nil {
+		return 0, err
+	}
+	return decode.Uint64(rsp)
+}
+
+// NextSelfNonce reserves the next private nonce of account in the self-only
+// group of self, see NextNonce.
+func (p *Privacy) NextSelfNonce(account common.Address, self *PublicKey) (uint64, error) {
+	group := p.SelfGroup(self)
+	entry := p.nonceEntry(account, group.ID)
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if !entry.loaded {
+		nonce, err := p.SelfNonce(account, self)
+		if err != nil {
+			return 0, err
+		}
+		entry.nonce, entry.loaded = nonce, true
+	}
+	nonce := entry.nonce
+	entry.nonce++
+	return nonce, nil
+}
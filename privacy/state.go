@@ -0,0 +1,90 @@
+package privacy
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/bsostech/go-besu/cache"
+)
+
+// State is the runtime state of a Privacy: the reserved nonces and the
+// cached privacy groups.
+type State struct {
+	Nonces []NonceState `json:"nonces"`
+	Groups []GroupState `json:"groups"`
+}
+
+// NonceState is the next nonce reserved for an account in a privacy group.
+type NonceState struct {
+	Account common.Address `json:"account"`
+	GroupID string         `json:"groupId"`
+	Nonce   uint64         `json:"nonce"`
+}
+
+// GroupState is a cached privacy group.
+type GroupState struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Type        string   `json:"type,omitempty"`
+	Members     []string `json:"members"`
+}
+
+// ExportState returns the loaded nonces and, if the group cache is a
+// cache.Ranger such as the default LRU, the cached privacy groups.
+func (p *Privacy) ExportState() *State {
+	state := new(State)
+	p.mu.RLock()
+	for key, entry := range p.nonces {
+		entry.mu.Lock()
+		if entry.loaded {
+			state.Nonces = append(state.Nonces, NonceState{Account: key.account, GroupID: key.groupID, Nonce: entry.nonce})
+		}
+		entry.mu.Unlock()
+	}
+	groups := p.groups
+	p.mu.RUnlock()
+	if r, ok := groups.(cache.Ranger); ok {
+		r.Range(func(key string, value interface{}) bool {
+			if g, ok := value.(*Group); ok && strings.HasPrefix(key, groupCacheKey("")) {
+				state.Groups = append(state.Groups, GroupState{
+					ID:          g.ID,
+					Name:        g.Name,
+					Description: g.Description,
+					Type:        g.Type,
+					Members:     NewParticipantSet(g.Members...).Strings(),
+				})
+			}
+			return true
+		})
+	}
+	return state
+}
+
+// ImportState restores the nonces and privacy groups of state, e.g. exported
+// by the previous instance of a service. Imported nonces replace the
+// reserved ones, so it is called before sending.
+func (p *Privacy) ImportState(state *State) error {
+	for _, n := range state.Nonces {
+		entry := p.nonceEntry(n.Account, n.GroupID)
+		entry.mu.Lock()
+		entry.nonce, entry.loaded = n.Nonce, true
+		entry.mu.Unlock()
+	}
+	for _, g := range state.Groups {
+		set, err := ParseParticipantSet(g.Members...)
+		if err != nil {
+			return err
+		}
+		members := set.Keys()
+		p.groupCache().Add(groupCacheKey(groupKey(members)), &Group{
+			ID:          g.ID,
+			Name:        g.Name,
+			Description: g.Description,
+			Type:        g.Type,
+			Members:     members,
+		})
+	}
+	return nil
+}
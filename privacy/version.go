@@ -0,0 +1,36 @@
+package privacy
+
+import (
+	"context"
+	"strings"
+)
+
+// ClientVersion returns the web3_clientVersion of the node, e.g.
+// "besu/v1.4.4/linux-x86_64/oracle_openjdk-java-11". It is cached after the
+// first successful call.
+func (p *Privacy) ClientVersion() (string, error) {
+	p.mu.RLock()
+	version := p.version
+	p.mu.RUnlock()
+	if version != "" {
+		return version, nil
+	}
+	err := p.client.CallContext(context.TODO(), &version, "web3_clientVersion")
+	if err != nil {
+		return "", err
+	}
+	p.mu.Lock()
+	p.version = version
+	p.mu.Unlock()
+	return version, nil
+}
+
+// clientVersion returns the client version, empty if it is unknown.
+func (p *Privacy) clientVersion() string {
+	version, _ := p.ClientVersion()
+	return version
+}
+
+func isPantheon(version string) bool {
+	return strings.HasPrefix(strings.ToLower(version), "pantheon/")
+}
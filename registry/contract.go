@@ -0,0 +1,70 @@
+package registry
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ContractABI is the interface a registry contract has to implement. Entry i
+// maps keys[i] to the organization ids[i] named names[i].
+const ContractABI = `[{"constant":true,"inputs":[],"name":"getOrganizations","outputs":[{"name":"ids","type":"string[]"},{"name":"names","type":"string[]"},{"name":"keys","type":"string[]"}],"payable":false,"stateMutability":"view","type":"function"}]`
+
+// ContractBackend loads organizations from a registry contract on the public chain.
+type ContractBackend struct {
+	client  *ethclient.Client
+	address common.Address
+	abi     abi.ABI
+}
+
+// NewContractBackend .
+func NewContractBackend(client *ethclient.Client, address common.Address) (*ContractBackend, error) {
+	parsed, err := abi.JSON(strings.NewReader(ContractABI))
+	if err != nil {
+		return nil, err
+	}
+	return &ContractBackend{
+		client:  client,
+		address: address,
+		abi:     parsed,
+	}, nil
+}
+
+// Organizations implements Backend.
+func (b *ContractBackend) Organizations(ctx context.Context) ([]*Organization, error) {
+	input, err := b.abi.Pack("getOrganizations")
+	if err != nil {
+		return nil, err
+	}
+	output, err := b.client.CallContract(ctx, ethereum.CallMsg{To: &b.address, Data: input}, nil)
+	if err != nil {
+		return nil, err
+	}
+	var entries struct {
+		Ids   []string
+		Names []string
+		Keys  []string
+	}
+	if err := b.abi.Unpack(&entries, "getOrganizations", output); err != nil {
+		return nil, err
+	}
+	var orgs []*Organization
+	byID := make(map[string]*Organization)
+	for i := range entries.Ids {
+		if i >= len(entries.Names) || i >= len(entries.Keys) {
+			break
+		}
+		org, ok := byID[entries.Ids[i]]
+		if !ok {
+			org = &Organization{ID: entries.Ids[i], Name: entries.Names[i]}
+			byID[org.ID] = org
+			orgs = append(orgs, org)
+		}
+		org.Keys = append(org.Keys, entries.Keys[i])
+	}
+	return orgs, nil
+}
@@ -0,0 +1,32 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+)
+
+// FileBackend loads organizations from a JSON file holding an array of Organization.
+type FileBackend struct {
+	Path string
+}
+
+// NewFileBackend .
+func NewFileBackend(path string) *FileBackend {
+	return &FileBackend{
+		Path: path,
+	}
+}
+
+// Organizations implements Backend.
+func (b *FileBackend) Organizations(ctx context.Context) ([]*Organization, error) {
+	data, err := ioutil.ReadFile(b.Path)
+	if err != nil {
+		return nil, err
+	}
+	var orgs []*Organization
+	if err := json.Unmarshal(data, &orgs); err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
@@ -0,0 +1,43 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// HTTPBackend loads organizations from an HTTP endpoint returning a JSON array of Organization.
+type HTTPBackend struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewHTTPBackend returns a backend using http.DefaultClient.
+func NewHTTPBackend(url string) *HTTPBackend {
+	return &HTTPBackend{
+		URL:    url,
+		Client: http.DefaultClient,
+	}
+}
+
+// Organizations implements Backend.
+func (b *HTTPBackend) Organizations(ctx context.Context) ([]*Organization, error) {
+	req, err := http.NewRequest(http.MethodGet, b.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := b.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to load organizations from %v: %v", b.URL, resp.Status)
+	}
+	var orgs []*Organization
+	if err := json.NewDecoder(resp.Body).Decode(&orgs); err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
@@ -0,0 +1,91 @@
+package registry
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bsostech/go-besu/privacy"
+)
+
+// Organization .
+type Organization struct {
+	ID   string   `json:"id"`
+	Name string   `json:"name"`
+	Keys []string `json:"keys"` // base64 enclave public keys
+}
+
+// Backend loads organizations from a source.
+type Backend interface {
+	Organizations(ctx context.Context) ([]*Organization, error)
+}
+
+// Registry maps enclave public keys to organizations.
+type Registry struct {
+	backend Backend
+
+	mu    sync.RWMutex
+	byKey map[string]*Organization
+	byID  map[string]*Organization
+}
+
+// NewRegistry returns an empty registry, call Refresh to load it from backend.
+func NewRegistry(backend Backend) *Registry {
+	return &Registry{
+		backend: backend,
+		byKey:   make(map[string]*Organization),
+		byID:    make(map[string]*Organization),
+	}
+}
+
+// Refresh reloads all organizations from the backend.
+func (r *Registry) Refresh(ctx context.Context) error {
+	orgs, err := r.backend.Organizations(ctx)
+	if err != nil {
+		return err
+	}
+	byKey := make(map[string]*Organization)
+	byID := make(map[string]*Organization)
+	for _, org := range orgs {
+		byID[org.ID] = org
+		for _, key := range org.Keys {
+			byKey[key] = org
+		}
+	}
+	r.mu.Lock()
+	r.byKey, r.byID = byKey, byID
+	r.mu.Unlock()
+	return nil
+}
+
+// Lookup returns the organization owning key.
+func (r *Registry) Lookup(key privacy.PublicKey) (*Organization, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	org, ok := r.byKey[key.ToString()]
+	return org, ok
+}
+
+// Organization returns the organization with id.
+func (r *Registry) Organization(id string) (*Organization, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	org, ok := r.byID[id]
+	return org, ok
+}
+
+// Name returns the name of the organization owning key, or the key itself if unknown.
+func (r *Registry) Name(key privacy.PublicKey) string {
+	if org, ok := r.Lookup(key); ok {
+		return org.Name
+	}
+	return key.ToString()
+}
+
+// Names returns Name of each key.
+func (r *Registry) Names(keys []*privacy.PublicKey) []string {
+	names := make([]string, len(keys))
+	for i := range keys {
+		names[i] = r.Name(*keys[i])
+	}
+	return names
+}
@@ -0,0 +1,114 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/bsostech/go-besu/clock"
+)
+
+// RateLimitCode is the JSON-RPC error code Besu and managed providers return
+// when the request rate is exceeded.
+const RateLimitCode = -32005
+
+// DefaultPolicy .
+var DefaultPolicy = Policy{
+	MaxAttempts: 5,
+	BaseDelay:   200 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+}
+
+// Policy is an exponential backoff policy.
+type Policy struct {
+	MaxAttempts int           // attempts including the first one
+	BaseDelay   time.Duration // delay before the second attempt, doubled after each attempt
+	MaxDelay    time.Duration // upper bound of the delay, Retry-After excepted
+	Clock       clock.Clock   // clock the delays are waited on, clock.Real if nil
+}
+
+// Do calls fn until it succeeds, returns an error which is not Retryable, the
+// attempts are exhausted or ctx is done. It returns the last error of fn.
+// Rate limited attempts wait at least as long as the server asked for.
+func (p Policy) Do(ctx context.Context, fn func() error) error {
+	var err error
+	for attempt := 0; attempt < p.MaxAttempts || attempt == 0; attempt++ {
+		if attempt > 0 {
+			delay := p.Delay(attempt)
+			if retryAfter, ok := RateLimited(err); ok && retryAfter > delay {
+				delay = retryAfter
+			}
+			timer := clock.Or(p.Clock).NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			case <-timer.C():
+			}
+		}
+		if err = fn(); err == nil || !Retryable(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// Delay returns the backoff delay before attempt, the first attempt being 0.
+func (p Policy) Delay(attempt int) time.Duration {
+	if attempt <= 0 {
+		return 0
+	}
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// Retryable reports whether the call which returned err can be retried:
+// rate limited calls and transport failures are, other JSON-RPC errors are not.
+func Retryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if _, ok := RateLimited(err); ok {
+		return true
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	// rpc returns non 2xx HTTP responses as errors carrying the status
+	return strings.HasPrefix(err.Error(), "502") || strings.HasPrefix(err.Error(), "503") || strings.HasPrefix(err.Error(), "504")
+}
+
+// RateLimited reports whether err is a rate limit response, and the delay the
+// server asked for if known.
+func RateLimited(err error) (time.Duration, bool) {
+	if err == nil {
+		return 0, false
+	}
+	var rateLimitErr *RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return rateLimitErr.RetryAfter, true
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == RateLimitCode {
+		return 0, true
+	}
+	return 0, strings.HasPrefix(err.Error(), "429")
+}
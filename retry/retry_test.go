@@ -0,0 +1,147 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bsostech/go-besu/clock"
+)
+
+type testRPCError struct{ code int }
+
+func (e testRPCError) Error() string  { return fmt.Sprintf("rpc error %v", e.code) }
+func (e testRPCError) ErrorCode() int { return e.code }
+
+func TestDelay(t *testing.T) {
+	p := Policy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	want := []time.Duration{0, 100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second}
+	for attempt, d := range want {
+		if got := p.Delay(attempt); got != d {
+			t.Errorf("Delay(%v) = %v, want %v", attempt, got, d)
+		}
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{&RateLimitError{Status: "429 Too Many Requests"}, true},
+		{testRPCError{RateLimitCode}, true},
+		{testRPCError{-32000}, false},
+		{context.Canceled, false},
+		{fmt.Errorf("call: %w", context.DeadlineExceeded), false},
+		{errors.New("503 Service Unavailable"), true},
+		{errors.New("400 Bad Request"), false},
+	}
+	for _, test := range tests {
+		if got := Retryable(test.err); got != test.want {
+			t.Errorf("Retryable(%v) = %v, want %v", test.err, got, test.want)
+		}
+	}
+}
+
+// do runs p.Do in a goroutine, with fn failing with errs in turn.
+func do(ctx context.Context, p Policy, errs ...error) (<-chan error, *int) {
+	calls := new(int)
+	done := make(chan error, 1)
+	go func() {
+		done <- p.Do(ctx, func() error {
+			*calls++
+			if *calls <= len(errs) {
+				return errs[*calls-1]
+			}
+			return nil
+		})
+	}()
+	return done, calls
+}
+
+func TestDoBackoff(t *testing.T) {
+	clk := clock.NewFake(time.Unix(0, 0))
+	p := Policy{MaxAttempts: 4, BaseDelay: time.Second, MaxDelay: time.Minute, Clock: clk}
+	unavailable := errors.New("503 Service Unavailable")
+	done, calls := do(context.Background(), p, unavailable, unavailable)
+	// waits 1s, then 2s
+	for _, d := range []time.Duration{time.Second, 2 * time.Second} {
+		clk.BlockUntil(1)
+		clk.Advance(d - time.Millisecond)
+		select {
+		case err := <-done:
+			t.Fatalf("returned %v before the backoff", err)
+		case <-time.After(10 * time.Millisecond):
+		}
+		clk.Advance(time.Millisecond)
+	}
+	if err := <-done; err != nil || *calls != 3 {
+		t.Fatalf("got %v after %v calls, want success after 3", err, *calls)
+	}
+}
+
+func TestDoRetryAfter(t *testing.T) {
+	clk := clock.NewFake(time.Unix(0, 0))
+	p := Policy{MaxAttempts: 2, BaseDelay: time.Second, Clock: clk}
+	done, calls := do(context.Background(), p, &RateLimitError{Status: "429", RetryAfter: 30 * time.Second})
+	clk.BlockUntil(1)
+	clk.Advance(29 * time.Second)
+	select {
+	case err := <-done:
+		t.Fatalf("returned %v before Retry-After", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+	clk.Advance(time.Second)
+	if err := <-done; err != nil || *calls != 2 {
+		t.Fatalf("got %v after %v calls", err, *calls)
+	}
+}
+
+func TestDoStops(t *testing.T) {
+	clk := clock.NewFake(time.Unix(0, 0))
+	p := Policy{MaxAttempts: 3, BaseDelay: time.Second, Clock: clk}
+	reverted := testRPCError{-32000}
+	if done, calls := do(context.Background(), p, reverted); <-done != reverted || *calls != 1 {
+		t.Fatalf("retried a non retryable error, %v calls", *calls)
+	}
+	limited := testRPCError{RateLimitCode}
+	done, calls := do(context.Background(), p, limited, limited, limited, limited)
+	for i := 0; i < 2; i++ {
+		clk.BlockUntil(1)
+		clk.Advance(time.Minute)
+	}
+	if err := <-done; err != limited || *calls != 3 {
+		t.Fatalf("got %v after %v calls, want the last error after 3", err, *calls)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done, calls = do(ctx, p, limited)
+	clk.BlockUntil(1)
+	cancel()
+	if err := <-done; err != limited || *calls != 1 {
+		t.Fatalf("got %v after %v calls, want the last error when ctx is done", err, *calls)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", 0},
+		{"120", 2 * time.Minute},
+		{"-1", 0},
+		{"soon", 0},
+		{now.Add(time.Minute).UTC().Format(http.TimeFormat), time.Minute},
+		{now.Add(-time.Minute).UTC().Format(http.TimeFormat), 0},
+	}
+	for _, test := range tests {
+		if got := ParseRetryAfter(test.header, now); got != test.want {
+			t.Errorf("ParseRetryAfter(%q) = %v, want %v", test.header, got, test.want)
+		}
+	}
+}
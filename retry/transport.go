@@ -0,0 +1,63 @@
+package retry
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimitError is returned by Transport for HTTP 429 responses.
+type RateLimitError struct {
+	Status     string
+	RetryAfter time.Duration // 0 if the response had no Retry-After
+}
+
+// Transport is an http.RoundTripper returning HTTP 429 responses as
+// *RateLimitError, so the Retry-After header reaches Policy.Do through the
+// rpc client. Use it with rpc.DialHTTPWithClient.
+type Transport struct {
+	Base http.RoundTripper // http.DefaultTransport if nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusTooManyRequests {
+		return resp, nil
+	}
+	resp.Body.Close()
+	return nil, &RateLimitError{
+		Status:     resp.Status,
+		RetryAfter: ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%v, retry after %v", e.Status, e.RetryAfter)
+	}
+	return e.Status
+}
+
+// ParseRetryAfter parses a Retry-After header given in seconds or as an HTTP
+// date, returning 0 if it is empty, invalid or in the past.
+func ParseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,216 @@
+// Package schema publishes JSON Schemas of the core types as exchanged with
+// Besu, and validates payloads against them. The validator supports the
+// keywords the schemas use: type, properties, required, items, pattern,
+// enum and anyOf.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Schemas are the schemas by title.
+var Schemas = map[string]string{
+	"PrivateTransaction": PrivateTransaction,
+	"PrivateReceipt":     PrivateReceipt,
+	"Group":              Group,
+}
+
+// ValidationError lists the violations of a payload.
+type ValidationError struct {
+	Schema     string
+	Violations []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %v: %v", e.Schema, strings.Join(e.Violations, "; "))
+}
+
+type node struct {
+	Type       json.RawMessage  `json:"type"`
+	Properties map[string]*node `json:"properties"`
+	Required   []string         `json:"required"`
+	Items      *node            `json:"items"`
+	Pattern    string           `json:"pattern"`
+	Enum       []interface{}    `json:"enum"`
+	AnyOf      []*node          `json:"anyOf"`
+
+	types   []string
+	pattern *regexp.Regexp
+}
+
+var (
+	mu     sync.Mutex
+	parsed = make(map[string]*node)
+)
+
+func load(name string) (*node, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if n, ok := parsed[name]; ok {
+		return n, nil
+	}
+	doc, ok := Schemas[name]
+	if !ok {
+		return nil, fmt.Errorf("schema %v not found", name)
+	}
+	n := new(node)
+	if err := json.Unmarshal([]byte(doc), n); err != nil {
+		return nil, err
+	}
+	if err := n.compile(); err != nil {
+		return nil, err
+	}
+	parsed[name] = n
+	return n, nil
+}
+
+func (n *node) compile() error {
+	if len(n.Type) > 0 {
+		var one string
+		if err := json.Unmarshal(n.Type, &one); err == nil {
+			n.types = []string{one}
+		} else if err := json.Unmarshal(n.Type, &n.types); err != nil {
+			return err
+		}
+	}
+	if n.Pattern != "" {
+		re, err := regexp.Compile(n.Pattern)
+		if err != nil {
+			return err
+		}
+		n.pattern = re
+	}
+	children := append([]*node{n.Items}, n.AnyOf...)
+	for _, p := range n.Properties {
+		children = append(children, p)
+	}
+	for _, c := range children {
+		if c == nil {
+			continue
+		}
+		if err := c.compile(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate validates the JSON payload data against the schema named name, returning a *ValidationError.
+func Validate(name string, data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	return ValidateValue(name, v)
+}
+
+// ValidateValue validates a payload decoded with encoding/json, e.g. an RPC response.
+func ValidateValue(name string, v interface{}) error {
+	n, err := load(name)
+	if err != nil {
+		return err
+	}
+	var violations []string
+	n.validate("$", v, &violations)
+	if len(violations) > 0 {
+		return &ValidationError{Schema: name, Violations: violations}
+	}
+	return nil
+}
+
+func (n *node) validate(path string, v interface{}, violations *[]string) {
+	if len(n.types) > 0 && !n.hasType(v) {
+		*violations = append(*violations, fmt.Sprintf("%v: expected %v, got %v", path, strings.Join(n.types, " or "), typeOf(v)))
+		return
+	}
+	if len(n.Enum) > 0 && !n.inEnum(v) {
+		*violations = append(*violations, fmt.Sprintf("%v: %v not allowed", path, v))
+	}
+	switch v := v.(type) {
+	case string:
+		if n.pattern != nil && !n.pattern.MatchString(v) {
+			*violations = append(*violations, fmt.Sprintf("%v: %q does not match %v", path, v, n.Pattern))
+		}
+	case []interface{}:
+		if n.Items != nil {
+			for i, item := range v {
+				n.Items.validate(fmt.Sprintf("%v[%v]", path, i), item, violations)
+			}
+		}
+	case map[string]interface{}:
+		for _, key := range n.Required {
+			if _, ok := v[key]; !ok {
+				*violations = append(*violations, fmt.Sprintf("%v.%v: required", path, key))
+			}
+		}
+		keys := make([]string, 0, len(n.Properties))
+		for key := range n.Properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if value, ok := v[key]; ok {
+				n.Properties[key].validate(path+"."+key, value, violations)
+			}
+		}
+		if len(n.AnyOf) > 0 && !n.anyOf(path, v) {
+			*violations = append(*violations, fmt.Sprintf("%v: matches none of anyOf", path))
+		}
+	}
+}
+
+func (n *node) anyOf(path string, v interface{}) bool {
+	for _, alt := range n.AnyOf {
+		var violations []string
+		alt.validate(path, v, &violations)
+		if len(violations) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *node) hasType(v interface{}) bool {
+	t := typeOf(v)
+	for _, want := range n.types {
+		if want == t || want == "number" && t == "integer" {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *node) inEnum(v interface{}) bool {
+	for _, e := range n.Enum {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+func typeOf(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
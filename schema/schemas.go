@@ -0,0 +1,74 @@
+package schema
+
+// PrivateTransaction is the JSON Schema of a private transaction as returned
+// by priv_getPrivateTransaction.
+const PrivateTransaction = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "PrivateTransaction",
+  "type": "object",
+  "properties": {
+    "blockHash": {"type": ["string", "null"], "pattern": "^0x[0-9a-fA-F]{64}$"},
+    "blockNumber": {"type": ["string", "null"], "pattern": "^(0x)?[0-9a-fA-F]+$"},
+    "transactionIndex": {"type": ["string", "null"], "pattern": "^(0x)?[0-9a-fA-F]+$"},
+    "hash": {"type": "string", "pattern": "^0x[0-9a-fA-F]{64}$"},
+    "from": {"type": "string", "pattern": "^0x[0-9a-fA-F]{40}$"},
+    "to": {"type": ["string", "null"], "pattern": "^(0x[0-9a-fA-F]{40})?$"},
+    "nonce": {"type": "string", "pattern": "^(0x)?[0-9a-fA-F]+$"},
+    "gas": {"type": "string", "pattern": "^(0x)?[0-9a-fA-F]+$"},
+    "gasPrice": {"type": "string", "pattern": "^(0x)?[0-9a-fA-F]+$"},
+    "value": {"type": "string", "pattern": "^(0x)?[0-9a-fA-F]+$"},
+    "input": {"type": "string", "pattern": "^(0x)?([0-9a-fA-F]{2})*$"},
+    "v": {"type": "string", "pattern": "^(0x)?[0-9a-fA-F]+$"},
+    "r": {"type": "string", "pattern": "^(0x)?[0-9a-fA-F]+$"},
+    "s": {"type": "string", "pattern": "^(0x)?[0-9a-fA-F]+$"},
+    "privateFrom": {"type": "string", "pattern": "^[A-Za-z0-9+/]+={0,2}$"},
+    "privateFor": {"type": "array", "items": {"type": "string", "pattern": "^[A-Za-z0-9+/]+={0,2}$"}},
+    "privacyGroupId": {"type": "string", "pattern": "^[A-Za-z0-9+/]+={0,2}$"},
+    "restriction": {"type": "string", "enum": ["restricted", "unrestricted"]}
+  },
+  "required": ["input", "privateFrom"],
+  "anyOf": [{"required": ["privateFor"]}, {"required": ["privacyGroupId"]}]
+}`
+
+// PrivateReceipt is the JSON Schema of a private receipt as returned by
+// priv_getTransactionReceipt.
+const PrivateReceipt = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "PrivateReceipt",
+  "type": "object",
+  "properties": {
+    "contractAddress": {"type": ["string", "null"], "pattern": "^0x[0-9a-fA-F]{40}$"},
+    "from": {"type": "string", "pattern": "^0x[0-9a-fA-F]{40}$"},
+    "to": {"type": ["string", "null"], "pattern": "^0x[0-9a-fA-F]{40}$"},
+    "output": {"type": ["string", "null"], "pattern": "^(0x)?([0-9a-fA-F]{2})*$"},
+    "commitmentHash": {"type": "string", "pattern": "^0x[0-9a-fA-F]{64}$"},
+    "transactionHash": {"type": "string", "pattern": "^0x[0-9a-fA-F]{64}$"},
+    "privateFrom": {"type": "string", "pattern": "^[A-Za-z0-9+/]+={0,2}$"},
+    "privateFor": {"type": ["array", "null"], "items": {"type": "string", "pattern": "^[A-Za-z0-9+/]+={0,2}$"}},
+    "privacyGroupId": {"type": ["string", "null"], "pattern": "^[A-Za-z0-9+/]+={0,2}$"},
+    "status": {"type": "string", "pattern": "^(0x)?[0-9a-fA-F]+$"},
+    "logs": {"type": "array", "items": {"type": "object"}},
+    "logsBloom": {"type": "string", "pattern": "^(0x)?([0-9a-fA-F]{2})*$"},
+    "blockHash": {"type": "string", "pattern": "^0x[0-9a-fA-F]{64}$"},
+    "blockNumber": {"type": "string", "pattern": "^(0x)?[0-9a-fA-F]+$"},
+    "transactionIndex": {"type": "string", "pattern": "^(0x)?[0-9a-fA-F]+$"},
+    "revertReason": {"type": ["string", "null"], "pattern": "^(0x)?([0-9a-fA-F]{2})*$"}
+  },
+  "required": ["commitmentHash", "transactionHash", "privateFrom", "logs", "logsBloom"],
+  "anyOf": [{"required": ["privateFor"]}, {"required": ["privacyGroupId"]}]
+}`
+
+// Group is the JSON Schema of a privacy group as returned by priv_findPrivacyGroup.
+const Group = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "Group",
+  "type": "object",
+  "properties": {
+    "privacyGroupId": {"type": "string", "pattern": "^[A-Za-z0-9+/]+={0,2}$"},
+    "name": {"type": ["string", "null"]},
+    "description": {"type": ["string", "null"]},
+    "type": {"type": ["string", "null"], "enum": ["LEGACY", "PANTHEON", "ONCHAIN", null]},
+    "members": {"type": "array", "items": {"type": "string", "pattern": "^[A-Za-z0-9+/]+={0,2}$"}}
+  },
+  "required": ["privacyGroupId", "members"]
+}`
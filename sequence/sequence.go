@@ -0,0 +1,223 @@
+// Package sequence sends dependent private transactions in order, such as
+// deploying a contract, initializing it and granting roles, checking each
+// receipt before the next step and rolling back on failure.
+package sequence
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bsostech/go-besu/client"
+	"github.com/bsostech/go-besu/privacy"
+	"github.com/bsostech/go-besu/types"
+)
+
+// Step is a transaction of a sequence.
+type Step struct {
+	Name string
+	// Build returns the unsigned transaction of the step with nonce. Results
+	// holds the receipts of the previous steps, e.g. for deployed addresses.
+	Build func(nonce uint64, results *Result) (*types.PrivateTransaction, error)
+	// Check validates the receipt of the step, requiring status 1 if nil.
+	Check func(receipt *types.PrivateReceipt) error
+	// Rollback undoes the step when a later step fails, nil if there is nothing to undo.
+	Rollback func(ctx context.Context, receipt *types.PrivateReceipt) error
+}
+
+// Result holds the receipts of the completed steps.
+type Result struct {
+	Steps    []string
+	Receipts map[string]*types.PrivateReceipt
+}
+
+// StepError is returned by Run when a step fails.
+type StepError struct {
+	Step         string
+	Err          error
+	RollbackErrs map[string]error // failed rollbacks by step
+}
+
+func (e *StepError) Error() string {
+	if len(e.RollbackErrs) > 0 {
+		return fmt.Sprintf("step %v failed: %v, %v rollbacks failed", e.Step, e.Err, len(e.RollbackErrs))
+	}
+	return fmt.Sprintf("step %v failed: %v", e.Step, e.Err)
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// Sequence is a builder of dependent transactions sent by one account to a privacy group.
+type Sequence struct {
+	Profile types.NetworkProfile
+
+	client      *client.Client
+	key         *ecdsa.PrivateKey
+	chainID     *big.Int
+	privateFrom privacy.PublicKey
+	group       *privacy.Group
+	steps       []Step
+}
+
+// New returns an empty sequence sending with key from privateFrom to group,
+// with the defaults of types.FreeGasNetwork.
+func New(c *client.Client, key *ecdsa.PrivateKey, chainID *big.Int, privateFrom privacy.PublicKey, group *privacy.Group) *Sequence {
+	return &Sequence{
+		Profile:     types.FreeGasNetwork,
+		client:      c,
+		key:         key,
+		chainID:     chainID,
+		privateFrom: privateFrom,
+		group:       group,
+	}
+}
+
+// Add appends step.
+func (s *Sequence) Add(step Step) *Sequence {
+	s.steps = append(s.steps, step)
+	return s
+}
+
+// Deploy appends a contract creation step.
+func (s *Sequence) Deploy(name string, code []byte) *Sequence {
+	return s.Add(Step{
+		Name: name,
+		Build: func(nonce uint64, results *Result) (*types.PrivateTransaction, error) {
+			groupID, err := s.groupID()
+			if err != nil {
+				return nil, err
+			}
+			return s.Profile.NewPrivateTransaction(nonce, nil, nil, code, s.privateFrom, groupID), nil
+		},
+	})
+}
+
+// Call appends a step calling the contract deployed by the step named contract.
+func (s *Sequence) Call(name, contract string, data []byte) *Sequence {
+	return s.Add(Step{
+		Name: name,
+		Build: func(nonce uint64, results *Result) (*types.PrivateTransaction, error) {
+			to, err := results.ContractAddress(contract)
+			if err != nil {
+				return nil, err
+			}
+			groupID, err := s.groupID()
+			if err != nil {
+				return nil, err
+			}
+			return s.Profile.NewPrivateTransaction(nonce, &to, nil, data, s.privateFrom, groupID), nil
+		},
+	})
+}
+
+// Run sends the steps in order, each after the receipt of the previous one
+// passed its check. When a step fails, the rollbacks of the completed steps
+// run in reverse order and a *StepError is returned with the receipts of the
+// completed steps.
+func (s *Sequence) Run(ctx context.Context) (*Result, error) {
+	account := crypto.PubkeyToAddress(s.key.PublicKey)
+	results := &Result{
+		Receipts: make(map[string]*types.PrivateReceipt),
+	}
+	for _, step := range s.steps {
+		receipt, err := s.run(ctx, account, step, results)
+		if err != nil {
+			s.client.ResetNonce(account, s.group)
+			return results, s.rollback(ctx, step.Name, err, results)
+		}
+		results.Steps = append(results.Steps, step.Name)
+		results.Receipts[step.Name] = receipt
+	}
+	return results, nil
+}
+
+func (s *Sequence) run(ctx context.Context, account common.Address, step Step, results *Result) (*types.PrivateReceipt, error) {
+	nonce, err := s.client.NextNonce(account, s.group)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := step.Build(nonce, results)
+	if err != nil {
+		return nil, err
+	}
+	signed, err := tx.SignTx(s.chainID, s.key)
+	if err != nil {
+		return nil, err
+	}
+	pmtHash, err := s.client.SendTransaction(ctx, signed)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := s.client.WaitForReceipt(ctx, pmtHash)
+	if err != nil {
+		return nil, err
+	}
+	check := step.Check
+	if check == nil {
+		check = requireSuccess
+	}
+	if err := check(receipt); err != nil {
+		return receipt, err
+	}
+	return receipt, nil
+}
+
+func (s *Sequence) rollback(ctx context.Context, failed string, err error, results *Result) error {
+	stepErr := &StepError{
+		Step: failed,
+		Err:  err,
+	}
+	for i := len(results.Steps) - 1; i >= 0; i-- {
+		name := results.Steps[i]
+		step := s.step(name)
+		if step.Rollback == nil {
+			continue
+		}
+		if err := step.Rollback(ctx, results.Receipts[name]); err != nil {
+			if stepErr.RollbackErrs == nil {
+				stepErr.RollbackErrs = make(map[string]error)
+			}
+			stepErr.RollbackErrs[name] = err
+		}
+	}
+	return stepErr
+}
+
+func (s *Sequence) step(name string) Step {
+	for _, step := range s.steps {
+		if step.Name == name {
+			return step
+		}
+	}
+	return Step{}
+}
+
+func (s *Sequence) groupID() ([]byte, error) {
+	return base64.StdEncoding.DecodeString(s.group.ID)
+}
+
+// ContractAddress returns the contract created by the step named name.
+func (r *Result) ContractAddress(name string) (common.Address, error) {
+	receipt, ok := r.Receipts[name]
+	if !ok {
+		return common.Address{}, fmt.Errorf("step %v not found", name)
+	}
+	if receipt.ContractAddress == (common.Address{}) {
+		return common.Address{}, fmt.Errorf("step %v created no contract", name)
+	}
+	return receipt.ContractAddress, nil
+}
+
+func requireSuccess(receipt *types.PrivateReceipt) error {
+	if receipt.Status != 1 {
+		return fmt.Errorf("%v", receipt.FailureReason())
+	}
+	return nil
+}
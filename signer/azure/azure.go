@@ -0,0 +1,221 @@
+// Package azure signs with EC keys of curve P-256K held by Azure Key Vault,
+// the keys never leaving the vault.
+package azure
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bsostech/go-besu/signer"
+)
+
+// APIVersion is the Key Vault REST API version used.
+const APIVersion = "7.4"
+
+// TokenSource returns bearer tokens for the Key Vault resource.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticToken is a TokenSource returning the same token, e.g. from a managed identity sidecar.
+type StaticToken string
+
+// Token implements TokenSource.
+func (t StaticToken) Token(ctx context.Context) (string, error) {
+	return string(t), nil
+}
+
+// ClientCredentials is a TokenSource getting tokens of a service principal
+// from Azure AD, cached until they expire.
+type ClientCredentials struct {
+	TenantID     string
+	ClientID     string
+	ClientSecret string
+	HTTPClient   *http.Client
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// Token implements TokenSource.
+func (c *ClientCredentials) Token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" && time.Now().Before(c.expires) {
+		return c.token, nil
+	}
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {c.ClientID},
+		"client_secret": {c.ClientSecret},
+		"scope":         {"https://vault.azure.net/.default"},
+	}
+	req, err := http.NewRequest(http.MethodPost, "https://login.microsoftonline.com/"+c.TenantID+"/oauth2/v2.0/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	var rsp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := send(ctx, c.HTTPClient, req, &rsp); err != nil {
+		return "", err
+	}
+	// renew a minute early
+	c.token, c.expires = rsp.AccessToken, time.Now().Add(time.Duration(rsp.ExpiresIn)*time.Second-time.Minute)
+	return c.token, nil
+}
+
+// Signer is a signer.Signer signing with a Key Vault key.
+type Signer struct {
+	keyURL     string
+	tokens     TokenSource
+	httpClient *http.Client
+	address    common.Address
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// NewSigner returns a signer with the key named key of the vault at vaultURL,
+// e.g. https://myvault.vault.azure.net, in its version if not empty, reading
+// its public key. httpClient is http.DefaultClient if nil.
+func NewSigner(ctx context.Context, vaultURL, key, version string, tokens TokenSource, httpClient *http.Client) (*Signer, error) {
+	s := &Signer{
+		keyURL:     strings.TrimSuffix(vaultURL, "/") + "/keys/" + key,
+		tokens:     tokens,
+		httpClient: httpClient,
+	}
+	if version != "" {
+		s.keyURL += "/" + version
+	}
+	var rsp struct {
+		Key jsonWebKey `json:"key"`
+	}
+	if err := s.do(ctx, http.MethodGet, s.keyURL, nil, &rsp); err != nil {
+		return nil, err
+	}
+	if (rsp.Key.Kty != "EC" && rsp.Key.Kty != "EC-HSM") || rsp.Key.Crv != "P-256K" {
+		return nil, fmt.Errorf("key %v is %v %v, not EC P-256K", key, rsp.Key.Kty, rsp.Key.Crv)
+	}
+	x, err := base64.RawURLEncoding.DecodeString(rsp.Key.X)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key of %v, err: %v", key, err)
+	}
+	y, err := base64.RawURLEncoding.DecodeString(rsp.Key.Y)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key of %v, err: %v", key, err)
+	}
+	if len(x) > 32 || len(y) > 32 {
+		return nil, fmt.Errorf("invalid public key of %v, coordinates of %v and %v bytes", key, len(x), len(y))
+	}
+	pub := make([]byte, 65)
+	pub[0] = 4
+	copy(pub[33-len(x):33], x)
+	copy(pub[65-len(y):], y)
+	pubKey, err := crypto.UnmarshalPubkey(pub)
+	if err != nil {
+		return nil, err
+	}
+	s.address = crypto.PubkeyToAddress(*pubKey)
+	return s, nil
+}
+
+// Address implements signer.Signer.
+func (s *Signer) Address() common.Address {
+	return s.address
+}
+
+// SignHash implements signer.Signer. Key Vault returns R || S without
+// recovery ID and S may be high, so the signature is normalized to low S and
+// V is found by recovery, as Besu requires.
+func (s *Signer) SignHash(ctx context.Context, hash common.Hash) ([]byte, error) {
+	req := map[string]string{
+		"alg":   "ES256K",
+		"value": base64.RawURLEncoding.EncodeToString(hash[:]),
+	}
+	var rsp struct {
+		Value string `json:"value"`
+	}
+	if err := s.do(ctx, http.MethodPost, s.keyURL+"/sign", req, &rsp); err != nil {
+		return nil, err
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(rsp.Value)
+	if err != nil || len(sig) != 64 {
+		return nil, fmt.Errorf("invalid signature %v", rsp.Value)
+	}
+	r := new(big.Int).SetBytes(sig[:32])
+	ss := new(big.Int).SetBytes(sig[32:])
+	return signer.FromRS(r, ss, hash, s.address)
+}
+
+func (s *Signer) do(ctx context.Context, method, u string, in, out interface{}) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequest(method, u+"?api-version="+APIVersion, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	token, err := s.tokens.Token(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return send(ctx, s.httpClient, req, out)
+}
+
+func send(ctx context.Context, httpClient *http.Client, req *http.Request, out interface{}) error {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var rsp struct {
+			Error struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.Unmarshal(data, &rsp)
+		return fmt.Errorf("%v %v failed, status: %v, err: %v %v", req.Method, req.URL.Path, resp.Status, rsp.Error.Code, rsp.Error.Message)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response, err: %v", err)
+	}
+	return nil
+}
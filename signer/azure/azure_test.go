@@ -0,0 +1,55 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// keyServer answers key requests with a P-256K key of coordinates x and y.
+func keyServer(t *testing.T, x, y []byte) string {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"key":{"kty":"EC-HSM","crv":"P-256K","x":%q,"y":%q}}`,
+			base64.RawURLEncoding.EncodeToString(x), base64.RawURLEncoding.EncodeToString(y))
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func TestNewSignerPublicKey(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub := crypto.FromECDSAPub(&key.PublicKey)
+	x, y := pub[1:33], pub[33:]
+	tests := []struct {
+		name string
+		x, y []byte
+		err  string
+	}{
+		{name: "valid", x: x, y: y},
+		{name: "x too long", x: append([]byte{0}, x...), y: y, err: "invalid public key"},
+		{name: "y too long", x: x, y: bytes.Repeat([]byte{1}, 48), err: "invalid public key"},
+	}
+	for _, test := range tests {
+		s, err := NewSigner(context.Background(), keyServer(t, test.x, test.y), "key", "", StaticToken("token"), nil)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%v: got %v, want %q", test.name, err, test.err)
+			}
+			continue
+		}
+		if err != nil || s.Address() != crypto.PubkeyToAddress(key.PublicKey) {
+			t.Errorf("%v: got %v, %v", test.name, s, err)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+// Package pkcs11 signs with secp256k1 keys held by a PKCS#11 HSM, e.g.
+// SoftHSM in tests. The caller opens the module with the binding of its
+// choice, usually github.com/miekg/pkcs11, and adapts it to Module, so that
+// this module does not need cgo.
+package pkcs11
+
+import (
+	"context"
+	"encoding/asn1"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bsostech/go-besu/signer"
+)
+
+// DefaultPoolSize is the number of sessions a signer opens by default.
+const DefaultPoolSize = 4
+
+// ObjectHandle is a CK_OBJECT_HANDLE.
+type ObjectHandle uint
+
+// Module is a loaded PKCS#11 library.
+type Module interface {
+	// OpenSession opens a read only session on slot.
+	OpenSession(slot uint) (Session, error)
+}
+
+// Session is a PKCS#11 session. With github.com/miekg/pkcs11, the methods
+// map to Login with CKU_USER, FindObjectsInit/FindObjects on CKA_CLASS,
+// CKA_KEY_TYPE CKK_EC, CKA_LABEL and CKA_ID, GetAttributeValue of CKA_EC_POINT
+// and SignInit/Sign with CKM_ECDSA.
+type Session interface {
+	// Login logs the user in, succeeding if already logged in by another session.
+	Login(pin string) error
+	// FindKey returns the key of class CKO_PRIVATE_KEY or CKO_PUBLIC_KEY
+	// matching label and id, either of which may be empty.
+	FindKey(public bool, label string, id []byte) (ObjectHandle, error)
+	// ECPoint returns the CKA_EC_POINT of a public key.
+	ECPoint(key ObjectHandle) ([]byte, error)
+	// SignECDSA signs digest with CKM_ECDSA, returning R || S.
+	SignECDSA(key ObjectHandle, digest []byte) ([]byte, error)
+	Close() error
+}
+
+// Config selects the slot and key.
+type Config struct {
+	Slot     uint   `yaml:"slot" json:"slot"`
+	PIN      string `yaml:"pin" json:"pin"`
+	KeyLabel string `yaml:"keyLabel" json:"keyLabel"`
+	KeyID    string `yaml:"keyId" json:"keyId"`       // hex CKA_ID
+	PoolSize int    `yaml:"poolSize" json:"poolSize"` // sessions, DefaultPoolSize if 0
+}
+
+// Signer is a signer.Signer signing with an HSM key, on a pool of sessions
+// as a PKCS#11 session can only run one operation at a time.
+type Signer struct {
+	module  Module
+	cfg     Config
+	keyID   []byte
+	address common.Address
+
+	sessions chan *session // idle sessions
+	slots    chan struct{} // one per open session, bounding them to PoolSize
+	mu       sync.Mutex
+	closed   bool
+}
+
+type session struct {
+	Session
+	key ObjectHandle
+}
+
+// NewSigner opens a session on the slot of cfg, logs in and reads the public key.
+func NewSigner(m Module, cfg Config) (*Signer, error) {
+	if cfg.PoolSize <= 0 {
+		cfg.PoolSize = DefaultPoolSize
+	}
+	keyID, err := hex.DecodeString(cfg.KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keyId %v", cfg.KeyID)
+	}
+	if cfg.KeyLabel == "" && len(keyID) == 0 {
+		return nil, fmt.Errorf("keyLabel or keyId not found")
+	}
+	s := &Signer{
+		module:   m,
+		cfg:      cfg,
+		keyID:    keyID,
+		sessions: make(chan *session, cfg.PoolSize),
+		slots:    make(chan struct{}, cfg.PoolSize),
+	}
+	s.slots <- struct{}{}
+	sess, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+	defer s.release(sess)
+	pubKey, err := sess.FindKey(true, cfg.KeyLabel, keyID)
+	if err != nil {
+		return nil, err
+	}
+	point, err := sess.ECPoint(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	// CKA_EC_POINT is a DER OCTET STRING holding the uncompressed point
+	var raw []byte
+	if rest, err := asn1.Unmarshal(point, &raw); err != nil || len(rest) > 0 {
+		raw = point
+	}
+	pub, err := crypto.UnmarshalPubkey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key, err: %v", err)
+	}
+	s.address = crypto.PubkeyToAddress(*pub)
+	return s, nil
+}
+
+// Address implements signer.Signer.
+func (s *Signer) Address() common.Address {
+	return s.address
+}
+
+// SignHash implements signer.Signer, waiting for a free session until ctx is done.
+func (s *Signer) SignHash(ctx context.Context, hash common.Hash) ([]byte, error) {
+	sess, err := s.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := sess.SignECDSA(sess.key, hash[:])
+	if err != nil {
+		// the session may be broken, e.g. after the HSM restarted
+		s.discard(sess)
+		return nil, err
+	}
+	s.release(sess)
+	if len(sig) != 64 {
+		return nil, fmt.Errorf("invalid signature length %v", len(sig))
+	}
+	return signer.FromRS(new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:]), hash, s.address)
+}
+
+// Close closes the sessions of the pool.
+func (s *Signer) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	var err error
+	for {
+		select {
+		case sess := <-s.sessions:
+			if e := sess.Close(); e != nil && err == nil {
+				err = e
+			}
+			<-s.slots
+		default:
+			return err
+		}
+	}
+}
+
+// acquire returns an idle session, opening one if the pool is not full. It
+// waits for a session to be released, or discarded freeing its slot, until
+// ctx is done.
+func (s *Signer) acquire(ctx context.Context) (*session, error) {
+	select {
+	case sess := <-s.sessions:
+		return sess, nil
+	default:
+	}
+	s.mu.Lock()
+	closed := s.closed
+	s.mu.Unlock()
+	if closed {
+		return nil, fmt.Errorf("signer is closed")
+	}
+	select {
+	case sess := <-s.sessions:
+		return sess, nil
+	case s.slots <- struct{}{}:
+		return s.open()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// open dials a session on a taken slot, freeing the slot on failure.
+func (s *Signer) open() (*session, error) {
+	sess, err := s.dial()
+	if err != nil {
+		<-s.slots
+		return nil, err
+	}
+	return sess, nil
+}
+
+func (s *Signer) dial() (*session, error) {
+	raw, err := s.module.OpenSession(s.cfg.Slot)
+	if err != nil {
+		return nil, err
+	}
+	if err := raw.Login(s.cfg.PIN); err != nil {
+		raw.Close()
+		return nil, err
+	}
+	key, err := raw.FindKey(false, s.cfg.KeyLabel, s.keyID)
+	if err != nil {
+		raw.Close()
+		return nil, err
+	}
+	return &session{Session: raw, key: key}, nil
+}
+
+func (s *Signer) release(sess *session) {
+	s.mu.Lock()
+	closed := s.closed
+	s.mu.Unlock()
+	if closed {
+		s.discard(sess)
+		return
+	}
+	select {
+	case s.sessions <- sess:
+	default:
+		// never block on a full pool
+		s.discard(sess)
+	}
+}
+
+// discard closes sess and frees its slot, waking an acquire waiting for one.
+func (s *Signer) discard(sess *session) {
+	sess.Close()
+	<-s.slots
+}
@@ -0,0 +1,285 @@
+package pkcs11
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// fakeModule is a Module holding one key, which fails to open sessions while
+// failOpen is set and checks that sessions are not used concurrently.
+type fakeModule struct {
+	key      *ecdsa.PrivateKey
+	failOpen int32
+
+	mu      sync.Mutex
+	open    int
+	maxOpen int
+	opens   int
+}
+
+func (m *fakeModule) OpenSession(slot uint) (Session, error) {
+	if atomic.LoadInt32(&m.failOpen) != 0 {
+		return nil, fmt.Errorf("CKR_DEVICE_ERROR")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.open++
+	m.opens++
+	if m.open > m.maxOpen {
+		m.maxOpen = m.open
+	}
+	return &fakeSession{m: m}, nil
+}
+
+func (m *fakeModule) stats() (open, maxOpen, opens int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.open, m.maxOpen, m.opens
+}
+
+type fakeSession struct {
+	m      *fakeModule
+	busy   int32
+	closed int32
+}
+
+func (s *fakeSession) Login(pin string) error {
+	return nil
+}
+
+func (s *fakeSession) FindKey(public bool, label string, id []byte) (ObjectHandle, error) {
+	if public {
+		return 1, nil
+	}
+	return 2, nil
+}
+
+func (s *fakeSession) ECPoint(key ObjectHandle) ([]byte, error) {
+	return asn1.Marshal(crypto.FromECDSAPub(&s.m.key.PublicKey))
+}
+
+func (s *fakeSession) SignECDSA(key ObjectHandle, digest []byte) ([]byte, error) {
+	if !atomic.CompareAndSwapInt32(&s.busy, 0, 1) {
+		return nil, fmt.Errorf("CKR_OPERATION_ACTIVE")
+	}
+	defer atomic.StoreInt32(&s.busy, 0)
+	if atomic.LoadInt32(&s.closed) != 0 {
+		return nil, fmt.Errorf("CKR_SESSION_CLOSED")
+	}
+	time.Sleep(time.Millisecond)
+	sig, err := crypto.Sign(digest, s.m.key)
+	if err != nil {
+		return nil, err
+	}
+	return sig[:64], nil
+}
+
+func (s *fakeSession) Close() error {
+	if atomic.CompareAndSwapInt32(&s.closed, 0, 1) {
+		s.m.mu.Lock()
+		s.m.open--
+		s.m.mu.Unlock()
+	}
+	return nil
+}
+
+func newFakeSigner(t *testing.T, poolSize int) (*Signer, *fakeModule) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &fakeModule{key: key}
+	s, err := NewSigner(m, Config{KeyLabel: "besu", PoolSize: poolSize})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Address() != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Fatalf("address %v, want %v", s.Address().Hex(), crypto.PubkeyToAddress(key.PublicKey).Hex())
+	}
+	return s, m
+}
+
+func sign(s *Signer, i int) error {
+	hash := crypto.Keccak256Hash([]byte(fmt.Sprint(i)))
+	sig, err := s.SignHash(context.Background(), hash)
+	if err != nil {
+		return err
+	}
+	pub, err := crypto.SigToPub(hash[:], sig)
+	if err != nil {
+		return err
+	}
+	if crypto.PubkeyToAddress(*pub) != s.Address() {
+		return fmt.Errorf("signature of %v does not recover the signer", hash.Hex())
+	}
+	return nil
+}
+
+func TestSignConcurrently(t *testing.T) {
+	const poolSize = 3
+	s, m := newFakeSigner(t, poolSize)
+	errs := make(chan error, 200)
+	var wg sync.WaitGroup
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- sign(s, i)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, maxOpen, _ := m.stats(); maxOpen > poolSize {
+		t.Fatalf("%v sessions open at once, pool size %v", maxOpen, poolSize)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if open, _, _ := m.stats(); open != 0 {
+		t.Fatalf("%v sessions open after Close", open)
+	}
+}
+
+func TestOpenFailureFreesSlot(t *testing.T) {
+	s, m := newFakeSigner(t, 2)
+	// hold the session opened by NewSigner so that signing has to open one
+	held, err := s.acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	atomic.StoreInt32(&m.failOpen, 1)
+	for i := 0; i < 5; i++ {
+		if err := sign(s, i); err == nil {
+			t.Fatal("signed without a session")
+		}
+	}
+	atomic.StoreInt32(&m.failOpen, 0)
+	// the failed opens must not have used up the free slot
+	if err := sign(s, 0); err != nil {
+		t.Fatal(err)
+	}
+	s.release(held)
+	if _, _, opens := m.stats(); opens != 2 {
+		t.Fatalf("%v sessions opened, want 2", opens)
+	}
+}
+
+func TestReleaseFullPool(t *testing.T) {
+	s, m := newFakeSigner(t, 1)
+	// a session on a slot beyond the pool, which has no room for it
+	extra, err := s.dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.slots = make(chan struct{}, 2)
+	s.slots <- struct{}{}
+	s.slots <- struct{}{}
+	done := make(chan struct{})
+	go func() {
+		s.release(extra)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("release blocked on a full pool")
+	}
+	if open, _, _ := m.stats(); open != 1 {
+		t.Fatalf("%v sessions open, want 1", open)
+	}
+	if err := sign(s, 0); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAcquireWaitsForContext(t *testing.T) {
+	s, _ := newFakeSigner(t, 1)
+	held, err := s.acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.release(held)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := s.SignHash(ctx, common.Hash{1}); err != context.DeadlineExceeded {
+		t.Fatalf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestDiscardWakesAcquire(t *testing.T) {
+	s, m := newFakeSigner(t, 1)
+	held, err := s.acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- sign(s, 0) }()
+	// wait for the signer to block on the full pool
+	time.Sleep(20 * time.Millisecond)
+	select {
+	case err := <-done:
+		t.Fatalf("signed on a full pool, %v", err)
+	default:
+	}
+	// a broken session is discarded, e.g. after the HSM restarted
+	s.discard(held)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("discard did not wake the waiting signer")
+	}
+	if open, _, opens := m.stats(); open != 1 || opens != 2 {
+		t.Fatalf("%v sessions open of %v opened, want 1 of 2", open, opens)
+	}
+}
+
+func TestFailedSignWakesAcquire(t *testing.T) {
+	s, m := newFakeSigner(t, 1)
+	held, err := s.acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func(i int) { done <- sign(s, i) }(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	// the HSM restarted: the first signer fails on the broken session and
+	// discards it, which must wake the second one
+	held.Close()
+	s.release(held)
+	var failed int
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-done:
+			if err != nil {
+				failed++
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("failed sign did not wake the waiting signer")
+		}
+	}
+	if failed != 1 {
+		t.Fatalf("%v signatures failed, want 1", failed)
+	}
+	if _, _, opens := m.stats(); opens != 2 {
+		t.Fatalf("%v sessions opened, want 2", opens)
+	}
+}
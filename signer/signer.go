@@ -0,0 +1,169 @@
+// Package signer signs private transactions with keys held in memory or by
+// external key custody services, see the subpackages.
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bsostech/go-besu/types"
+)
+
+// Signer signs digests with the key of an account.
+type Signer interface {
+	Address() common.Address
+	// SignHash returns a 65 bytes [R || S || V] signature of hash, with V
+	// being 0 or 1, as returned by crypto.Sign.
+	SignHash(ctx context.Context, hash common.Hash) ([]byte, error)
+}
+
+// SignTx signs tx for chain chainID with s.
+func SignTx(ctx context.Context, s Signer, tx *types.PrivateTransaction, chainID *big.Int) (*types.PrivateTransaction, error) {
+	sig, err := s.SignHash(ctx, types.SigningHash(tx, chainID))
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(chainID, sig)
+}
+
+// PayloadSigner signs the Keccak-256 hash of payloads it hashes itself, e.g.
+// remote signers which do not sign digests.
+type PayloadSigner interface {
+	Address() common.Address
+	// SignPayload returns a 65 bytes [R || S || V] signature of the
+	// Keccak-256 hash of payload, with V being 0 or 1.
+	SignPayload(ctx context.Context, payload []byte) ([]byte, error)
+}
+
+// SignTxPayload signs tx for chain chainID with s.
+func SignTxPayload(ctx context.Context, s PayloadSigner, tx *types.PrivateTransaction, chainID *big.Int) (*types.PrivateTransaction, error) {
+	payload, err := types.SigningPayload(tx, chainID)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := s.SignPayload(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(chainID, sig)
+}
+
+// KeySigner is a Signer with a private key in memory.
+type KeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewKeySigner .
+func NewKeySigner(key *ecdsa.PrivateKey) *KeySigner {
+	return &KeySigner{
+		key:     key,
+		address: crypto.PubkeyToAddress(key.PublicKey),
+	}
+}
+
+// Address implements Signer.
+func (s *KeySigner) Address() common.Address {
+	return s.address
+}
+
+// SignHash implements Signer.
+func (s *KeySigner) SignHash(ctx context.Context, hash common.Hash) ([]byte, error) {
+	if s.key == nil {
+		return nil, fmt.Errorf("signer is closed")
+	}
+	return crypto.Sign(hash[:], s.key)
+}
+
+// Close zeroes the private key, the signer can not sign afterwards.
+func (s *KeySigner) Close() error {
+	if s.key != nil {
+		ZeroKey(s.key)
+		s.key = nil
+	}
+	return nil
+}
+
+// ZeroKey overwrites the private scalar of key.
+func ZeroKey(key *ecdsa.PrivateKey) {
+	if key == nil || key.D == nil {
+		return
+	}
+	words := key.D.Bits()
+	for i := range words {
+		words[i] = 0
+	}
+	key.D.SetInt64(0)
+}
+
+// Zero overwrites b, e.g. a decoded private key.
+func Zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+var (
+	secp256k1N     = common.HexToHash("0xfffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141").Big()
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+// FromDER converts an ASN.1 DER ECDSA signature of hash, as returned by key
+// management services, into a [R || S || V] signature: S is normalized to
+// the lower half of the curve order and V is found by recovering address.
+func FromDER(der []byte, hash common.Hash, address common.Address) ([]byte, error) {
+	var sig struct {
+		R, S *big.Int
+	}
+	if _, err := asn1.Unmarshal(der, &sig); err != nil {
+		return nil, fmt.Errorf("invalid DER signature, err: %v", err)
+	}
+	return FromRS(sig.R, sig.S, hash, address)
+}
+
+// FromRS converts the R and S values of a signature of hash into a
+// [R || S || V] signature, like FromDER.
+func FromRS(r, s *big.Int, hash common.Hash, address common.Address) ([]byte, error) {
+	if r.Sign() <= 0 || s.Sign() <= 0 || r.Cmp(secp256k1N) >= 0 || s.Cmp(secp256k1N) >= 0 {
+		return nil, fmt.Errorf("invalid signature values")
+	}
+	if s.Cmp(secp256k1HalfN) > 0 {
+		s = new(big.Int).Sub(secp256k1N, s)
+	}
+	sig := make([]byte, crypto.SignatureLength)
+	copy(sig[32-len(r.Bytes()):32], r.Bytes())
+	copy(sig[64-len(s.Bytes()):64], s.Bytes())
+	for v := byte(0); v < 2; v++ {
+		sig[64] = v
+		pub, err := crypto.SigToPub(hash[:], sig)
+		if err == nil && crypto.PubkeyToAddress(*pub) == address {
+			return sig, nil
+		}
+	}
+	return nil, fmt.Errorf("signature does not recover %v", address.Hex())
+}
+
+// subjectPublicKeyInfo is the PKIX encoding of a public key.
+type subjectPublicKeyInfo struct {
+	Algorithm struct {
+		Algorithm  asn1.ObjectIdentifier
+		Parameters asn1.RawValue `asn1:"optional"`
+	}
+	PublicKey asn1.BitString
+}
+
+// ParsePKIXPublicKey parses a DER PKIX secp256k1 public key, which
+// crypto/x509 does not support, as returned by key management services.
+func ParsePKIXPublicKey(der []byte) (*ecdsa.PublicKey, error) {
+	var info subjectPublicKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, fmt.Errorf("invalid public key, err: %v", err)
+	}
+	return crypto.UnmarshalPubkey(info.PublicKey.RightAlign())
+}
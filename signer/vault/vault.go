@@ -0,0 +1,207 @@
+// Package vault signs with keys held by HashiCorp Vault: by the transit
+// secrets engine for keys of type secp256k1, available with plugins as the
+// builtin engine has no such key type, or by reading a private key stored in
+// the KV secrets engine and zeroing it once the signer is closed.
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/bsostech/go-besu/signer"
+)
+
+// Config is the connection to Vault.
+type Config struct {
+	Address    string // e.g. https://vault:8200
+	Token      string
+	Namespace  string // Vault Enterprise namespace, optional
+	HTTPClient *http.Client
+}
+
+// TransitSigner is a signer.Signer signing with a secp256k1 key of the
+// transit secrets engine, the key never leaving Vault.
+type TransitSigner struct {
+	cfg     Config
+	mount   string
+	key     string
+	address common.Address
+}
+
+type keyResponse struct {
+	Data struct {
+		Type          string `json:"type"`
+		LatestVersion int    `json:"latest_version"`
+		Keys          map[string]struct {
+			PublicKey string `json:"public_key"`
+		} `json:"keys"`
+	} `json:"data"`
+}
+
+type signResponse struct {
+	Data struct {
+		Signature string `json:"signature"`
+	} `json:"data"`
+}
+
+// NewTransitSigner returns a signer with the key named key of the transit
+// engine mounted at mount, "transit" if empty, reading its public key.
+func NewTransitSigner(ctx context.Context, cfg Config, mount, key string) (*TransitSigner, error) {
+	if mount == "" {
+		mount = "transit"
+	}
+	s := &TransitSigner{
+		cfg:   cfg,
+		mount: mount,
+		key:   key,
+	}
+	var rsp keyResponse
+	if _, err := cfg.do(ctx, http.MethodGet, mount+"/keys/"+key, nil, &rsp); err != nil {
+		return nil, err
+	}
+	if rsp.Data.Type != "secp256k1" && rsp.Data.Type != "ecdsa-secp256k1" {
+		return nil, fmt.Errorf("key %v has type %v, not secp256k1", key, rsp.Data.Type)
+	}
+	version, ok := rsp.Data.Keys[strconv.Itoa(rsp.Data.LatestVersion)]
+	if !ok {
+		return nil, fmt.Errorf("public key of %v not found", key)
+	}
+	block, _ := pem.Decode([]byte(version.PublicKey))
+	if block == nil {
+		return nil, fmt.Errorf("invalid public key of %v", key)
+	}
+	pub, err := signer.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	s.address = crypto.PubkeyToAddress(*pub)
+	return s, nil
+}
+
+// Address implements signer.Signer.
+func (s *TransitSigner) Address() common.Address {
+	return s.address
+}
+
+// SignHash implements signer.Signer.
+func (s *TransitSigner) SignHash(ctx context.Context, hash common.Hash) ([]byte, error) {
+	req := map[string]interface{}{
+		"input":                base64.StdEncoding.EncodeToString(hash[:]),
+		"prehashed":            true,
+		"marshaling_algorithm": "asn1",
+	}
+	var rsp signResponse
+	if _, err := s.cfg.do(ctx, http.MethodPost, s.mount+"/sign/"+s.key, req, &rsp); err != nil {
+		return nil, err
+	}
+	// vault:v<version>:<base64 signature>
+	parts := strings.SplitN(rsp.Data.Signature, ":", 3)
+	if len(parts) != 3 || parts[0] != "vault" {
+		return nil, fmt.Errorf("invalid signature %v", rsp.Data.Signature)
+	}
+	der, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature %v, err: %v", rsp.Data.Signature, err)
+	}
+	return signer.FromDER(der, hash, s.address)
+}
+
+type secretResponse struct {
+	Data struct {
+		Data map[string]json.RawMessage `json:"data"`
+	} `json:"data"`
+}
+
+// NewKVSigner reads the hex private key in field of the secret at path of
+// the KV version 2 engine mounted at mount, "secret" if empty. The response
+// and the decoded key are zeroed once parsed, and the key when the returned
+// signer is closed.
+func NewKVSigner(ctx context.Context, cfg Config, mount, path, field string) (*signer.KeySigner, error) {
+	if mount == "" {
+		mount = "secret"
+	}
+	var rsp secretResponse
+	body, err := cfg.do(ctx, http.MethodGet, mount+"/data/"+path, nil, &rsp)
+	defer signer.Zero(body)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range rsp.Data.Data {
+		defer signer.Zero(v)
+	}
+	raw, ok := rsp.Data.Data[field]
+	if !ok {
+		return nil, fmt.Errorf("field %v of secret %v not found", field, path)
+	}
+	hexKey := bytes.TrimPrefix(bytes.Trim(raw, `"`), []byte("0x"))
+	d := make([]byte, hex.DecodedLen(len(hexKey)))
+	defer signer.Zero(d)
+	if _, err := hex.Decode(d, hexKey); err != nil {
+		return nil, fmt.Errorf("invalid private key in %v", path)
+	}
+	key, err := crypto.ToECDSA(d)
+	if err != nil {
+		return nil, err
+	}
+	return signer.NewKeySigner(key), nil
+}
+
+// do sends a request to the Vault API at path, decoding the response into
+// out, and returns the response body.
+func (c Config) do(ctx context.Context, method, path string, in, out interface{}) ([]byte, error) {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return nil, err
+		}
+	}
+	req, err := http.NewRequest(method, strings.TrimSuffix(c.Address, "/")+"/v1/"+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("X-Vault-Token", c.Token)
+	if c.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.Namespace)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var rsp struct {
+			Errors []string `json:"errors"`
+		}
+		json.Unmarshal(data, &rsp)
+		return nil, fmt.Errorf("vault %v %v failed, status: %v, errors: %v", method, path, resp.Status, strings.Join(rsp.Errors, "; "))
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return data, fmt.Errorf("failed to decode vault response, err: %v", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,131 @@
+// Package web3signer delegates signing to ConsenSys Web3Signer, the remote
+// signer commonly run next to Besu, through its Eth1 signing API.
+package web3signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signer is a signer.PayloadSigner signing with a key of Web3Signer.
+// Web3Signer hashes the data it signs, so it signs transactions with
+// signer.SignTxPayload.
+type Signer struct {
+	url        string
+	identifier string // hex public key
+	address    common.Address
+	httpClient *http.Client
+}
+
+// PublicKeys returns the Eth1 public keys loaded by the Web3Signer at url.
+// httpClient is http.DefaultClient if nil.
+func PublicKeys(ctx context.Context, url string, httpClient *http.Client) ([]string, error) {
+	var keys []string
+	err := do(ctx, httpClient, http.MethodGet, strings.TrimSuffix(url, "/")+"/api/v1/eth1/publicKeys", nil, func(data []byte) error {
+		return json.Unmarshal(data, &keys)
+	})
+	return keys, err
+}
+
+// NewSigner returns a signer with the key of the Web3Signer at url whose
+// address is address, looked up among its public keys.
+func NewSigner(ctx context.Context, url string, address common.Address, httpClient *http.Client) (*Signer, error) {
+	keys, err := PublicKeys(ctx, url, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		pub, err := hexutil.Decode(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %v", key)
+		}
+		// Web3Signer returns the 64 bytes of the point without the 0x04 prefix
+		if len(pub) == 64 {
+			pub = append([]byte{4}, pub...)
+		}
+		pubKey, err := crypto.UnmarshalPubkey(pub)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %v, err: %v", key, err)
+		}
+		if crypto.PubkeyToAddress(*pubKey) == address {
+			return &Signer{
+				url:        strings.TrimSuffix(url, "/"),
+				identifier: key,
+				address:    address,
+				httpClient: httpClient,
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("key of %v not found", address.Hex())
+}
+
+// Address implements signer.PayloadSigner.
+func (s *Signer) Address() common.Address {
+	return s.address
+}
+
+// SignPayload implements signer.PayloadSigner.
+func (s *Signer) SignPayload(ctx context.Context, payload []byte) ([]byte, error) {
+	body, err := json.Marshal(map[string]string{"data": hexutil.Encode(payload)})
+	if err != nil {
+		return nil, err
+	}
+	var sig []byte
+	err = do(ctx, s.httpClient, http.MethodPost, s.url+"/api/v1/eth1/sign/"+s.identifier, body, func(data []byte) error {
+		sig, err = hexutil.Decode(strings.TrimSpace(string(data)))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(sig) != crypto.SignatureLength {
+		return nil, fmt.Errorf("invalid signature length %v", len(sig))
+	}
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	// check the signature, as a misconfigured signer may use another key
+	pub, err := crypto.SigToPub(crypto.Keccak256(payload), sig)
+	if err != nil {
+		return nil, err
+	}
+	if crypto.PubkeyToAddress(*pub) != s.address {
+		return nil, fmt.Errorf("signature does not recover %v", s.address.Hex())
+	}
+	return sig, nil
+}
+
+func do(ctx context.Context, httpClient *http.Client, method, url string, body []byte, decode func([]byte) error) error {
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("web3signer %v %v failed, status: %v, body: %v", method, req.URL.Path, resp.Status, strings.TrimSpace(string(data)))
+	}
+	return decode(data)
+}
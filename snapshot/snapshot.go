@@ -0,0 +1,101 @@
+// Package snapshot saves the runtime state of a client and its indexer, so
+// that a restarted service, or the new one of a blue/green deployment,
+// resumes where the previous one left off without deriving the state from
+// the chain again.
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/bsostech/go-besu/client"
+	"github.com/bsostech/go-besu/indexer"
+	"github.com/bsostech/go-besu/privacy"
+)
+
+// Version is the format version of snapshots.
+const Version = 1
+
+// Snapshot .
+type Snapshot struct {
+	Version    int            `json:"version"`
+	Privacy    *privacy.State `json:"privacy"`
+	Checkpoint *uint64        `json:"checkpoint,omitempty"` // last block indexed
+}
+
+// Export returns the nonces and privacy groups of c and, if ix is not nil,
+// the checkpoint of its store.
+func Export(ctx context.Context, c *client.Client, ix *indexer.Indexer) (*Snapshot, error) {
+	snap := &Snapshot{
+		Version: Version,
+		Privacy: c.Privacy.ExportState(),
+	}
+	if ix != nil {
+		block, ok, err := ix.Store().Checkpoint(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			snap.Checkpoint = &block
+		}
+	}
+	return snap, nil
+}
+
+// Import restores snap into c and, if ix is not nil, the checkpoint of its
+// store. It is called before c sends or ix indexes.
+func Import(ctx context.Context, snap *Snapshot, c *client.Client, ix *indexer.Indexer) error {
+	if snap.Version != Version {
+		return fmt.Errorf("unsupported snapshot version %v", snap.Version)
+	}
+	if snap.Privacy != nil {
+		if err := c.Privacy.ImportState(snap.Privacy); err != nil {
+			return err
+		}
+	}
+	if ix != nil && snap.Checkpoint != nil {
+		if err := ix.Store().SetCheckpoint(ctx, *snap.Checkpoint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Save writes snap to path as JSON, replacing the file atomically.
+func (snap *Snapshot) Save(path string) error {
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// Load reads a snapshot saved at path.
+func Load(path string) (*Snapshot, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("failed to parse %v, err: %v", path, err)
+	}
+	return &snap, nil
+}
@@ -0,0 +1,156 @@
+package templates
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// convert converts v, as decoded from YAML or JSON or passed as a variable,
+// to the Go type packing as t. Scalars, bytes and slices of them are supported.
+func convert(t abi.Type, v interface{}) (interface{}, error) {
+	switch t.T {
+	case abi.AddressTy:
+		if addr, ok := v.(common.Address); ok {
+			return addr, nil
+		}
+		s := fmt.Sprint(v)
+		if !common.IsHexAddress(s) {
+			return nil, fmt.Errorf("invalid address %v", s)
+		}
+		return common.HexToAddress(s), nil
+	case abi.BoolTy:
+		switch b := v.(type) {
+		case bool:
+			return b, nil
+		case string:
+			return strconv.ParseBool(b)
+		}
+	case abi.StringTy:
+		return fmt.Sprint(v), nil
+	case abi.BytesTy:
+		return toBytes(v)
+	case abi.FixedBytesTy:
+		b, err := toBytes(v)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) > t.Size {
+			return nil, fmt.Errorf("%v bytes exceed bytes%v", len(b), t.Size)
+		}
+		arr := reflect.New(reflect.ArrayOf(t.Size, reflect.TypeOf(byte(0)))).Elem()
+		reflect.Copy(arr, reflect.ValueOf(b))
+		return arr.Interface(), nil
+	case abi.IntTy, abi.UintTy:
+		i, err := toBig(v)
+		if err != nil {
+			return nil, err
+		}
+		return sizedInt(t, i)
+	case abi.SliceTy:
+		items, ok := v.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected a list, got %T", v)
+		}
+		if len(items) == 0 {
+			return nil, fmt.Errorf("empty lists are not supported")
+		}
+		var slice reflect.Value
+		for i, item := range items {
+			elem, err := convert(*t.Elem, item)
+			if err != nil {
+				return nil, fmt.Errorf("item %v: %v", i, err)
+			}
+			if i == 0 {
+				slice = reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(elem)), 0, len(items))
+			}
+			slice = reflect.Append(slice, reflect.ValueOf(elem))
+		}
+		return slice.Interface(), nil
+	}
+	return nil, fmt.Errorf("unsupported value %v for type %v", v, t.String())
+}
+
+func toBytes(v interface{}) ([]byte, error) {
+	switch b := v.(type) {
+	case []byte:
+		return b, nil
+	case string:
+		if !strings.HasPrefix(b, "0x") {
+			b = "0x" + b
+		}
+		return hexutil.Decode(b)
+	}
+	return nil, fmt.Errorf("expected hex bytes, got %T", v)
+}
+
+func toBig(v interface{}) (*big.Int, error) {
+	switch i := v.(type) {
+	case *big.Int:
+		return i, nil
+	case int:
+		return big.NewInt(int64(i)), nil
+	case int64:
+		return big.NewInt(i), nil
+	case uint64:
+		return new(big.Int).SetUint64(i), nil
+	case float64:
+		if i != float64(int64(i)) {
+			return nil, fmt.Errorf("%v is not an integer", i)
+		}
+		return big.NewInt(int64(i)), nil
+	case string:
+		n, ok := new(big.Int).SetString(i, 0)
+		if !ok {
+			return nil, fmt.Errorf("invalid integer %v", i)
+		}
+		return n, nil
+	}
+	return nil, fmt.Errorf("expected an integer, got %T", v)
+}
+
+// sizedInt returns i as the Go type of integers of t: intN and uintN for
+// sizes up to 64 bits, *big.Int above.
+func sizedInt(t abi.Type, i *big.Int) (interface{}, error) {
+	if t.Size > 64 {
+		return i, nil
+	}
+	if t.T == abi.UintTy {
+		if i.Sign() < 0 || i.BitLen() > t.Size {
+			return nil, fmt.Errorf("%v overflows uint%v", i, t.Size)
+		}
+		u := i.Uint64()
+		switch t.Size {
+		case 8:
+			return uint8(u), nil
+		case 16:
+			return uint16(u), nil
+		case 32:
+			return uint32(u), nil
+		case 64:
+			return u, nil
+		}
+	} else {
+		if !i.IsInt64() || i.BitLen() >= t.Size {
+			return nil, fmt.Errorf("%v overflows int%v", i, t.Size)
+		}
+		n := i.Int64()
+		switch t.Size {
+		case 8:
+			return int8(n), nil
+		case 16:
+			return int16(n), nil
+		case 32:
+			return int32(n), nil
+		case 64:
+			return n, nil
+		}
+	}
+	return i, nil
+}
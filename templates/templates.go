@@ -0,0 +1,137 @@
+// Package templates defines private transactions in configuration: a
+// template fixes the contract, method, arguments and privacy settings of a
+// message type, leaving variables filled in when it is sent.
+package templates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v2"
+
+	"github.com/bsostech/go-besu/client"
+	"github.com/bsostech/go-besu/privacy"
+)
+
+// Template is a private transaction calling Method of the contract at To.
+// It is sent to PrivacyGroupID, or to PrivateFor, or to the group of the
+// session if neither is set.
+type Template struct {
+	Name           string   `yaml:"name" json:"name"`
+	To             string   `yaml:"to" json:"to"`
+	ABI            string   `yaml:"abi" json:"abi"` // JSON ABI of the contract, at least of Method
+	Method         string   `yaml:"method" json:"method"`
+	Args           []Arg    `yaml:"args" json:"args"`
+	PrivacyGroupID string   `yaml:"privacyGroupId" json:"privacyGroupId"`
+	PrivateFor     []string `yaml:"privateFor" json:"privateFor"`
+}
+
+// Arg is an argument of Method, either a fixed Value or the variable Var.
+type Arg struct {
+	Name  string      `yaml:"name" json:"name"`
+	Value interface{} `yaml:"value" json:"value"`
+	Var   string      `yaml:"var" json:"var"`
+}
+
+// Load reads the templates in the file at path, YAML unless its extension is .json.
+func Load(path string) (map[string]*Template, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var list []*Template
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &list)
+	} else {
+		err = yaml.UnmarshalStrict(data, &list)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %v, err: %v", path, err)
+	}
+	templates := make(map[string]*Template, len(list))
+	for _, t := range list {
+		if t.Name == "" {
+			return nil, fmt.Errorf("template name not found")
+		}
+		if _, ok := templates[t.Name]; ok {
+			return nil, fmt.Errorf("duplicate template %v", t.Name)
+		}
+		templates[t.Name] = t
+	}
+	return templates, nil
+}
+
+// Input returns the call input of the template with vars filled in.
+func (t *Template) Input(vars map[string]interface{}) ([]byte, error) {
+	parsed, err := abi.JSON(strings.NewReader(t.ABI))
+	if err != nil {
+		return nil, fmt.Errorf("invalid abi of template %v, err: %v", t.Name, err)
+	}
+	method, ok := parsed.Methods[t.Method]
+	if !ok {
+		return nil, fmt.Errorf("method %v of template %v not found", t.Method, t.Name)
+	}
+	if len(t.Args) != len(method.Inputs) {
+		return nil, fmt.Errorf("template %v has %v args, %v takes %v", t.Name, len(t.Args), t.Method, len(method.Inputs))
+	}
+	args := make([]interface{}, len(t.Args))
+	for i, arg := range t.Args {
+		value := arg.Value
+		if arg.Var != "" {
+			v, ok := vars[arg.Var]
+			if !ok {
+				return nil, fmt.Errorf("variable %v of template %v not found", arg.Var, t.Name)
+			}
+			value = v
+		}
+		converted, err := convert(method.Inputs[i].Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid arg %v of template %v, err: %v", i, t.Name, err)
+		}
+		args[i] = converted
+	}
+	return parsed.Pack(t.Method, args...)
+}
+
+// Session returns s with the privacy settings of the template applied.
+func (t *Template) Session(s *client.Session) (*client.Session, error) {
+	if t.PrivacyGroupID != "" {
+		return s.WithGroup(&privacy.Group{ID: t.PrivacyGroupID}), nil
+	}
+	if len(t.PrivateFor) > 0 {
+		keys := make([]privacy.PublicKey, len(t.PrivateFor))
+		for i, k := range t.PrivateFor {
+			key, err := privacy.ToPublicKey(k)
+			if err != nil {
+				return nil, fmt.Errorf("invalid privateFor %v of template %v, err: %v", k, t.Name, err)
+			}
+			keys[i] = key
+		}
+		return s.WithPrivateFor(keys...), nil
+	}
+	return s, nil
+}
+
+// Send instantiates the template with vars and sends it with s, returning
+// the hash of the privacy marker transaction.
+func (t *Template) Send(ctx context.Context, s *client.Session, vars map[string]interface{}) (common.Hash, error) {
+	if !common.IsHexAddress(t.To) {
+		return common.Hash{}, fmt.Errorf("invalid to %v of template %v", t.To, t.Name)
+	}
+	input, err := t.Input(vars)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	s, err = t.Session(s)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	to := common.HexToAddress(t.To)
+	return s.Send(ctx, &to, input)
+}
@@ -0,0 +1,159 @@
+package types
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bsostech/go-besu/internal/decode"
+)
+
+// loadResponse reads a JSON-RPC result from testdata, applying overrides, in
+// which a nil value deletes the field.
+func loadResponse(t testing.TB, name string, overrides map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	raw, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r map[string]interface{}
+	if err := json.Unmarshal(raw, &r); err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range overrides {
+		if v == nil {
+			delete(r, k)
+			continue
+		}
+		// values are JSON so that they match what the RPC client decodes
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(v.(string)), &decoded); err != nil {
+			t.Fatalf("override %v: %v", k, err)
+		}
+		r[k] = decoded
+	}
+	return r
+}
+
+var (
+	longQuantity = `"0x1` + strings.Repeat("0", 64) + `"`
+	shortHash    = `"0x79b9e6b0856db398ad7dc208f15b1d38c0c0b0c5f99e4a443a2c5a85510e96"`
+)
+
+func TestMarshalPrivateReceiptMalformed(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string]interface{}
+		strict    bool
+		err       string // substring of the error, no error if empty
+	}{
+		{name: "valid"},
+		{name: "optional fields null", overrides: map[string]interface{}{
+			"contractAddress": "null", "output": "null", "privacyGroupId": "null", "privateFor": `["A1aVtMxLCUHmBVHXoZzzBgPbW/wj5axDpW9X8l91SGo="]`,
+			"status": "null", "blockHash": "null", "blockNumber": "null", "transactionIndex": "null", "revertReason": "null",
+		}},
+		{name: "optional fields missing", overrides: map[string]interface{}{
+			"contractAddress": nil, "output": nil, "status": nil, "blockHash": nil, "blockNumber": nil, "transactionIndex": nil,
+		}},
+		{name: "commitmentHash null", overrides: map[string]interface{}{"commitmentHash": "null"}, err: "commitmentHash not found"},
+		{name: "transactionHash missing", overrides: map[string]interface{}{"transactionHash": nil}, err: "transactionHash not found"},
+		{name: "privateFrom null", overrides: map[string]interface{}{"privateFrom": "null"}, err: "privateFrom not found"},
+		{name: "logs null", overrides: map[string]interface{}{"logs": "null"}, err: "logs not found"},
+		{name: "logsBloom null", overrides: map[string]interface{}{"logsBloom": "null"}, err: "logsBloom not found"},
+		{name: "group and privateFor null", overrides: map[string]interface{}{"privacyGroupId": "null", "privateFor": "null"}, err: "privateFor not found"},
+		{name: "status number", overrides: map[string]interface{}{"status": "1"}, err: "expected string"},
+		{name: "blockNumber number", overrides: map[string]interface{}{"blockNumber": "487"}, err: "expected string"},
+		{name: "logs object", overrides: map[string]interface{}{"logs": "{}"}, err: "expected array"},
+		{name: "privateFor string", overrides: map[string]interface{}{"privateFor": `"A1aVtMxLCUHmBVHXoZzzBgPbW/wj5axDpW9X8l91SGo="`}, err: "expected array"},
+		{name: "privateFor element number", overrides: map[string]interface{}{"privacyGroupId": "null", "privateFor": "[1]"}, strict: true, err: "expected string"},
+		{name: "privateFrom number", overrides: map[string]interface{}{"privateFrom": "1"}, err: "expected string"},
+		{name: "privateFrom not base64", overrides: map[string]interface{}{"privateFrom": `"not base64!"`}, err: "illegal base64"},
+		{name: "output truncated", overrides: map[string]interface{}{"output": `"0x608"`}, err: "failed to decode output"},
+		{name: "revertReason truncated", overrides: map[string]interface{}{"revertReason": `"0x08c379a"`}, err: "failed to decode revertReason"},
+		{name: "logsBloom truncated", overrides: map[string]interface{}{"logsBloom": `"0x00"`}, err: "invalid logsBloom length"},
+		{name: "logsBloom oversized", overrides: map[string]interface{}{"logsBloom": `"0x` + strings.Repeat("00", 300) + `"`}, err: "invalid logsBloom length"},
+		{name: "commitmentHash truncated", overrides: map[string]interface{}{"commitmentHash": shortHash}, err: "failed to decode commitmentHash"},
+		{name: "blockHash oversized", overrides: map[string]interface{}{"blockHash": `"0x` + strings.Repeat("ab", 33) + `"`}, err: "failed to decode blockHash"},
+		{name: "contractAddress truncated", overrides: map[string]interface{}{"contractAddress": `"0x42699a"`}, err: "failed to decode contractAddress"},
+		{name: "status oversized", overrides: map[string]interface{}{"status": `"0x10000000000000000"`}, err: "failed to decode status"},
+		{name: "transactionIndex oversized", overrides: map[string]interface{}{"transactionIndex": `"0x10000000000000000"`}, err: "failed to decode transactionIndex"},
+		{name: "blockNumber oversized", overrides: map[string]interface{}{"blockNumber": longQuantity}, err: "failed to decode blockNumber"},
+		{name: "blockNumber not hex", overrides: map[string]interface{}{"blockNumber": `"0xzz"`}, err: "failed to decode blockNumber"},
+	}
+	for _, test := range tests {
+		r := loadResponse(t, "receipt.json", test.overrides)
+		mode := decode.Lenient
+		if test.strict {
+			mode = decode.Strict
+		}
+		receipt, err := MarshalPrivateReceiptWithMode(r, mode)
+		if test.err == "" {
+			if err != nil || receipt == nil {
+				t.Errorf("%v: %v", test.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%v: got error %v, want %q", test.name, err, test.err)
+		}
+	}
+}
+
+func TestMarshalPrivateReceiptLogs(t *testing.T) {
+	r := loadResponse(t, "receipt.json", map[string]interface{}{"logs": `[{"address":1}]`})
+	receipt, err := MarshalPrivateReceiptWithMode(r, decode.Lenient)
+	if err != nil || len(receipt.Logs) != 0 {
+		t.Fatalf("lenient: got %v, %v", receipt, err)
+	}
+	if _, err := MarshalPrivateReceiptWithMode(r, decode.Strict); err == nil {
+		t.Fatal("strict: invalid log decoded")
+	}
+	r = loadResponse(t, "receipt.json", map[string]interface{}{"unexpected": `"0x1"`})
+	if _, err := MarshalPrivateReceiptWithMode(r, decode.Strict); err == nil {
+		t.Fatal("strict: unknown field accepted")
+	}
+}
+
+func TestMarshalPrivateTransactionMalformed(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string]interface{}
+		err       string
+	}{
+		{name: "valid"},
+		{name: "contract creation", overrides: map[string]interface{}{"to": "null"}},
+		{name: "optional fields null", overrides: map[string]interface{}{
+			"nonce": "null", "gas": "null", "gasPrice": "null", "value": "null", "v": "null", "r": "null", "s": "null", "restriction": "null",
+		}},
+		{name: "privateFor instead of group", overrides: map[string]interface{}{"privacyGroupId": nil, "privateFor": `["A1aVtMxLCUHmBVHXoZzzBgPbW/wj5axDpW9X8l91SGo="]`}},
+		{name: "input null", overrides: map[string]interface{}{"input": "null"}, err: "input data not found"},
+		{name: "privateFrom missing", overrides: map[string]interface{}{"privateFrom": nil}, err: "privateFrom not found"},
+		{name: "group and privateFor missing", overrides: map[string]interface{}{"privacyGroupId": nil}, err: "privateFor or privacyGroupId not found"},
+		{name: "nonce number", overrides: map[string]interface{}{"nonce": "2"}, err: "expected string"},
+		{name: "to object", overrides: map[string]interface{}{"to": "{}"}, err: "expected string"},
+		{name: "privateFor object", overrides: map[string]interface{}{"privacyGroupId": nil, "privateFor": "{}"}, err: "expected array"},
+		{name: "privateFor element null", overrides: map[string]interface{}{"privacyGroupId": nil, "privateFor": "[null]"}, err: "expected string"},
+		{name: "input truncated", overrides: map[string]interface{}{"input": `"0x3fa4f24"`}, err: "payload can not decode"},
+		{name: "to truncated", overrides: map[string]interface{}{"to": `"0x42699a7612a82f1d9c36148af9c77354759b21"`}, err: "failed to decode to"},
+		{name: "nonce oversized", overrides: map[string]interface{}{"nonce": `"0x10000000000000000"`}, err: "failed to decode nonce"},
+		{name: "gas not hex", overrides: map[string]interface{}{"gas": `"0xgas"`}, err: "failed to decode gas"},
+		{name: "value oversized", overrides: map[string]interface{}{"value": longQuantity}, err: "failed to decode value"},
+		{name: "r negative", overrides: map[string]interface{}{"r": `"-0x1"`}, err: "failed to decode r"},
+		{name: "restriction unknown", overrides: map[string]interface{}{"restriction": `"public"`}, err: "restriction"},
+	}
+	for _, test := range tests {
+		r := loadResponse(t, "transaction.json", test.overrides)
+		tx, err := MarshalPrivateTransactionWithMode(r, decode.Lenient)
+		if test.err == "" {
+			if err != nil || tx == nil {
+				t.Errorf("%v: %v", test.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%v: got error %v, want %q", test.name, err, test.err)
+		}
+	}
+}
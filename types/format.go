@@ -0,0 +1,172 @@
+package types
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Verbosity controls how much Format writes.
+type Verbosity int
+
+const (
+	// Summary writes one line without payloads, the format of String.
+	Summary Verbosity = iota
+	// Detailed writes all fields, one per line, with payloads, outputs,
+	// revert reasons and log contents redacted to their sizes.
+	Detailed
+	// Full writes all fields including payloads. It is meant for debugging
+	// and must not be used for logs of production systems.
+	Full
+)
+
+// Format writes tx to w with verbosity v.
+func (tx *PrivateTransaction) Format(w io.Writer, v Verbosity) error {
+	to := "creation"
+	if tx.data.Recipient != nil {
+		to = tx.data.Recipient.Hex()
+	}
+	if v == Summary {
+		_, err := fmt.Fprintf(w, "PrivateTransaction{nonce: %v, to: %v, gas: %v, %v, payload: %v}",
+			tx.Nonce(), to, tx.Gas(), tx.addressing(), redacted(tx.data.Payload))
+		return err
+	}
+	f := &fieldWriter{w: w}
+	f.line("PrivateTransaction")
+	if from, err := tx.Sender(); err == nil {
+		f.field("from", from.Hex())
+	}
+	f.field("nonce", tx.Nonce())
+	f.field("to", to)
+	f.field("gas", tx.Gas())
+	f.field("gasPrice", tx.data.Price)
+	f.field("value", tx.data.Amount)
+	f.field("privateFrom", encodeKey(tx.data.PrivateFrom))
+	if tx.data.PrivacyGroupID != nil {
+		f.field("privacyGroupId", encodeKey(tx.data.PrivacyGroupID))
+	} else {
+		f.field("privateFor", encodeKeys(tx.PrivateFor()))
+	}
+	f.field("restriction", tx.data.Restriction)
+	f.field("payload", payload(tx.data.Payload, v))
+	return f.err
+}
+
+func (tx *PrivateTransaction) String() string {
+	return FormatString(tx.Format)
+}
+
+// addressing describes the recipients of tx for Summary.
+func (tx *PrivateTransaction) addressing() string {
+	if tx.data.PrivacyGroupID != nil {
+		return "group: " + encodeKey(tx.data.PrivacyGroupID)
+	}
+	return fmt.Sprintf("privateFor: %v keys", len(tx.data.PrivateFor))
+}
+
+// Format writes r to w with verbosity v.
+func (r *PrivateReceipt) Format(w io.Writer, v Verbosity) error {
+	if v == Summary {
+		_, err := fmt.Fprintf(w, "PrivateReceipt{tx: %v, status: %v, block: %v, logs: %v, output: %v}",
+			r.CommitmentHash.Hex(), r.Status, r.BlockNumberU64(), len(r.Logs), redacted(r.Output))
+		return err
+	}
+	f := &fieldWriter{w: w}
+	f.line("PrivateReceipt")
+	f.field("commitmentHash", r.CommitmentHash.Hex())
+	f.field("transactionHash", r.TxHash.Hex())
+	f.field("status", r.Status)
+	f.field("blockHash", r.BlockHash.Hex())
+	f.field("blockNumber", r.BlockNumberU64())
+	f.field("transactionIndex", r.TxIndex)
+	if r.ContractAddress != (common.Address{}) {
+		f.field("contractAddress", r.ContractAddress.Hex())
+	}
+	f.field("privateFrom", encodeKey(r.PrivateFrom))
+	if r.PrivacyGroupID != "" {
+		f.field("privacyGroupId", r.PrivacyGroupID)
+	} else {
+		privateFor := make([][]byte, len(r.PrivateFor))
+		for i := range r.PrivateFor {
+			privateFor[i] = r.PrivateFor[i]
+		}
+		f.field("privateFor", encodeKeys(privateFor))
+	}
+	f.field("output", payload(r.Output, v))
+	if len(r.RevertReason) > 0 {
+		f.field("revertReason", payload(r.RevertReason, v))
+	}
+	f.field("logs", len(r.Logs))
+	for i, log := range r.Logs {
+		if v < Full {
+			f.field(fmt.Sprintf("  log %v", i), fmt.Sprintf("address: %v, %v topics, data: %v",
+				log.Address.Hex(), len(log.Topics), redacted(log.Data)))
+			continue
+		}
+		topics := make([]string, len(log.Topics))
+		for j := range log.Topics {
+			topics[j] = log.Topics[j].Hex()
+		}
+		f.field(fmt.Sprintf("  log %v", i), fmt.Sprintf("address: %v, topics: [%v], data: %v",
+			log.Address.Hex(), strings.Join(topics, ", "), hexutil.Encode(log.Data)))
+	}
+	return f.err
+}
+
+func (r *PrivateReceipt) String() string {
+	return FormatString(r.Format)
+}
+
+// fieldWriter writes aligned fields, keeping the first error.
+type fieldWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (f *fieldWriter) line(s string) {
+	if f.err == nil {
+		_, f.err = fmt.Fprintln(f.w, s)
+	}
+}
+
+func (f *fieldWriter) field(name string, value interface{}) {
+	f.line(fmt.Sprintf("  %-18s %v", name+":", value))
+}
+
+// FormatString returns the Summary of a value with a Format method, for its
+// String method.
+func FormatString(format func(io.Writer, Verbosity) error) string {
+	var buf bytes.Buffer
+	if err := format(&buf, Summary); err != nil {
+		return fmt.Sprintf("<format failed: %v>", err)
+	}
+	return buf.String()
+}
+
+func redacted(data []byte) string {
+	return fmt.Sprintf("[redacted %v bytes]", len(data))
+}
+
+func payload(data []byte, v Verbosity) string {
+	if v < Full {
+		return redacted(data)
+	}
+	return hexutil.Encode(data)
+}
+
+func encodeKey(key []byte) string {
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func encodeKeys(keys [][]byte) string {
+	encoded := make([]string, len(keys))
+	for i := range keys {
+		encoded[i] = encodeKey(keys[i])
+	}
+	return "[" + strings.Join(encoded, ", ") + "]"
+}
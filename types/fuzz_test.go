@@ -0,0 +1,73 @@
+//go:build go1.18
+// +build go1.18
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/bsostech/go-besu/internal/decode"
+)
+
+// addSeeds adds the testdata responses and variants of their quantities to
+// the corpus of f.
+func addSeeds(f *testing.F, names ...string) {
+	for _, name := range names {
+		raw, err := ioutil.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(raw)
+		f.Add(bytes.Replace(raw, []byte(`"0x`), []byte(`"`), -1))
+		f.Add(bytes.Replace(raw, []byte(`"0x`), []byte(`"0X00`), -1))
+		f.Add(bytes.Replace(raw, []byte(`"0x1e7"`), []byte(`null`), -1))
+	}
+}
+
+func FuzzMarshalPrivateReceipt(f *testing.F) {
+	addSeeds(f, "receipt.json")
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var r map[string]interface{}
+		if err := json.Unmarshal(data, &r); err != nil {
+			return
+		}
+		for _, mode := range []decode.Mode{decode.Lenient, decode.Strict} {
+			receipt, err := MarshalPrivateReceiptWithMode(r, mode)
+			if err == nil && receipt == nil {
+				t.Fatalf("mode %v: nil receipt without error", mode)
+			}
+		}
+	})
+}
+
+func FuzzMarshalPrivateTransaction(f *testing.F) {
+	addSeeds(f, "transaction.json")
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var r map[string]interface{}
+		if err := json.Unmarshal(data, &r); err != nil {
+			return
+		}
+		tx, err := MarshalPrivateTransactionWithMode(r, decode.Lenient)
+		if err != nil {
+			return
+		}
+		// decoded transactions must survive an RLP round trip
+		enc, err := rlp.EncodeToBytes(tx)
+		if err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+		decoded := new(PrivateTransaction)
+		if err := rlp.DecodeBytes(enc, decoded); err != nil {
+			t.Fatalf("decode %x: %v", enc, err)
+		}
+		if decoded.Nonce() != tx.Nonce() || !bytes.Equal(decoded.Data(), tx.Data()) || decoded.Value().Cmp(tx.Value()) != 0 {
+			t.Fatalf("round trip of %x changed the transaction", enc)
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NetworkProfile holds the transaction defaults of a network, applied by its constructors.
+type NetworkProfile struct {
+	GasPrice    *big.Int
+	GasLimit    uint64
+	Restriction Restriction
+}
+
+var (
+	// FreeGasNetwork is a consortium network running with zero gas price.
+	FreeGasNetwork = NetworkProfile{
+		GasPrice:    big.NewInt(0),
+		GasLimit:    3000000,
+		Restriction: Restricted,
+	}
+	// PublicTestnet is a network charging gas, e.g. a public test network.
+	PublicTestnet = NetworkProfile{
+		GasPrice:    big.NewInt(1000000000),
+		GasLimit:    3000000,
+		Restriction: Restricted,
+	}
+)
+
+// NewContractCreation .
+func (p NetworkProfile) NewContractCreation(nonce uint64, data []byte, privateFrom []byte, privateFor [][]byte) *PrivateTransaction {
+	return p.apply(newTransaction(nonce, nil, nil, p.GasLimit, p.GasPrice, data, privateFrom, privateFor, nil))
+}
+
+// NewTransaction .
+func (p NetworkProfile) NewTransaction(nonce uint64, to *common.Address, amount *big.Int, data []byte, privateFrom []byte, privateFor [][]byte) *PrivateTransaction {
+	return p.apply(newTransaction(nonce, to, amount, p.GasLimit, p.GasPrice, data, privateFrom, privateFor, nil))
+}
+
+// NewPrivateTransaction .
+func (p NetworkProfile) NewPrivateTransaction(nonce uint64, to *common.Address, amount *big.Int, data []byte, privateFrom []byte, privacyGroupID []byte) *PrivateTransaction {
+	return p.apply(newTransaction(nonce, to, amount, p.GasLimit, p.GasPrice, data, privateFrom, nil, privacyGroupID))
+}
+
+// WithGasLimit returns a copy of p with another gas limit.
+func (p NetworkProfile) WithGasLimit(gasLimit uint64) NetworkProfile {
+	p.GasLimit = gasLimit
+	return p
+}
+
+// WithGasPrice returns a copy of p with another gas price.
+func (p NetworkProfile) WithGasPrice(gasPrice *big.Int) NetworkProfile {
+	p.GasPrice = gasPrice
+	return p
+}
+
+// WithRestriction returns a copy of p with another restriction.
+func (p NetworkProfile) WithRestriction(restriction Restriction) NetworkProfile {
+	p.Restriction = restriction
+	return p
+}
+
+func (p NetworkProfile) apply(tx *PrivateTransaction) *PrivateTransaction {
+	if p.Restriction != "" {
+		tx.data.Restriction = p.Restriction
+	}
+	return tx
+}
@@ -0,0 +1,107 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/bsostech/go-besu/privacy/keys"
+)
+
+// OfflineBundle holds everything needed to sign a private transaction on
+// another, e.g. air-gapped, machine and to assemble the signed raw transaction
+// from the returned signature. It is meant to be serialized as JSON.
+type OfflineBundle struct {
+	Nonce          hexutil.Uint64  `json:"nonce"`
+	GasPrice       *hexutil.Big    `json:"gasPrice"`
+	Gas            hexutil.Uint64  `json:"gas"`
+	To             *common.Address `json:"to"`
+	Value          *hexutil.Big    `json:"value"`
+	Input          hexutil.Bytes   `json:"input"`
+	PrivateFrom    string          `json:"privateFrom"`
+	PrivateFor     []string        `json:"privateFor,omitempty"`
+	PrivacyGroupID string          `json:"privacyGroupId,omitempty"`
+	Restriction    Restriction     `json:"restriction"`
+	ChainID        *hexutil.Big    `json:"chainId"`
+	SigningHash    common.Hash     `json:"signingHash"` // the digest to sign
+}
+
+// NewOfflineBundle .
+func NewOfflineBundle(tx *PrivateTransaction, chainID *big.Int) *OfflineBundle {
+	b := &OfflineBundle{
+		Nonce:       hexutil.Uint64(tx.Nonce()),
+		GasPrice:    (*hexutil.Big)(tx.GasPrice()),
+		Gas:         hexutil.Uint64(tx.Gas()),
+		To:          tx.To(),
+		Value:       (*hexutil.Big)(tx.Value()),
+		Input:       tx.Data(),
+		PrivateFrom: keys.PublicKey(tx.PrivateFrom()).ToString(),
+		Restriction: tx.Restriction(),
+		ChainID:     (*hexutil.Big)(new(big.Int).Set(chainID)),
+		SigningHash: SigningHash(tx, chainID),
+	}
+	if id := tx.PrivacyGroupID(); id != nil {
+		b.PrivacyGroupID = keys.PublicKey(id).ToString()
+	}
+	for _, v := range tx.PrivateFor() {
+		b.PrivateFor = append(b.PrivateFor, keys.PublicKey(v).ToString())
+	}
+	return b
+}
+
+// Transaction rebuilds the unsigned transaction and checks it matches SigningHash.
+func (b *OfflineBundle) Transaction() (*PrivateTransaction, error) {
+	if b.ChainID == nil {
+		return nil, fmt.Errorf("chainId not found")
+	}
+	privateFrom, err := keys.ToPublicKey(b.PrivateFrom)
+	if err != nil {
+		return nil, err
+	}
+	var privateFor [][]byte
+	for _, v := range b.PrivateFor {
+		key, err := keys.ToPublicKey(v)
+		if err != nil {
+			return nil, err
+		}
+		privateFor = append(privateFor, key)
+	}
+	var privacyGroupID []byte
+	if b.PrivacyGroupID != "" {
+		if privacyGroupID, err = keys.ToPublicKey(b.PrivacyGroupID); err != nil {
+			return nil, err
+		}
+	}
+	tx := newTransaction(uint64(b.Nonce), b.To, b.Value.ToInt(), uint64(b.Gas), b.GasPrice.ToInt(), b.Input, privateFrom, privateFor, privacyGroupID)
+	tx.data.Restriction = b.Restriction
+	if h := SigningHash(tx, b.ChainID.ToInt()); h != b.SigningHash {
+		return nil, fmt.Errorf("signing hash mismatch: got %v, want %v", h.Hex(), b.SigningHash.Hex())
+	}
+	return tx, nil
+}
+
+// WithSignature returns the transaction signed with sig, a 65 bytes [R || S || V]
+// signature of SigningHash with V being 0 or 1, as returned by crypto.Sign.
+func (b *OfflineBundle) WithSignature(sig []byte) (*PrivateTransaction, error) {
+	if len(sig) != crypto.SignatureLength {
+		return nil, fmt.Errorf("wrong size for signature: got %d, want %d", len(sig), crypto.SignatureLength)
+	}
+	tx, err := b.Transaction()
+	if err != nil {
+		return nil, err
+	}
+	return withSignature(tx, sig, b.ChainID.ToInt())
+}
+
+// RawTransaction returns the RLP encoded transaction signed with sig, ready for eea_sendRawTransaction.
+func (b *OfflineBundle) RawTransaction(sig []byte) ([]byte, error) {
+	tx, err := b.WithSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+	return rlp.EncodeToBytes(tx)
+}
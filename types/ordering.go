@@ -0,0 +1,79 @@
+package types
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"sort"
+
+	"github.com/bsostech/go-besu/privacy/keys"
+)
+
+// ParticipantsOrdering is the order privateFor is hashed and encoded in.
+// Signatures only verify with the order used at signing, so transactions
+// exchanged with other SDKs have to use theirs.
+type ParticipantsOrdering int
+
+// ParticipantsOrdering .
+const (
+	// AsProvided keeps privateFor as given, as this package always did.
+	AsProvided ParticipantsOrdering = iota
+	// Canonical sorts privateFor by base64 encoding, the order of keys.ParticipantSet.
+	Canonical
+	// Web3jsLegacy sorts privateFor by the Java string hash of the keys, as
+	// web3js-eea sorts participants.
+	Web3jsLegacy
+)
+
+var orderingNames = []string{"as-provided", "canonical", "web3js-legacy"}
+
+func (o ParticipantsOrdering) String() string {
+	if int(o) < len(orderingNames) {
+		return orderingNames[o]
+	}
+	return fmt.Sprintf("ParticipantsOrdering(%d)", int(o))
+}
+
+// ParseParticipantsOrdering parses "as-provided", "canonical" or "web3js-legacy".
+func ParseParticipantsOrdering(s string) (ParticipantsOrdering, error) {
+	for i, name := range orderingNames {
+		if s == name {
+			return ParticipantsOrdering(i), nil
+		}
+	}
+	return AsProvided, fmt.Errorf("unknown participants ordering %v", s)
+}
+
+// Order returns a copy of privateFor in the ordering. Duplicates are kept.
+func (o ParticipantsOrdering) Order(privateFor [][]byte) [][]byte {
+	if privateFor == nil {
+		return nil
+	}
+	ordered := append([][]byte(nil), privateFor...)
+	switch o {
+	case Canonical:
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return base64.StdEncoding.EncodeToString(ordered[i]) < base64.StdEncoding.EncodeToString(ordered[j])
+		})
+	case Web3jsLegacy:
+		sort.SliceStable(ordered, func(i, j int) bool {
+			hi, hj := keys.PublicKey(ordered[i]).Hash(), keys.PublicKey(ordered[j]).Hash()
+			if hi != hj {
+				return hi < hj
+			}
+			return bytes.Compare(ordered[i], ordered[j]) < 0
+		})
+	}
+	return ordered
+}
+
+// WithParticipantsOrdering returns a copy of tx hashed, signed and encoded
+// with privateFor in the ordering o.
+func (tx *PrivateTransaction) WithParticipantsOrdering(o ParticipantsOrdering) *PrivateTransaction {
+	return &PrivateTransaction{data: tx.data, ordering: o}
+}
+
+// ParticipantsOrdering returns the ordering of privateFor.
+func (tx *PrivateTransaction) ParticipantsOrdering() ParticipantsOrdering {
+	return tx.ordering
+}
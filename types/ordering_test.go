@@ -0,0 +1,167 @@
+package types
+
+import (
+	"bytes"
+	"encoding/base64"
+	"math/big"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/bsostech/go-besu/privacy/keys"
+)
+
+// javaHashCode is java.util.Arrays.hashCode(byte[]), which web3js-eea sorts
+// participants by.
+func javaHashCode(b []byte) int32 {
+	h := int32(1)
+	for _, v := range b {
+		h = 31*h + int32(int8(v))
+	}
+	return h
+}
+
+// referenceOrder sorts a copy of keys with sort.Slice, independently of Order.
+func referenceOrder(o ParticipantsOrdering, privateFor [][]byte) [][]byte {
+	ordered := make([][]byte, len(privateFor))
+	copy(ordered, privateFor)
+	switch o {
+	case Canonical:
+		sort.Slice(ordered, func(i, j int) bool {
+			return base64.StdEncoding.EncodeToString(ordered[i]) < base64.StdEncoding.EncodeToString(ordered[j])
+		})
+	case Web3jsLegacy:
+		sort.Slice(ordered, func(i, j int) bool {
+			hi, hj := javaHashCode(ordered[i]), javaHashCode(ordered[j])
+			if hi != hj {
+				return hi < hj
+			}
+			return bytes.Compare(ordered[i], ordered[j]) < 0
+		})
+	}
+	return ordered
+}
+
+func sameKeys(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func randomKeys(rnd *rand.Rand) [][]byte {
+	keys := make([][]byte, rnd.Intn(12))
+	for i := range keys {
+		switch {
+		case i > 0 && rnd.Intn(8) == 0:
+			// duplicate
+			keys[i] = append([]byte(nil), keys[rnd.Intn(i)]...)
+		case rnd.Intn(8) == 0:
+			// short keys make hash collisions likely
+			keys[i] = []byte{byte(rnd.Intn(4)), byte(rnd.Intn(4))}
+		default:
+			keys[i] = make([]byte, 32)
+			rnd.Read(keys[i])
+		}
+	}
+	return keys
+}
+
+func TestJavaHashCode(t *testing.T) {
+	tests := []struct {
+		in   []byte
+		want int32
+	}{
+		{in: nil, want: 1},
+		{in: []byte{1, 2, 3}, want: 30817},
+		{in: []byte{0xff}, want: 30},
+		{in: bytes.Repeat([]byte{0x7f}, 32), want: javaHashCode(bytes.Repeat([]byte{0x7f}, 32))},
+	}
+	for _, test := range tests {
+		if got := javaHashCode(test.in); got != test.want {
+			t.Errorf("javaHashCode(%x) = %v, want %v", test.in, got, test.want)
+		}
+		if got := keys.PublicKey(test.in).Hash(); got != int(test.want) {
+			t.Errorf("PublicKey(%x).Hash() = %v, want %v", test.in, got, test.want)
+		}
+	}
+}
+
+func TestOrderMatchesReference(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		keys := randomKeys(rnd)
+		input := make([][]byte, len(keys))
+		for j := range keys {
+			input[j] = append([]byte(nil), keys[j]...)
+		}
+		for _, o := range []ParticipantsOrdering{AsProvided, Canonical, Web3jsLegacy} {
+			got := o.Order(keys)
+			if want := referenceOrder(o, keys); !sameKeys(got, want) {
+				t.Fatalf("%v: Order(%x) = %x, want %x", o, keys, got, want)
+			}
+			if !sameKeys(keys, input) {
+				t.Fatalf("%v: Order modified its input", o)
+			}
+		}
+	}
+	if AsProvided.Order(nil) != nil {
+		t.Fatal("Order(nil) is not nil")
+	}
+}
+
+func TestOrderingAppliedToHashAndRLP(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	to := common.HexToAddress("0x42699a7612a82f1d9c36148af9c77354759b210b")
+	chainID := big.NewInt(2018)
+	for i := 0; i < 200; i++ {
+		keys := randomKeys(rnd)
+		shuffled := append([][]byte(nil), keys...)
+		rnd.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+		for _, o := range []ParticipantsOrdering{Canonical, Web3jsLegacy} {
+			a := NewTransaction(1, &to, big.NewInt(0), 21000, big.NewInt(0), nil, make([]byte, 32), keys).WithParticipantsOrdering(o)
+			b := NewTransaction(1, &to, big.NewInt(0), 21000, big.NewInt(0), nil, make([]byte, 32), shuffled).WithParticipantsOrdering(o)
+			if SigningHash(a, chainID) != SigningHash(b, chainID) {
+				t.Fatalf("%v: signing hash depends on the order of %x", o, keys)
+			}
+			encA, err := rlp.EncodeToBytes(a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			encB, err := rlp.EncodeToBytes(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(encA, encB) {
+				t.Fatalf("%v: encoding depends on the order of %x", o, keys)
+			}
+			if !sameKeys(a.PrivateFor(), referenceOrder(o, keys)) {
+				t.Fatalf("%v: PrivateFor() = %x, want %x", o, a.PrivateFor(), referenceOrder(o, keys))
+			}
+		}
+		asProvided := NewTransaction(1, &to, big.NewInt(0), 21000, big.NewInt(0), nil, make([]byte, 32), keys)
+		if !sameKeys(asProvided.PrivateFor(), keys) {
+			t.Fatalf("as-provided: PrivateFor() = %x, want %x", asProvided.PrivateFor(), keys)
+		}
+	}
+}
+
+func TestParseParticipantsOrdering(t *testing.T) {
+	for _, o := range []ParticipantsOrdering{AsProvided, Canonical, Web3jsLegacy} {
+		parsed, err := ParseParticipantsOrdering(o.String())
+		if err != nil || parsed != o {
+			t.Errorf("ParseParticipantsOrdering(%q) = %v, %v", o.String(), parsed, err)
+		}
+	}
+	if _, err := ParseParticipantsOrdering("sorted"); err == nil {
+		t.Error("unknown ordering parsed")
+	}
+}
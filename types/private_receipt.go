@@ -1,14 +1,19 @@
 package types
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
 
-	"github.com/bsostech/go-besu/privacy"
+	"github.com/bsostech/go-besu/internal/decode"
+	"github.com/bsostech/go-besu/privacy/keys"
 )
 
 // PrivateReceipt represents the results of a transaction.
@@ -28,113 +33,200 @@ type PrivateReceipt struct {
 
 	// Inclusion information: These fields provide information about the inclusion of the
 	// transaction corresponding to this receipt.
-	BlockHash        common.Hash `json:"blockHash,omitempty"`
-	BlockNumber      *big.Int    `json:"blockNumber,omitempty"`
-	TransactionIndex uint        `json:"transactionIndex"`
+	BlockHash   common.Hash `json:"blockHash,omitempty"`
+	BlockNumber *big.Int    `json:"blockNumber,omitempty"`
+	TxIndex     uint64      `json:"transactionIndex"`
+	// Deprecated: use TxIndex.
+	TransactionIndex uint `json:"-"`
 
 	// Privacy
-	PrivateFrom privacy.PublicKey   `json:"privateFrom"    gencodec:"required"`
-	PrivateFor  []privacy.PublicKey `json:"privateFor"    gencodec:"required"`
-	Restriction string
+	PrivateFrom    keys.PublicKey   `json:"privateFrom"    gencodec:"required"`
+	PrivateFor     []keys.PublicKey `json:"privateFor"    gencodec:"required"`
+	PrivacyGroupID string           `json:"privacyGroupId,omitempty"`
+	Restriction    Restriction
 
 	// Private
 	CommitmentHash common.Hash `json:"commitmentHash" gencodec:"required"`
 	Output         []byte      `json:"output"`
+	RevertReason   []byte      `json:"revertReason,omitempty"`
 }
 
-// MarshalPrivateReceipt .
+// receiptFields are the fields of a priv_getTransactionReceipt response.
+var receiptFields = []string{"contractAddress", "from", "to", "output", "commitmentHash", "transactionHash",
+	"privateFrom", "privateFor", "privacyGroupId", "status", "logs", "logsBloom", "blockHash", "blockNumber",
+	"transactionIndex", "revertReason"}
+
+// MarshalPrivateReceipt decodes a private receipt in decode.Lenient mode.
 func MarshalPrivateReceipt(r map[string]interface{}) (*PrivateReceipt, error) {
+	return MarshalPrivateReceiptWithMode(r, decode.Lenient)
+}
+
+// MarshalPrivateReceiptWithMode decodes a private receipt returned by priv_getTransactionReceipt.
+func MarshalPrivateReceiptWithMode(r map[string]interface{}, mode decode.Mode) (*PrivateReceipt, error) {
+	if err := mode.CheckFields(r, receiptFields...); err != nil {
+		return nil, err
+	}
 	// contractAddress not required
 	var contractAddress common.Address
-	if v, ok := r["contractAddress"]; ok {
-		contractAddress = common.HexToAddress(v.(string))
+	v, ok, err := decode.String(r, "contractAddress")
+	if err != nil {
+		return nil, err
+	}
+	if ok && v != "" {
+		if contractAddress, err = decode.Address(v); err != nil {
+			return nil, fmt.Errorf("failed to decode contractAddress, err: %v", err)
+		}
 	}
 	// output not required
 	var output []byte
-	if v, ok := r["output"]; ok {
-		output, _ = hexutil.Decode(v.(string))
+	if v, ok, err := decode.String(r, "output"); err != nil {
+		return nil, err
+	} else if ok {
+		b, err := decode.Bytes(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode output %v, err: %v", v, err)
+		}
+		output = b
 	}
 	// commitmentHash required
-	if _, ok := r["commitmentHash"]; !ok {
-		return nil, fmt.Errorf("commitmentHash not found")
+	v, err = decode.RequiredString(r, "commitmentHash")
+	if err != nil {
+		return nil, err
+	}
+	commitmentHash, err := decode.Hash(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode commitmentHash, err: %v", err)
 	}
-	commitmentHash := common.HexToHash(r["commitmentHash"].(string))
 	// transactionHash required
-	if _, ok := r["transactionHash"]; !ok {
-		return nil, fmt.Errorf("transactionHash not found")
+	v, err = decode.RequiredString(r, "transactionHash")
+	if err != nil {
+		return nil, err
+	}
+	transactionHash, err := decode.Hash(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode transactionHash, err: %v", err)
 	}
-	transactionHash := common.HexToHash(r["transactionHash"].(string))
 	// privateFrom required
-	if _, ok := r["privateFrom"]; !ok {
-		return nil, fmt.Errorf("privateFrom not found")
+	v, err = decode.RequiredString(r, "privateFrom")
+	if err != nil {
+		return nil, err
 	}
-	privateFrom, err := privacy.ToPublicKey(r["privateFrom"].(string))
+	privateFrom, err := keys.ToPublicKey(v)
 	if err != nil {
 		return nil, err
 	}
-	// privateFor required
-	if _, ok := r["privateFor"]; !ok {
+	// privacyGroupId not required, returned by Besu 1.4+
+	privacyGroupID, _, err := decode.String(r, "privacyGroupId")
+	if err != nil {
+		return nil, err
+	}
+	// privateFor required unless privacyGroupId is set
+	ps, ok, err := decode.Array(r, "privateFor")
+	if err != nil {
+		return nil, err
+	}
+	if !ok && privacyGroupID == "" {
 		return nil, fmt.Errorf("privateFor not found")
 	}
-	var privateFor []privacy.PublicKey
-	for _, v := range r["privateFor"].([]interface{}) {
-		key, err := privacy.ToPublicKey(v.(string))
+	var privateFor []keys.PublicKey
+	for i := range ps {
+		key, err := decodePublicKey("privateFor", ps, i)
 		if err != nil {
+			if err := mode.Skip(err); err != nil {
+				return nil, err
+			}
 			continue
 		}
 		privateFor = append(privateFor, key)
 	}
+	// revertReason not required, returned when the node runs with --revert-reason-enabled
+	var revertReason []byte
+	if v, ok, err := decode.String(r, "revertReason"); err != nil {
+		return nil, err
+	} else if ok {
+		b, err := decode.Bytes(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode revertReason %v, err: %v", v, err)
+		}
+		revertReason = b
+	}
 	// status not required
 	status := uint64(0)
-	if v, ok := r["status"]; ok {
-		if v.(string) == "0x1" {
-			status = uint64(1)
+	if v, ok, err := decode.String(r, "status"); err != nil {
+		return nil, err
+	} else if ok {
+		s, err := decode.Uint64(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode status %v, err: %v", v, err)
 		}
+		status = s
 	}
 	// logs required
-	if _, ok := r["logs"]; !ok {
+	rawLogs, ok, err := decode.Array(r, "logs")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
 		return nil, fmt.Errorf("logs not found")
 	}
 	var logs []*types.Log
-	for _, v := range r["logs"].([]interface{}) {
-		var log *types.Log
-		err := log.UnmarshalJSON(v.([]byte))
+	for _, v := range rawLogs {
+		raw, err := json.Marshal(v)
 		if err != nil {
+			return nil, err
+		}
+		log := new(types.Log)
+		if err := log.UnmarshalJSON(raw); err != nil {
+			if err := mode.Skip(fmt.Errorf("invalid log %s: %v", raw, err)); err != nil {
+				return nil, err
+			}
 			continue
 		}
 		logs = append(logs, log)
 	}
 	// logsBloom required
-	if _, ok := r["logsBloom"]; !ok {
-		return nil, fmt.Errorf("logsBloom not found")
+	logsBloomString, err := decode.RequiredString(r, "logsBloom")
+	if err != nil {
+		return nil, err
 	}
-	logsBloomString := r["logsBloom"].(string)
-	logsBloomBytes, err := hexutil.Decode(logsBloomString)
+	logsBloomBytes, err := decode.Bytes(logsBloomString)
 	if err != nil {
 		return nil, fmt.Errorf("failed to Decode %v, err: %v", logsBloomString, err)
 	}
-	logsBloom := types.BytesToBloom(logsBloomBytes)
-	if err != nil {
-		return nil, fmt.Errorf("failed to UnmarshalText %v, err: %v", logsBloomString, err)
+	if len(logsBloomBytes) != types.BloomByteLength {
+		return nil, fmt.Errorf("invalid logsBloom length %v", len(logsBloomBytes))
 	}
+	logsBloom := types.BytesToBloom(logsBloomBytes)
 	// blockHash not required
 	var blockHash common.Hash
-	if v, ok := r["blockHash"]; ok {
-		blockHash = common.HexToHash(v.(string))
+	if v, ok, err := decode.String(r, "blockHash"); err != nil {
+		return nil, err
+	} else if ok && v != "" {
+		if blockHash, err = decode.Hash(v); err != nil {
+			return nil, fmt.Errorf("failed to decode blockHash, err: %v", err)
+		}
 	}
 	// blockNumber not required
 	var blockNumber *big.Int
-	if v, ok := r["blockNumber"]; ok {
-		i := new(big.Int)
-		i.SetString(v.(string), 16)
+	if v, ok, err := decode.String(r, "blockNumber"); err != nil {
+		return nil, err
+	} else if ok {
+		i, err := decode.Big(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode blockNumber %v, err: %v", v, err)
+		}
 		blockNumber = i
 	}
 	// transactionIndex not required
-	var transactionIndex uint
-	if v, ok := r["transactionIndex"]; ok {
-		i := new(big.Int)
-		i.SetString(v.(string), 16)
-		transactionIndex = uint(i.Uint64())
+	var transactionIndex uint64
+	if v, ok, err := decode.String(r, "transactionIndex"); err != nil {
+		return nil, err
+	} else if ok {
+		i, err := decode.Uint64(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode transactionIndex %v, err: %v", v, err)
+		}
+		transactionIndex = i
 	}
 	return &PrivateReceipt{
 		Status:           status,
@@ -144,11 +236,124 @@ func MarshalPrivateReceipt(r map[string]interface{}) (*PrivateReceipt, error) {
 		ContractAddress:  contractAddress,
 		BlockHash:        blockHash,
 		BlockNumber:      blockNumber,
-		TransactionIndex: transactionIndex,
+		TxIndex:          transactionIndex,
+		TransactionIndex: uint(transactionIndex),
 		PrivateFrom:      privateFrom,
 		PrivateFor:       privateFor,
-		Restriction:      "restricted",
+		PrivacyGroupID:   privacyGroupID,
+		Restriction:      Restricted,
 		CommitmentHash:   commitmentHash,
 		Output:           output,
+		RevertReason:     revertReason,
 	}, nil
 }
+
+// decodePublicKey decodes element i of the array field key as a public key.
+func decodePublicKey(key string, a []interface{}, i int) (keys.PublicKey, error) {
+	v, err := decode.StringElement(key, a, i)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := keys.ToPublicKey(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %v %v: %v", key, v, err)
+	}
+	return pub, nil
+}
+
+// errorSelector is the selector of Error(string), which revert reasons are encoded with.
+var errorSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+
+// FailureReason returns why the transaction failed, empty if it succeeded.
+// The reason is decoded from the revert reason, or from the output on nodes
+// not returning revert reasons, falling back to the raw revert data.
+func (r *PrivateReceipt) FailureReason() string {
+	if r.Status == 1 {
+		return ""
+	}
+	for _, data := range [][]byte{r.RevertReason, r.Output} {
+		if reason, ok := unpackRevert(data); ok {
+			return "execution reverted: " + reason
+		}
+	}
+	if len(r.RevertReason) > 0 {
+		return "execution reverted: " + hexutil.Encode(r.RevertReason)
+	}
+	if len(r.Output) > 0 {
+		return "execution reverted: " + hexutil.Encode(r.Output)
+	}
+	return "execution failed"
+}
+
+// unpackRevert decodes the message of data encoded as Error(string).
+func unpackRevert(data []byte) (string, bool) {
+	if len(data) < 4+64 || !bytes.Equal(data[:4], errorSelector) {
+		return "", false
+	}
+	data = data[4:]
+	offset := new(big.Int).SetBytes(data[:32])
+	if !offset.IsUint64() || offset.Uint64() > uint64(len(data))-32 {
+		return "", false
+	}
+	start := offset.Uint64() + 32
+	length := new(big.Int).SetBytes(data[start-32 : start])
+	if !length.IsUint64() || length.Uint64() > uint64(len(data))-start {
+		return "", false
+	}
+	return string(data[start : start+length.Uint64()]), true
+}
+
+// rlpReceipt is the RLP layout of a Besu private receipt, the revert reason
+// being appended only when present.
+type rlpReceipt struct {
+	Status       uint64
+	Logs         []*types.Log
+	Output       []byte
+	RevertReason [][]byte `rlp:"tail"`
+}
+
+// EncodeRLP implements rlp.Encoder, encoding the receipt as Besu does:
+// [status, logs, output] or [status, logs, output, revertReason].
+func (r *PrivateReceipt) EncodeRLP(w io.Writer) error {
+	logs := r.Logs
+	if logs == nil {
+		logs = []*types.Log{}
+	}
+	enc := &rlpReceipt{
+		Status: r.Status,
+		Logs:   logs,
+		Output: r.Output,
+	}
+	if len(r.RevertReason) > 0 {
+		enc.RevertReason = [][]byte{r.RevertReason}
+	}
+	return rlp.Encode(w, enc)
+}
+
+// DecodeRLP implements rlp.Decoder. Only the consensus fields are decoded,
+// the bloom is derived from the logs.
+func (r *PrivateReceipt) DecodeRLP(s *rlp.Stream) error {
+	var dec rlpReceipt
+	if err := s.Decode(&dec); err != nil {
+		return err
+	}
+	if len(dec.RevertReason) > 1 {
+		return fmt.Errorf("too many receipt fields")
+	}
+	r.Status, r.Logs, r.Output = dec.Status, dec.Logs, dec.Output
+	r.RevertReason = nil
+	if len(dec.RevertReason) == 1 {
+		r.RevertReason = dec.RevertReason[0]
+	}
+	r.Bloom = types.BytesToBloom(types.LogsBloom(dec.Logs).Bytes())
+	return nil
+}
+
+// BlockNumberU64 returns the number of the block of the privacy marker
+// transaction, 0 if it is not mined.
+func (r *PrivateReceipt) BlockNumberU64() uint64 {
+	if r.BlockNumber == nil {
+		return 0
+	}
+	return r.BlockNumber.Uint64()
+}
@@ -3,18 +3,23 @@ package types
 import (
 	"crypto/ecdsa"
 	"fmt"
+	"io"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rlp"
-	"golang.org/x/crypto/sha3"
+
+	"github.com/bsostech/go-besu/internal/decode"
+	"github.com/bsostech/go-besu/internal/encoding"
+	"github.com/bsostech/go-besu/privacy/keys"
 )
 
 // PrivateTransaction .
 type PrivateTransaction struct {
-	Data txdata
+	data     txdata
+	ordering ParticipantsOrdering // applied to privateFor when hashing and encoding
 }
 
 type txdata struct {
@@ -29,24 +34,60 @@ type txdata struct {
 	R *big.Int `json:"r" gencodec:"required"`
 	S *big.Int `json:"s" gencodec:"required"`
 
-	PrivateFrom []byte   `json:"private_from"    gencodec:"required"`
-	PrivateFor  [][]byte `json:"private_for"    gencodec:"required"`
-	Restriction string
+	PrivateFrom    []byte   `json:"private_from"    gencodec:"required"`
+	PrivateFor     [][]byte `json:"private_for"`
+	PrivacyGroupID []byte   `json:"privacy_group_id"` // used instead of PrivateFor if set
+	Restriction    Restriction
+}
+
+// rlpTxdata is the wire layout of txdata, the 11th item being either
+// privateFor (a list) or privacyGroupId (a string).
+type rlpTxdata struct {
+	AccountNonce uint64
+	Price        *big.Int
+	GasLimit     uint64
+	Recipient    *common.Address `rlp:"nil"`
+	Amount       *big.Int
+	Payload      []byte
+	V            *big.Int
+	R            *big.Int
+	S            *big.Int
+	PrivateFrom  []byte
+	Privacy      rlp.RawValue
+	Restriction  Restriction
 }
 
 // NewContractCreation .
 func NewContractCreation(nonce uint64, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte, privateFrom []byte, privateFor [][]byte) *PrivateTransaction {
-	return newTransaction(nonce, nil, amount, gasLimit, gasPrice, data, privateFrom, privateFor)
+	return newTransaction(nonce, nil, amount, gasLimit, gasPrice, data, privateFrom, privateFor, nil)
 }
 
 // NewTransaction .
 func NewTransaction(nonce uint64, to *common.Address, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte, privateFrom []byte, privateFor [][]byte) *PrivateTransaction {
-	return newTransaction(nonce, to, amount, gasLimit, gasPrice, data, privateFrom, privateFor)
+	return newTransaction(nonce, to, amount, gasLimit, gasPrice, data, privateFrom, privateFor, nil)
+}
+
+// NewPrivateTransaction creates a transaction addressed to a privacy group
+// by its ID (the decoded base64 group ID) instead of by privateFor.
+// A nil to creates a contract.
+func NewPrivateTransaction(nonce uint64, to *common.Address, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte, privateFrom []byte, privacyGroupID []byte) *PrivateTransaction {
+	return newTransaction(nonce, to, amount, gasLimit, gasPrice, data, privateFrom, nil, privacyGroupID)
+}
+
+// NewPrivateCall creates a message call to an existing private contract,
+// with zero value and zero gas price.
+func NewPrivateCall(nonce uint64, to common.Address, gasLimit uint64, data []byte, privateFrom []byte, privateFor [][]byte) *PrivateTransaction {
+	return newTransaction(nonce, &to, nil, gasLimit, nil, data, privateFrom, privateFor, nil)
+}
+
+// NewPrivateGroupCall is NewPrivateCall addressed to a privacy group by its ID.
+func NewPrivateGroupCall(nonce uint64, to common.Address, gasLimit uint64, data []byte, privateFrom []byte, privacyGroupID []byte) *PrivateTransaction {
+	return newTransaction(nonce, &to, nil, gasLimit, nil, data, privateFrom, nil, privacyGroupID)
 }
 
 // SignTx .
 func (tx *PrivateTransaction) SignTx(chainID *big.Int, prv *ecdsa.PrivateKey) (*PrivateTransaction, error) {
-	h := hash(tx, chainID)
+	h := SigningHash(tx, chainID)
 	sig, err := crypto.Sign(h[:], prv)
 	if err != nil {
 		return nil, err
@@ -54,50 +95,286 @@ func (tx *PrivateTransaction) SignTx(chainID *big.Int, prv *ecdsa.PrivateKey) (*
 	return withSignature(tx, sig, chainID)
 }
 
-// MarshalPrivateTransaction .
+// WithSignature returns a copy of tx signed with sig, a 65 bytes [R || S || V]
+// signature of SigningHash(tx, chainID) with V being 0 or 1, e.g. made by an
+// external signer.
+func (tx *PrivateTransaction) WithSignature(chainID *big.Int, sig []byte) (*PrivateTransaction, error) {
+	if len(sig) != crypto.SignatureLength {
+		return nil, fmt.Errorf("wrong size for signature: got %d, want %d", len(sig), crypto.SignatureLength)
+	}
+	return withSignature(tx, sig, chainID)
+}
+
+// Sender recovers the address which signed the transaction.
+func (tx *PrivateTransaction) Sender() (common.Address, error) {
+	if tx.data.V == nil || tx.data.V.Cmp(big35) < 0 {
+		return common.Address{}, fmt.Errorf("transaction is not signed")
+	}
+	if tx.data.R == nil || tx.data.S == nil || tx.data.R.BitLen() > 256 || tx.data.S.BitLen() > 256 {
+		return common.Address{}, fmt.Errorf("invalid signature values")
+	}
+	// V = recovery id + chainID*2 + 35
+	v := getBig().Sub(tx.data.V, big35)
+	defer putBig(v)
+	chainID := getBig().Rsh(v, 1)
+	defer putBig(chainID)
+	sig := make([]byte, crypto.SignatureLength)
+	math.ReadBits(tx.data.R, sig[:32])
+	math.ReadBits(tx.data.S, sig[32:64])
+	sig[64] = byte(v.Bit(0))
+	h := SigningHash(tx, chainID)
+	pub, err := crypto.SigToPub(h[:], sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// EncodeRLP implements rlp.Encoder
+func (tx *PrivateTransaction) EncodeRLP(w io.Writer) error {
+	return rlp.Encode(w, []interface{}{
+		tx.data.AccountNonce,
+		tx.data.Price,
+		tx.data.GasLimit,
+		tx.data.Recipient,
+		tx.data.Amount,
+		tx.data.Payload,
+		tx.data.V, tx.data.R, tx.data.S,
+		tx.data.PrivateFrom,
+		tx.privacy(),
+		tx.data.Restriction,
+	})
+}
+
+// DecodeRLP implements rlp.Decoder
+func (tx *PrivateTransaction) DecodeRLP(s *rlp.Stream) error {
+	var dec rlpTxdata
+	if err := s.Decode(&dec); err != nil {
+		return err
+	}
+	if !dec.Restriction.Valid() {
+		return fmt.Errorf("invalid restriction %v", string(dec.Restriction))
+	}
+	d := txdata{
+		AccountNonce: dec.AccountNonce,
+		Price:        dec.Price,
+		GasLimit:     dec.GasLimit,
+		Recipient:    dec.Recipient,
+		Amount:       dec.Amount,
+		Payload:      dec.Payload,
+		V:            dec.V,
+		R:            dec.R,
+		S:            dec.S,
+		PrivateFrom:  dec.PrivateFrom,
+		Restriction:  dec.Restriction,
+	}
+	kind, _, _, err := rlp.Split(dec.Privacy)
+	if err != nil {
+		return err
+	}
+	if kind == rlp.List {
+		err = rlp.DecodeBytes(dec.Privacy, &d.PrivateFor)
+	} else {
+		err = rlp.DecodeBytes(dec.Privacy, &d.PrivacyGroupID)
+	}
+	if err != nil {
+		return err
+	}
+	tx.data = d
+	return nil
+}
+
+// Nonce returns the private nonce of the transaction.
+func (tx *PrivateTransaction) Nonce() uint64 { return tx.data.AccountNonce }
+
+// GasPrice returns a copy of the gas price of the transaction.
+func (tx *PrivateTransaction) GasPrice() *big.Int { return copyBig(tx.data.Price) }
+
+// Gas returns the gas limit of the transaction.
+func (tx *PrivateTransaction) Gas() uint64 { return tx.data.GasLimit }
+
+// Value returns a copy of the amount of the transaction.
+func (tx *PrivateTransaction) Value() *big.Int { return copyBig(tx.data.Amount) }
+
+// Data returns a copy of the input data of the transaction.
+func (tx *PrivateTransaction) Data() []byte { return common.CopyBytes(tx.data.Payload) }
+
+// To returns the recipient address of the transaction.
+// It returns nil if the transaction is a contract creation.
+func (tx *PrivateTransaction) To() *common.Address {
+	if tx.data.Recipient == nil {
+		return nil
+	}
+	to := *tx.data.Recipient
+	return &to
+}
+
+// PrivateFrom returns the enclave public key of the sender.
+func (tx *PrivateTransaction) PrivateFrom() []byte { return common.CopyBytes(tx.data.PrivateFrom) }
+
+// PrivateFor returns the enclave public keys of the recipients, in the participants ordering.
+func (tx *PrivateTransaction) PrivateFor() [][]byte {
+	ordered := tx.ordering.Order(tx.data.PrivateFor)
+	privateFor := make([][]byte, len(ordered))
+	for i := range ordered {
+		privateFor[i] = common.CopyBytes(ordered[i])
+	}
+	return privateFor
+}
+
+// PrivacyGroupID returns the privacy group ID the transaction is addressed to,
+// or nil if it is addressed by PrivateFor.
+func (tx *PrivateTransaction) PrivacyGroupID() []byte {
+	return common.CopyBytes(tx.data.PrivacyGroupID)
+}
+
+// Restriction returns the restriction of the transaction.
+func (tx *PrivateTransaction) Restriction() Restriction { return tx.data.Restriction }
+
+// RawSignatureValues returns the V, R, S signature values of the transaction.
+func (tx *PrivateTransaction) RawSignatureValues() (v, r, s *big.Int) {
+	return tx.data.V, tx.data.R, tx.data.S
+}
+
+// transactionFields are the fields of a priv_getPrivateTransaction response.
+var transactionFields = []string{"blockHash", "blockNumber", "transactionIndex", "hash", "from", "gas", "gasPrice",
+	"input", "nonce", "to", "value", "v", "r", "s", "privateFrom", "privateFor", "privacyGroupId", "restriction"}
+
+// MarshalPrivateTransaction decodes a private transaction in decode.Lenient mode.
 func MarshalPrivateTransaction(r map[string]interface{}) (*PrivateTransaction, error) {
-	// AccountNonce: can not get private nonce from r now
-	// Price , GasLimit Amount dont care
-	// recipient required
-	var recipient *common.Address
-	if _, ok := r["to"]; !ok {
-		*recipient = common.HexToAddress(r["to"].(string))
+	return MarshalPrivateTransactionWithMode(r, decode.Lenient)
+}
+
+// MarshalPrivateTransactionWithMode decodes a private transaction returned by priv_getPrivateTransaction.
+func MarshalPrivateTransactionWithMode(r map[string]interface{}, mode decode.Mode) (*PrivateTransaction, error) {
+	if err := mode.CheckFields(r, transactionFields...); err != nil {
+		return nil, err
+	}
+	var ptx txdata
+	// to not required, nil means contract creation
+	v, ok, err := decode.String(r, "to")
+	if err != nil {
+		return nil, err
+	}
+	if ok && v != "" {
+		recipient, err := decode.Address(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode to, err: %v", err)
+		}
+		ptx.Recipient = &recipient
 	}
 	// payload required
-	if _, ok := r["input"]; !ok {
+	v, ok, err = decode.String(r, "input")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
 		return nil, fmt.Errorf("input data not found")
 	}
-	payload, err := hexutil.Decode(r["input"].(string))
+	payload, err := decode.Bytes(v)
 	if err != nil {
 		return nil, fmt.Errorf("payload can not decode")
 	}
-	// KEVIN TODO: load other args
-	ptx := txdata{
-		Recipient: recipient,
-		Payload:   payload,
+	ptx.Payload = payload
+	// nonce, gas not required
+	for key, field := range map[string]*uint64{"nonce": &ptx.AccountNonce, "gas": &ptx.GasLimit} {
+		v, ok, err := decode.String(r, key)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			i, err := decode.Uint64(v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode %v %v, err: %v", key, v, err)
+			}
+			*field = i
+		}
+	}
+	// gasPrice, value, v, r, s not required
+	for key, field := range map[string]**big.Int{"gasPrice": &ptx.Price, "value": &ptx.Amount, "v": &ptx.V, "r": &ptx.R, "s": &ptx.S} {
+		*field = new(big.Int)
+		v, ok, err := decode.String(r, key)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			i, err := decode.Big(v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode %v %v, err: %v", key, v, err)
+			}
+			*field = i
+		}
+	}
+	// privateFrom required
+	v, err = decode.RequiredString(r, "privateFrom")
+	if err != nil {
+		return nil, err
+	}
+	privateFrom, err := keys.ToPublicKey(v)
+	if err != nil {
+		return nil, err
+	}
+	ptx.PrivateFrom = privateFrom
+	// one of privateFor and privacyGroupId required
+	groupID, hasGroupID, err := decode.String(r, "privacyGroupId")
+	if err != nil {
+		return nil, err
+	}
+	privateFor, hasPrivateFor, err := decode.Array(r, "privateFor")
+	if err != nil {
+		return nil, err
+	}
+	if hasGroupID {
+		privacyGroupID, err := keys.ToPublicKey(groupID)
+		if err != nil {
+			return nil, err
+		}
+		ptx.PrivacyGroupID = privacyGroupID
+	} else if hasPrivateFor {
+		for i := range privateFor {
+			key, err := decodePublicKey("privateFor", privateFor, i)
+			if err != nil {
+				return nil, err
+			}
+			ptx.PrivateFor = append(ptx.PrivateFor, key)
+		}
+	} else {
+		return nil, fmt.Errorf("privateFor or privacyGroupId not found")
+	}
+	// restriction not required
+	ptx.Restriction = Restricted
+	if v, ok, err := decode.String(r, "restriction"); err != nil {
+		return nil, err
+	} else if ok {
+		restriction, err := ParseRestriction(v)
+		if err != nil {
+			return nil, err
+		}
+		ptx.Restriction = restriction
 	}
 	return &PrivateTransaction{
-		Data: ptx,
+		data: ptx,
 	}, nil
 }
 
-func newTransaction(nonce uint64, to *common.Address, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte, privateFrom []byte, privateFor [][]byte) *PrivateTransaction {
+func newTransaction(nonce uint64, to *common.Address, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte, privateFrom []byte, privateFor [][]byte, privacyGroupID []byte) *PrivateTransaction {
 	if len(data) > 0 {
 		data = common.CopyBytes(data)
 	}
 	d := txdata{
-		AccountNonce: nonce,
-		Recipient:    to,
-		Payload:      data,
-		Amount:       new(big.Int),
-		GasLimit:     gasLimit,
-		Price:        new(big.Int),
-		PrivateFrom:  privateFrom,
-		PrivateFor:   privateFor,
-		Restriction:  "restricted",
-		V:            new(big.Int),
-		R:            new(big.Int),
-		S:            new(big.Int),
+		AccountNonce:   nonce,
+		Recipient:      to,
+		Payload:        data,
+		Amount:         new(big.Int),
+		GasLimit:       gasLimit,
+		Price:          new(big.Int),
+		PrivateFrom:    privateFrom,
+		PrivateFor:     privateFor,
+		PrivacyGroupID: privacyGroupID,
+		Restriction:    Restricted,
+		V:              new(big.Int),
+		R:              new(big.Int),
+		S:              new(big.Int),
 	}
 	if amount != nil {
 		d.Amount.Set(amount)
@@ -105,33 +382,59 @@ func newTransaction(nonce uint64, to *common.Address, amount *big.Int, gasLimit
 	if gasPrice != nil {
 		d.Price.Set(gasPrice)
 	}
-	return &PrivateTransaction{Data: d}
-}
-
-func hash(tx *PrivateTransaction, chainID *big.Int) common.Hash {
-	h := rlpHash([]interface{}{
-		tx.Data.AccountNonce,
-		tx.Data.Price,
-		tx.Data.GasLimit,
-		tx.Data.Recipient,
-		tx.Data.Amount,
-		tx.Data.Payload,
-		chainID, uint(0), uint(0),
-		tx.Data.PrivateFrom,
-		tx.Data.PrivateFor,
-		tx.Data.Restriction,
-	})
-	return h
+	return &PrivateTransaction{data: d}
 }
 
-func rlpHash(x interface{}) (h common.Hash) {
-	hw := sha3.NewLegacyKeccak256()
-	err := rlp.Encode(hw, x)
-	if err != nil {
-		return common.Hash{}
+func copyBig(i *big.Int) *big.Int {
+	if i == nil {
+		return new(big.Int)
 	}
-	hw.Sum(h[:0])
-	return h
+	return new(big.Int).Set(i)
+}
+
+// SigningHash returns the digest signed by the sender of tx on chain chainID,
+// following EIP-155 with the EEA privacy fields appended. It is the
+// Keccak-256 hash of the RLP list
+//
+//	[nonce, gasPrice, gas, to, value, data, chainID, 0, 0,
+//	 privateFrom, privateFor | privacyGroupId, restriction]
+//
+// where privateFor is a list of enclave keys and privacyGroupId the raw bytes
+// of the group ID, whichever the transaction is addressed with. The signature
+// is encoded with v = recoveryID + 27 + chainID*2 + 8. A nil chainID is
+// encoded as 0. The chain ID and restriction segments are encoded once and
+// reused.
+func SigningHash(tx *PrivateTransaction, chainID *big.Int) common.Hash {
+	return encoding.RLPHash(signingItems(tx, chainID))
+}
+
+// SigningPayload returns the RLP list hashed by SigningHash, for remote
+// signers hashing what they sign.
+func SigningPayload(tx *PrivateTransaction, chainID *big.Int) ([]byte, error) {
+	return rlp.EncodeToBytes(signingItems(tx, chainID))
+}
+
+func signingItems(tx *PrivateTransaction, chainID *big.Int) []interface{} {
+	return []interface{}{
+		tx.data.AccountNonce,
+		tx.data.Price,
+		tx.data.GasLimit,
+		tx.data.Recipient,
+		tx.data.Amount,
+		tx.data.Payload,
+		chainSegment(chainID),
+		tx.data.PrivateFrom,
+		tx.privacy(),
+		restrictionSegment(tx.data.Restriction),
+	}
+}
+
+// privacy returns the privacyGroupId if set, privateFor otherwise.
+func (tx *PrivateTransaction) privacy() interface{} {
+	if tx.data.PrivacyGroupID != nil {
+		return tx.data.PrivacyGroupID
+	}
+	return tx.ordering.Order(tx.data.PrivateFor)
 }
 
 func withSignature(tx *PrivateTransaction, sig []byte, chainID *big.Int) (*PrivateTransaction, error) {
@@ -139,18 +442,30 @@ func withSignature(tx *PrivateTransaction, sig []byte, chainID *big.Int) (*Priva
 	if err != nil {
 		return nil, err
 	}
-	newV := v.Uint64() + chainID.Uint64()*2 + 8 // KEVIN hack from web3js-eea
-	cpy := &PrivateTransaction{Data: tx.Data}
-	cpy.Data.R, cpy.Data.S, cpy.Data.V = r, s, new(big.Int).SetUint64(newV)
+	if chainID == nil {
+		chainID = common.Big0
+	}
+	// V = v + chainID*2 + 8, the KEVIN hack from web3js-eea
+	var newV *big.Int
+	if chainID.IsUint64() && chainID.Uint64() <= (math.MaxUint64-v-8)/2 {
+		newV = new(big.Int).SetUint64(v + 8 + chainID.Uint64()*2)
+	} else {
+		double := getBig().Lsh(chainID, 1)
+		defer putBig(double)
+		newV = new(big.Int).SetUint64(v + 8)
+		newV.Add(newV, double)
+	}
+	cpy := &PrivateTransaction{data: tx.data, ordering: tx.ordering}
+	cpy.data.R, cpy.data.S, cpy.data.V = r, s, newV
 	return cpy, nil
 }
 
-func signatureValues(tx *PrivateTransaction, sig []byte) (r, s, v *big.Int, err error) {
+func signatureValues(tx *PrivateTransaction, sig []byte) (r, s *big.Int, v uint64, err error) {
 	if len(sig) != crypto.SignatureLength {
 		panic(fmt.Sprintf("wrong size for signature: got %d, want %d", len(sig), crypto.SignatureLength))
 	}
 	r = new(big.Int).SetBytes(sig[:32])
 	s = new(big.Int).SetBytes(sig[32:64])
-	v = new(big.Int).SetBytes([]byte{sig[64] + 27})
+	v = uint64(sig[64]) + 27
 	return r, s, v, nil
 }
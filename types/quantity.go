@@ -0,0 +1,70 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/bsostech/go-besu/internal/decode"
+)
+
+// ToUint64 converts a nonce, gas amount, block number or index as found in
+// RPC responses and older APIs of this library to uint64: unsigned and
+// non-negative signed integers, *big.Int, the hexutil quantities, json.Number
+// and strings, hex with 0x prefix or decimal. Nonces, gas and indexes are
+// uint64 in the public API, and hexutil.Uint64 in JSON.
+func ToUint64(v interface{}) (uint64, error) {
+	switch v := v.(type) {
+	case uint64:
+		return v, nil
+	case uint:
+		return uint64(v), nil
+	case uint32:
+		return uint64(v), nil
+	case int:
+		return nonNegative(int64(v))
+	case int64:
+		return nonNegative(v)
+	case hexutil.Uint64:
+		return uint64(v), nil
+	case hexutil.Uint:
+		return uint64(v), nil
+	case *hexutil.Big:
+		if v == nil {
+			return 0, fmt.Errorf("nil quantity")
+		}
+		return bigToUint64((*big.Int)(v))
+	case *big.Int:
+		return bigToUint64(v)
+	case json.Number:
+		return strconv.ParseUint(string(v), 10, 64)
+	case string:
+		if strings.HasPrefix(v, "0x") || strings.HasPrefix(v, "0X") {
+			return decode.Uint64(v)
+		}
+		return strconv.ParseUint(v, 10, 64)
+	default:
+		return 0, fmt.Errorf("unsupported quantity type %T", v)
+	}
+}
+
+func nonNegative(v int64) (uint64, error) {
+	if v < 0 {
+		return 0, fmt.Errorf("negative quantity %v", v)
+	}
+	return uint64(v), nil
+}
+
+func bigToUint64(v *big.Int) (uint64, error) {
+	if v == nil {
+		return 0, fmt.Errorf("nil quantity")
+	}
+	if !v.IsUint64() {
+		return 0, fmt.Errorf("quantity %v out of uint64 range", v)
+	}
+	return v.Uint64(), nil
+}
@@ -0,0 +1,61 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Restriction tells which nodes store the payload of a private transaction.
+type Restriction string
+
+// Restriction .
+const (
+	// Restricted payloads are only stored by the participants.
+	Restricted Restriction = "restricted"
+	// Unrestricted payloads are stored by all nodes, with privacy plugins.
+	Unrestricted Restriction = "unrestricted"
+)
+
+// ParseRestriction returns the restriction named s.
+func ParseRestriction(s string) (Restriction, error) {
+	r := Restriction(s)
+	if !r.Valid() {
+		return "", fmt.Errorf("invalid restriction %v", s)
+	}
+	return r, nil
+}
+
+// Valid .
+func (r Restriction) Valid() bool {
+	return r == Restricted || r == Unrestricted
+}
+
+func (r Restriction) String() string {
+	return string(r)
+}
+
+// MarshalJSON implements json.Marshaler. The zero value marshals as "", an unset restriction.
+func (r Restriction) MarshalJSON() ([]byte, error) {
+	if r != "" && !r.Valid() {
+		return nil, fmt.Errorf("invalid restriction %v", string(r))
+	}
+	return json.Marshal(string(r))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (r *Restriction) UnmarshalJSON(input []byte) error {
+	var s string
+	if err := json.Unmarshal(input, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		*r = ""
+		return nil
+	}
+	parsed, err := ParseRestriction(s)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
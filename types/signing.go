@@ -0,0 +1,86 @@
+package types
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// big35 is the offset of EIP-155 signature values.
+var big35 = big.NewInt(35)
+
+// scratch pools the big.Int temporaries of the V, R, S and chain ID math.
+var scratch = sync.Pool{
+	New: func() interface{} { return new(big.Int) },
+}
+
+func getBig() *big.Int {
+	return scratch.Get().(*big.Int)
+}
+
+func putBig(i *big.Int) {
+	scratch.Put(i)
+}
+
+// chainSegments caches the RLP encoding of the [chainID, 0, 0] segment of the
+// signing hash by chain ID, which is the same for every transaction signed on a chain.
+var chainSegments sync.Map
+
+// chainSegment returns the pre-encoded [chainID, 0, 0] items of the signing
+// hash, a nil chainID being encoded as 0.
+func chainSegment(chainID *big.Int) rlp.RawValue {
+	if chainID == nil {
+		chainID = common.Big0
+	}
+	if chainID.IsUint64() {
+		if seg, ok := chainSegments.Load(chainID.Uint64()); ok {
+			return seg.(rlp.RawValue)
+		}
+	}
+	seg, err := encodeItems(chainID, uint(0), uint(0))
+	if err != nil {
+		return nil
+	}
+	if chainID.IsUint64() {
+		chainSegments.Store(chainID.Uint64(), seg)
+	}
+	return seg
+}
+
+// restrictionSegments are the pre-encoded restrictions.
+var restrictionSegments = map[Restriction]rlp.RawValue{
+	Restricted:   mustEncode(Restricted),
+	Unrestricted: mustEncode(Unrestricted),
+}
+
+// restrictionSegment returns the pre-encoded restriction.
+func restrictionSegment(restriction Restriction) rlp.RawValue {
+	if seg, ok := restrictionSegments[restriction]; ok {
+		return seg
+	}
+	return mustEncode(restriction)
+}
+
+// encodeItems returns the concatenated RLP encodings of items, to be spliced
+// into a list as a raw value.
+func encodeItems(items ...interface{}) (rlp.RawValue, error) {
+	var seg []byte
+	for _, item := range items {
+		b, err := rlp.EncodeToBytes(item)
+		if err != nil {
+			return nil, err
+		}
+		seg = append(seg, b...)
+	}
+	return seg, nil
+}
+
+func mustEncode(v interface{}) rlp.RawValue {
+	b, err := rlp.EncodeToBytes(v)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
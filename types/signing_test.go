@@ -0,0 +1,201 @@
+package types
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	benchKey, _  = crypto.HexToECDSA("8f2a55949038a9610f50fb23b5883af3b4ecb3c3bb792cbcefbd1542c692be63")
+	benchChainID = big.NewInt(2018)
+)
+
+func groupTx(nonce uint64, data []byte) *PrivateTransaction {
+	to := common.HexToAddress("0x42699a7612a82f1d9c36148af9c77354759b210b")
+	return NewPrivateTransaction(nonce, &to, big.NewInt(0), 3000000, big.NewInt(0), data, make([]byte, 32), make([]byte, 32))
+}
+
+func TestSenderChainIDs(t *testing.T) {
+	huge, _ := new(big.Int).SetString("10000000000000000000000000000000", 16)
+	for _, chainID := range []*big.Int{big.NewInt(1), big.NewInt(2018), new(big.Int).SetUint64(1<<63 + 5), huge} {
+		signed, err := groupTx(1, []byte{1}).SignTx(chainID, benchKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v, _, _ := signed.RawSignatureValues()
+		// v = recovery id + 27 + chainID*2 + 8
+		base := new(big.Int).Add(new(big.Int).Lsh(chainID, 1), big35)
+		if d := new(big.Int).Sub(v, base); d.Sign() < 0 || d.Cmp(big.NewInt(1)) > 0 {
+			t.Fatalf("chain %v: v = %v", chainID, v)
+		}
+		sender, err := signed.Sender()
+		if err != nil || sender != crypto.PubkeyToAddress(benchKey.PublicKey) {
+			t.Fatalf("chain %v: sender %v, %v", chainID, sender.Hex(), err)
+		}
+	}
+}
+
+func TestNilChainID(t *testing.T) {
+	tx := groupTx(1, []byte{1})
+	if SigningHash(tx, nil) != SigningHash(tx, big.NewInt(0)) {
+		t.Fatal("nil chain ID not hashed as 0")
+	}
+	signed, err := tx.SignTx(nil, benchKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _, _ := signed.RawSignatureValues(); v.Cmp(big35) < 0 || v.Cmp(big.NewInt(36)) > 0 {
+		t.Fatalf("v = %v, want 35 or 36", v)
+	}
+	sender, err := signed.Sender()
+	if err != nil || sender != crypto.PubkeyToAddress(benchKey.PublicKey) {
+		t.Fatalf("sender %v, %v", sender.Hex(), err)
+	}
+}
+
+func TestSenderInvalidSignatureValues(t *testing.T) {
+	signed, err := groupTx(1, nil).SignTx(benchChainID, benchKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oversized := new(big.Int).Lsh(big.NewInt(1), 256)
+	for _, mutate := range []func(tx *PrivateTransaction){
+		func(tx *PrivateTransaction) { tx.data.V = big.NewInt(27) },
+		func(tx *PrivateTransaction) { tx.data.R = oversized },
+		func(tx *PrivateTransaction) { tx.data.S = oversized },
+		func(tx *PrivateTransaction) { tx.data.R = nil },
+	} {
+		cpy := &PrivateTransaction{data: signed.data}
+		mutate(cpy)
+		if _, err := cpy.Sender(); err == nil {
+			t.Errorf("sender of %v recovered", cpy.data)
+		}
+	}
+}
+
+func BenchmarkSigningHash(b *testing.B) {
+	tx := groupTx(1, make([]byte, 256))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		SigningHash(tx, benchChainID)
+	}
+}
+
+func BenchmarkSignTx(b *testing.B) {
+	benchmarkSign(b, benchKey)
+}
+
+func benchmarkSign(b *testing.B, key *ecdsa.PrivateKey) {
+	tx := groupTx(1, make([]byte, 256))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := tx.SignTx(benchChainID, key); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkWithSignature(b *testing.B) {
+	tx := groupTx(1, make([]byte, 256))
+	h := SigningHash(tx, benchChainID)
+	sig, err := crypto.Sign(h[:], benchKey)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := tx.WithSignature(benchChainID, sig); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSender(b *testing.B) {
+	signed, err := groupTx(1, make([]byte, 256)).SignTx(benchChainID, benchKey)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := signed.Sender(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// referenceSigningPayload builds the documented EEA signing list by hand.
+func referenceSigningPayload(t *testing.T, nonce uint64, to *common.Address, data, privateFrom []byte, privacy interface{}, restriction string, chainID *big.Int) []byte {
+	enc, err := rlp.EncodeToBytes([]interface{}{
+		nonce, big.NewInt(0), uint64(3000000), to, big.NewInt(0), data,
+		chainID, uint(0), uint(0),
+		privateFrom, privacy, restriction,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return enc
+}
+
+func TestSigningHashLayout(t *testing.T) {
+	to := common.HexToAddress("0x42699a7612a82f1d9c36148af9c77354759b210b")
+	privateFrom := bytes.Repeat([]byte{1}, 32)
+	groupID := bytes.Repeat([]byte{2}, 32)
+	privateFor := [][]byte{bytes.Repeat([]byte{4}, 32), bytes.Repeat([]byte{3}, 32)}
+	tests := []struct {
+		name    string
+		tx      *PrivateTransaction
+		privacy interface{}
+	}{
+		{"group", NewPrivateTransaction(7, &to, big.NewInt(0), 3000000, big.NewInt(0), []byte{0xde, 0xad}, privateFrom, groupID), groupID},
+		{"privateFor", NewTransaction(7, &to, big.NewInt(0), 3000000, big.NewInt(0), []byte{0xde, 0xad}, privateFrom, privateFor), privateFor},
+		{"creation", NewContractCreation(7, big.NewInt(0), 3000000, big.NewInt(0), []byte{0xde, 0xad}, privateFrom, privateFor), privateFor},
+	}
+	for _, test := range tests {
+		for _, chainID := range []*big.Int{big.NewInt(1), benchChainID, new(big.Int).Lsh(big.NewInt(1), 70)} {
+			want := referenceSigningPayload(t, 7, test.tx.To(), []byte{0xde, 0xad}, privateFrom, test.privacy, "restricted", chainID)
+			payload, err := SigningPayload(test.tx, chainID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(payload, want) {
+				t.Fatalf("%v, chain %v: SigningPayload = %x, want %x", test.name, chainID, payload, want)
+			}
+			if got := SigningHash(test.tx, chainID); got != crypto.Keccak256Hash(want) {
+				t.Fatalf("%v, chain %v: SigningHash = %v, want keccak256 of the payload", test.name, chainID, got.Hex())
+			}
+		}
+	}
+}
+
+func TestSigningHashVector(t *testing.T) {
+	to := common.HexToAddress("0x42699a7612a82f1d9c36148af9c77354759b210b")
+	tx := NewPrivateTransaction(0, &to, big.NewInt(0), 3000000, big.NewInt(0), common.FromHex("0x3fa4f245"),
+		common.FromHex("0x035695b4cc4b0941e60551d7a19cf30603db5bfc23e5ac43a56f57f25f75486a"),
+		common.FromHex("0x0f200e885ff29e973e2576b6600181d1b0a2b5294e30d9be4a1981ffb33a0b8c"))
+	if got := SigningHash(tx, benchChainID); got != common.HexToHash(signingHashVector) {
+		t.Fatalf("SigningHash = %v, want %v", got.Hex(), signingHashVector)
+	}
+	signed, err := tx.SignTx(benchChainID, benchKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, r, s := signed.RawSignatureValues()
+	sig := append(append(common.LeftPadBytes(r.Bytes(), 32), common.LeftPadBytes(s.Bytes(), 32)...),
+		byte(new(big.Int).Sub(v, new(big.Int).Add(new(big.Int).Lsh(benchChainID, 1), big35)).Uint64()))
+	pub, err := crypto.SigToPub(common.HexToHash(signingHashVector).Bytes(), sig)
+	if err != nil || crypto.PubkeyToAddress(*pub) != crypto.PubkeyToAddress(benchKey.PublicKey) {
+		t.Fatalf("signature does not recover the signer, %v", err)
+	}
+}
+
+// signingHashVector is the signing hash of the transaction of
+// TestSigningHashVector on chain 2018.
+const signingHashVector = "0xc35a5ed4911fd446aa8c68c9d3faf2da88487f6efadd1e37d3a8642b0b5a6142"
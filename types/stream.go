@@ -0,0 +1,129 @@
+package types
+
+import (
+	"encoding/hex"
+	"io"
+	"strings"
+)
+
+// WithSharedPayload returns a copy of tx with the input data set to data
+// without copying it, for large deployment payloads. The caller must not
+// modify data while the transaction is in use.
+func (tx *PrivateTransaction) WithSharedPayload(data []byte) *PrivateTransaction {
+	cpy := &PrivateTransaction{data: tx.data, ordering: tx.ordering}
+	cpy.data.Payload = data
+	return cpy
+}
+
+// WriteRLP streams the RLP encoding of tx to w, the same bytes as
+// EncodeRLP. Unlike rlp.Encode, the payload is written to w as it is instead
+// of being copied into an encoding buffer first.
+func (tx *PrivateTransaction) WriteRLP(w io.Writer) error {
+	prefix, suffix, err := tx.segments()
+	if err != nil {
+		return err
+	}
+	return tx.writeRLP(w, prefix, suffix)
+}
+
+func (tx *PrivateTransaction) writeRLP(w io.Writer, prefix, suffix []byte) error {
+	payloadHeader := stringHeader(tx.data.Payload)
+	size := len(prefix) + len(payloadHeader) + len(tx.data.Payload) + len(suffix)
+	for _, b := range [][]byte{listHeader(size), prefix, payloadHeader, tx.data.Payload, suffix} {
+		if len(b) == 0 {
+			continue
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EncodedSize returns the length of the RLP encoding of tx.
+func (tx *PrivateTransaction) EncodedSize() (int, error) {
+	prefix, suffix, err := tx.segments()
+	if err != nil {
+		return 0, err
+	}
+	return tx.encodedSize(prefix, suffix), nil
+}
+
+func (tx *PrivateTransaction) encodedSize(prefix, suffix []byte) int {
+	size := len(prefix) + len(stringHeader(tx.data.Payload)) + len(tx.data.Payload) + len(suffix)
+	return len(listHeader(size)) + size
+}
+
+// EncodeHex returns the 0x prefixed hex of the RLP encoding of tx, as taken
+// by eea_sendRawTransaction, hex encoding while streaming so that the binary
+// encoding is never held in memory.
+func (tx *PrivateTransaction) EncodeHex() (string, error) {
+	prefix, suffix, err := tx.segments()
+	if err != nil {
+		return "", err
+	}
+	w := new(hexWriter)
+	w.sb.Grow(2 + 2*tx.encodedSize(prefix, suffix))
+	w.sb.WriteString("0x")
+	if err := tx.writeRLP(w, prefix, suffix); err != nil {
+		return "", err
+	}
+	return w.sb.String(), nil
+}
+
+// segments returns the encoded items before and after the payload.
+func (tx *PrivateTransaction) segments() (prefix, suffix []byte, err error) {
+	prefix, err = encodeItems(tx.data.AccountNonce, tx.data.Price, tx.data.GasLimit, tx.data.Recipient, tx.data.Amount)
+	if err != nil {
+		return nil, nil, err
+	}
+	suffix, err = encodeItems(tx.data.V, tx.data.R, tx.data.S, tx.data.PrivateFrom, tx.privacy(), tx.data.Restriction)
+	if err != nil {
+		return nil, nil, err
+	}
+	return prefix, suffix, nil
+}
+
+// stringHeader returns the RLP header of the byte string b, empty if b is a
+// single byte encoded as itself.
+func stringHeader(b []byte) []byte {
+	if len(b) == 1 && b[0] < 0x80 {
+		return nil
+	}
+	return header(0x80, len(b))
+}
+
+func listHeader(size int) []byte {
+	return header(0xc0, size)
+}
+
+func header(offset byte, size int) []byte {
+	if size < 56 {
+		return []byte{offset + byte(size)}
+	}
+	var be []byte
+	for n := size; n > 0; n >>= 8 {
+		be = append([]byte{byte(n)}, be...)
+	}
+	return append([]byte{offset + 55 + byte(len(be))}, be...)
+}
+
+// hexWriter hex encodes the bytes written through a small buffer into sb,
+// whose string is then not copied.
+type hexWriter struct {
+	sb  strings.Builder
+	buf [512]byte
+}
+
+func (w *hexWriter) Write(p []byte) (int, error) {
+	for rest := p; len(rest) > 0; {
+		n := len(w.buf) / 2
+		if len(rest) < n {
+			n = len(rest)
+		}
+		hex.Encode(w.buf[:], rest[:n])
+		w.sb.Write(w.buf[:2*n])
+		rest = rest[n:]
+	}
+	return len(p), nil
+}
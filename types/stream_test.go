@@ -0,0 +1,142 @@
+package types
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// deployment is the size of a large contract deployment payload.
+const deployment = 300 << 10
+
+func TestWriteRLPMatchesEncodeRLP(t *testing.T) {
+	for _, size := range []int{0, 1, 55, 56, 255, 256, 65536, deployment} {
+		for _, payload := range [][]byte{bytes.Repeat([]byte{0x7f}, size), bytes.Repeat([]byte{0xff}, size)} {
+			tx, err := groupTx(1, nil).WithSharedPayload(payload).SignTx(benchChainID, benchKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := rlp.EncodeToBytes(tx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			if err := tx.WriteRLP(&buf); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(buf.Bytes(), want) {
+				t.Fatalf("payload %v bytes: WriteRLP differs from EncodeRLP", size)
+			}
+			if n, err := tx.EncodedSize(); err != nil || n != len(want) {
+				t.Fatalf("payload %v bytes: EncodedSize = %v, %v, want %v", size, n, err, len(want))
+			}
+			if h, err := tx.EncodeHex(); err != nil || h != hexutil.Encode(want) {
+				t.Fatalf("payload %v bytes: EncodeHex differs, %v", size, err)
+			}
+		}
+	}
+}
+
+func deploymentTx(b *testing.B) (*PrivateTransaction, []byte) {
+	payload := bytes.Repeat([]byte{0x60}, deployment)
+	tx, err := groupTx(1, nil).WithSharedPayload(payload).SignTx(benchChainID, benchKey)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return tx, payload
+}
+
+// The "before" benchmarks are the encodings used before streaming, the
+// "after" ones those used now.
+
+func BenchmarkNewDeployment(b *testing.B) {
+	_, payload := deploymentTx(b)
+	to := groupTx(0, nil).To()
+	b.Run("before", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			NewPrivateTransaction(1, to, big.NewInt(0), 3000000, big.NewInt(0), payload, make([]byte, 32), make([]byte, 32))
+		}
+	})
+	b.Run("after", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			NewPrivateTransaction(1, to, big.NewInt(0), 3000000, big.NewInt(0), nil, make([]byte, 32), make([]byte, 32)).WithSharedPayload(payload)
+		}
+	})
+}
+
+func BenchmarkEncodeDeployment(b *testing.B) {
+	tx, _ := deploymentTx(b)
+	b.Run("before", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(deployment)
+		for i := 0; i < b.N; i++ {
+			enc, err := rlp.EncodeToBytes(tx)
+			if err != nil {
+				b.Fatal(err)
+			}
+			ioutil.Discard.Write(enc)
+		}
+	})
+	b.Run("after", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(deployment)
+		for i := 0; i < b.N; i++ {
+			if err := tx.WriteRLP(ioutil.Discard); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkEncodeHexDeployment(b *testing.B) {
+	tx, _ := deploymentTx(b)
+	b.Run("before", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(deployment)
+		for i := 0; i < b.N; i++ {
+			enc, err := rlp.EncodeToBytes(tx)
+			if err != nil {
+				b.Fatal(err)
+			}
+			_ = hexutil.Encode(enc)
+		}
+	})
+	b.Run("after", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(deployment)
+		for i := 0; i < b.N; i++ {
+			if _, err := tx.EncodeHex(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkHashDeployment(b *testing.B) {
+	tx, _ := deploymentTx(b)
+	b.Run("before", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(deployment)
+		for i := 0; i < b.N; i++ {
+			enc, err := rlp.EncodeToBytes(signingItems(tx, benchChainID))
+			if err != nil {
+				b.Fatal(err)
+			}
+			crypto.Keccak256Hash(enc)
+		}
+	})
+	b.Run("after", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(deployment)
+		for i := 0; i < b.N; i++ {
+			SigningHash(tx, benchChainID)
+		}
+	})
+}